@@ -9,7 +9,7 @@ import (
 var dockerCmd = &cobra.Command{
 	Use:   "docker",
 	Short: "Manage EPOS environments with Docker Compose.",
-	Long:  "Manage EPOS environments with Docker Compose. Use these commands to deploy, update, list, populate, render, clean, and delete local environments.",
+	Long:  "Manage EPOS environments with Docker Compose. Use these commands to deploy, clone, update, start, stop, list, check the status and logs of, populate, render, clean, back up, restore, export, import, and delete local environments.",
 	Run: func(cmd *cobra.Command, args []string) {
 		_ = cmd.Help()
 	},
@@ -17,13 +17,24 @@ var dockerCmd = &cobra.Command{
 
 func init() {
 	dockerCmd.AddCommand(docker.DeployCmd)
+	dockerCmd.AddCommand(docker.CloneCmd)
 	dockerCmd.AddCommand(docker.DeleteCmd)
+	dockerCmd.AddCommand(docker.StartCmd)
+	dockerCmd.AddCommand(docker.StopCmd)
 	dockerCmd.AddCommand(docker.UpdateCmd)
 	dockerCmd.AddCommand(docker.PopulateCmd)
+	dockerCmd.AddCommand(docker.UnpopulateCmd)
 	dockerCmd.AddCommand(docker.ExportCmd)
 	dockerCmd.AddCommand(docker.GetCmd)
 	dockerCmd.AddCommand(docker.ListCmd)
+	dockerCmd.AddCommand(docker.StatusCmd)
+	dockerCmd.AddCommand(docker.LogsCmd)
+	dockerCmd.AddCommand(docker.BackupCmd)
+	dockerCmd.AddCommand(docker.RestoreCmd)
+	dockerCmd.AddCommand(docker.ExportEnvCmd)
+	dockerCmd.AddCommand(docker.ImportEnvCmd)
 	dockerCmd.AddCommand(docker.CleanCmd)
 	dockerCmd.AddCommand(docker.RenderCmd)
+	dockerCmd.AddCommand(docker.OntologiesCmd)
 	rootCmd.AddCommand(dockerCmd)
 }
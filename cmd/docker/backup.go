@@ -0,0 +1,33 @@
+package docker
+
+import (
+	"os"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+var BackupCmd = &cobra.Command{
+	Use:               "backup <env-name>",
+	Short:             "Back up the data of an environment.",
+	Long:              "Back up the data of an environment. Writes a gzipped tar archive with a dump of the metadata database of a running Docker Compose environment, taken with pg_dump inside the database container, together with its stored configuration and the records of its ingested files. Use 'restore' to load the archive back into this or another environment.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := docker.Backup(docker.BackupOpts{
+			Name:   args[0],
+			Output: backupOutput,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	BackupCmd.Flags().StringVarP(&backupOutput, "output", "o", "", "Path of the backup archive to write")
+	_ = BackupCmd.MarkFlagRequired("output")
+}
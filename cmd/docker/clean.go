@@ -14,7 +14,7 @@ import (
 var CleanCmd = &cobra.Command{
 	Use:               "clean <env-name>",
 	Short:             "Reset an environment's data.",
-	Long:              "Reset an environment's data. Removes database data and ingested file records, then restarts the environment and repopulates base ontologies. Prompts for confirmation unless --force is set.",
+	Long:              "Reset an environment's data. Removes database data and ingested file records, then restarts the environment and repopulates base ontologies from the copies embedded in the binary (or from GitHub with --remote-ontologies). Use --backup-dir to back up the environment to a timestamped archive in a directory first, which 'restore' can load back. Prompts for confirmation unless --force is set.",
 	Args:              cobra.ExactArgs(1),
 	ValidArgsFunction: validArgsFunction,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -34,7 +34,10 @@ var CleanCmd = &cobra.Command{
 		}
 
 		env, err := docker.Clean(docker.CleanOpts{
-			Name: name,
+			Name:             name,
+			RemoteOntologies: remoteOntologies,
+			BackupDir:        backupDir,
+			ReadyTimeout:     readyTimeout,
 		})
 		if err != nil {
 			display.Error("%v", err)
@@ -53,4 +56,7 @@ var CleanCmd = &cobra.Command{
 
 func init() {
 	CleanCmd.Flags().BoolVarP(&cleanForce, "force", "f", false, "Skip the confirmation prompt")
+	CleanCmd.Flags().BoolVar(&remoteOntologies, "remote-ontologies", false, "Have the ingestor fetch the base ontologies from GitHub instead of uploading the embedded copies")
+	CleanCmd.Flags().DurationVar(&readyTimeout, "ready-timeout", docker.DefaultReadyTimeout, "How long to wait for the services to become ready before initializing the ontologies")
+	CleanCmd.Flags().StringVar(&backupDir, "backup-dir", "", "Back up the environment to a timestamped archive in this directory before cleaning it")
 }
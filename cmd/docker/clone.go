@@ -0,0 +1,39 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+var CloneCmd = &cobra.Command{
+	Use:               "clone <source-env> <new-env>",
+	Short:             "Create a copy of an environment including its data.",
+	Long:              "Create a copy of an environment including its data. Deploys a new Docker Compose environment with the configuration of an existing one, using the default ports or free ones when they are in use, a copy of its metadata database volume and its ingested file records. The metadata database of the source environment is stopped for the time its volume is copied. Useful to try a risky change on a throwaway copy of a populated environment.",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[1]
+
+		env, err := docker.Clone(docker.CloneOpts{
+			Source: args[0],
+			Name:   name,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+
+		urls, err := env.BuildEnvURLs()
+		if err != nil {
+			display.Error("failed to build environment URLs: %v", err)
+			os.Exit(1)
+		}
+
+		display.URLs(urls.GUIURL, urls.APIURL, fmt.Sprintf("epos-opensource docker clone %s %s", args[0], name), urls.BackofficeURL)
+	},
+}
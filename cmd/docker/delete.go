@@ -15,7 +15,7 @@ import (
 var DeleteCmd = &cobra.Command{
 	Use:               "delete <env-name>...",
 	Short:             "Delete one or more environments.",
-	Long:              "Delete one or more environments. Removes the Docker Compose environment, including its containers, volumes, and tracked metadata. Prompts for confirmation unless --force is set.",
+	Long:              "Delete one or more environments. Removes the Docker Compose environment, including its containers, volumes, and tracked metadata. Use --backup-dir to back up every environment to a timestamped archive in a directory first, which 'restore' can load back into a new environment. Prompts for confirmation unless --force is set.",
 	Args:              cobra.MinimumNArgs(1),
 	ValidArgsFunction: validArgsFunction,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -37,7 +37,8 @@ var DeleteCmd = &cobra.Command{
 		}
 
 		err := docker.Delete(docker.DeleteOpts{
-			Name: name,
+			Name:      name,
+			BackupDir: backupDir,
 		})
 		if err != nil {
 			display.Error("%v", err)
@@ -48,4 +49,5 @@ var DeleteCmd = &cobra.Command{
 
 func init() {
 	DeleteCmd.Flags().BoolVarP(&deleteForce, "force", "f", false, "Skip the confirmation prompt")
+	DeleteCmd.Flags().StringVar(&backupDir, "backup-dir", "", "Back up every environment to a timestamped archive in this directory before deleting it")
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/EPOS-ERIC/epos-opensource/display"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/docker/config"
@@ -14,7 +15,7 @@ import (
 var DeployCmd = &cobra.Command{
 	Use:   "deploy <env-name>",
 	Short: "Deploy a new environment.",
-	Long:  "Deploy a new environment. Starts a new local Docker Compose environment with the given name. Uses the default configuration unless --config is set.",
+	Long:  "Deploy a new environment. Starts a new local Docker Compose environment with the given name. Uses the default configuration unless --config is set. The base ontologies are uploaded from the copies embedded in the binary; use --remote-ontologies to have the ingestor fetch them from GitHub instead. When the configuration sets seed_manifest, the new environment is populated from that dataset manifest, as with populate --manifest; use --no-seed to skip it. Before the ontologies are initialized, the command waits for the gateway, ingestor, metadata database and RabbitMQ to pass their healthcheck and for the gateway and the ingestor to answer; when they are not ready within --ready-timeout, it reports the services that never became healthy.",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
@@ -33,9 +34,16 @@ var DeployCmd = &cobra.Command{
 
 		cfg.Name = name
 
-		env, err := docker.Deploy(docker.DeployOpts{
-			PullImages: pullImages,
-			Config:     cfg,
+		ctx, stop := common.NotifyInterrupt(cmd.Context())
+		defer stop()
+
+		env, err := docker.Deploy(ctx, docker.DeployOpts{
+			PullImages:       pullImages,
+			RemoteOntologies: remoteOntologies,
+			SkipSeed:         noSeed,
+			SeedParallel:     seedParallel,
+			ReadyTimeout:     readyTimeout,
+			Config:           cfg,
 		})
 		if err != nil {
 			display.Error("%v", err)
@@ -55,4 +63,8 @@ var DeployCmd = &cobra.Command{
 func init() {
 	DeployCmd.Flags().BoolVarP(&pullImages, "update-images", "u", false, "Pull Docker images before starting")
 	DeployCmd.Flags().StringVar(&configFilePath, "config", "", "Path to YAML configuration file")
+	DeployCmd.Flags().BoolVar(&remoteOntologies, "remote-ontologies", false, "Have the ingestor fetch the base ontologies from GitHub instead of uploading the embedded copies")
+	DeployCmd.Flags().DurationVar(&readyTimeout, "ready-timeout", docker.DefaultReadyTimeout, "How long to wait for the services to become ready before initializing the ontologies")
+	DeployCmd.Flags().BoolVar(&noSeed, "no-seed", false, "Do not populate the environment from the seed manifest of the configuration")
+	DeployCmd.Flags().IntVar(&seedParallel, "seed-parallel", 1, "Parallel TTL uploads when populating the environment from its seed manifest (1-20)")
 }
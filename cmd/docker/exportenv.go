@@ -0,0 +1,33 @@
+package docker
+
+import (
+	"os"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+var ExportEnvCmd = &cobra.Command{
+	Use:               "export-env <env-name>",
+	Short:             "Export an environment to a self-contained bundle.",
+	Long:              "Export an environment to a self-contained bundle. Writes a gzipped tar archive with the stored configuration of a running Docker Compose environment, a dump of its metadata database, the records of its ingested files and the references and digests of its images. Use 'import-env' to recreate the environment from the bundle, for example on another machine. The bundle is a backup archive, so 'restore' accepts it too.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := docker.Backup(docker.BackupOpts{
+			Name:   args[0],
+			Output: exportEnvOutput,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	ExportEnvCmd.Flags().StringVarP(&exportEnvOutput, "output", "o", "", "Path of the bundle to write")
+	_ = ExportEnvCmd.MarkFlagRequired("output")
+}
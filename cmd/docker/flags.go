@@ -1,10 +1,52 @@
 package docker
 
+import "time"
+
 var (
 	pullImages       bool
 	configFilePath   string
 	parallel         int
 	populateExamples bool
+	remoteExamples   bool
+	remoteOntologies bool
+	ontologyType     string
+	ontologyURL      string
+	incremental      bool
+	dryRun           bool
+	retries          int
+	retryBackoff     time.Duration
+	retryMaxBackoff  time.Duration
+	retryOn          []int
+	reportFormat     string
+	reportFile       string
+	ingestionType    string
+	ingestionModel   string
+	ingestionMapping string
+	includePatterns  []string
+	excludePatterns  []string
+	failFast         bool
+	keepGoing        bool
+	datasetManifest  string
+	compress         bool
+	noSeed           bool
+	seedParallel     int
+	watch            bool
+	watchDebounce    time.Duration
 	cleanForce       bool
+	unpopulateDryRun bool
+	unpopulateForce  bool
+	unpopulateToken  string
 	deleteForce      bool
+	backupDir        string
+	readyTimeout     time.Duration
+	backupOutput     string
+	restoreConfig    bool
+	exportEnvOutput  string
+	importEnvName    string
+	importKeepTags   bool
+	logsFollow       bool
+	logsSince        string
+	logsTail         string
+	logsGrep         string
+	logsInvertMatch  bool
 )
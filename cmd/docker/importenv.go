@@ -0,0 +1,42 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+var ImportEnvCmd = &cobra.Command{
+	Use:   "import-env <bundle>",
+	Short: "Recreate an environment from a bundle written by export-env.",
+	Long:  "Recreate an environment from a bundle written by export-env. Deploys a new Docker Compose environment with the configuration of the bundle, using the default ports or free ones when they are in use, and restores the metadata database and ingested file records of the bundle into it. The images are pinned to the digests recorded in the bundle, so the environment runs the same images it was exported with; use --keep-tags to deploy the image tags of the configuration instead. The environment keeps the name it was exported with unless --name is given. When restoring the data fails the new environment is kept; remove it with 'delete' before importing again.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := docker.ImportEnv(docker.ImportEnvOpts{
+			Input:    args[0],
+			Name:     importEnvName,
+			KeepTags: importKeepTags,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+
+		urls, err := env.BuildEnvURLs()
+		if err != nil {
+			display.Error("failed to build environment URLs: %v", err)
+			os.Exit(1)
+		}
+
+		display.URLs(urls.GUIURL, urls.APIURL, fmt.Sprintf("epos-opensource docker import-env %s", args[0]), urls.BackofficeURL)
+	},
+}
+
+func init() {
+	ImportEnvCmd.Flags().StringVar(&importEnvName, "name", "", "Name of the new environment (default: the name of the exported environment)")
+	ImportEnvCmd.Flags().BoolVar(&importKeepTags, "keep-tags", false, "Deploy the image tags of the configuration instead of the image digests recorded in the bundle")
+}
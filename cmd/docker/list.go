@@ -13,7 +13,7 @@ import (
 var ListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List EPOS environments.",
-	Long:  "List EPOS environments. Lists local Docker Compose environments and shows their GUI, API, and backoffice URLs.",
+	Long:  "List EPOS environments. Lists local Docker Compose environments and shows whether they are running, stopped or only partially running, and their GUI, API, and backoffice URLs.",
 	Run: func(cmd *cobra.Command, args []string) {
 		envs, err := docker.List()
 		if err != nil {
@@ -21,6 +21,11 @@ var ListCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		states, err := docker.States()
+		if err != nil {
+			display.Warn("Could not get the state of the environments: %v", err)
+		}
+
 		rows := make([][]any, len(envs))
 		for i, dockerEnv := range envs {
 			urls, err := dockerEnv.BuildEnvURLs()
@@ -46,10 +51,18 @@ var ListCmd = &cobra.Command{
 				}
 			}
 
-			rows[i] = []any{dockerEnv.Name, urls.GUIURL, apiURL, backofficeURL}
+			state := "unknown"
+			if states != nil {
+				state = string(docker.EnvStopped)
+				if s, ok := states[dockerEnv.Name]; ok {
+					state = string(s)
+				}
+			}
+
+			rows[i] = []any{dockerEnv.Name, state, urls.GUIURL, apiURL, backofficeURL}
 		}
 
-		headers := []string{"Name", "GUI URL", "API URL", "Backoffice URL"}
+		headers := []string{"Name", "State", "GUI URL", "API URL", "Backoffice URL"}
 		display.InfraList(rows, headers, "Installed Docker environments")
 	},
 }
@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"os"
+
+	"github.com/EPOS-ERIC/epos-opensource/cmd/internal/completion"
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+var LogsCmd = &cobra.Command{
+	Use:   "logs <env-name> [service...]",
+	Short: "Show the logs of the services of an environment.",
+	Long:  "Show the logs of the services of an environment. Prints the logs of the given services of a Docker Compose environment, or of every service when none is given, each line prefixed by the container it comes from. Use --follow to keep streaming new lines until Ctrl-C, --since to only show the lines logged since a timestamp or a relative time (10m, 2h), and --tail to only show the last lines of every service. Use --grep with a regular expression to only show the matching lines, and --invert-match to show the lines that do not match instead.",
+	Args:  cobra.MinimumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.SharedValuesCompletion(toComplete, func() ([]string, error) {
+				envs, err := docker.List()
+				if err != nil {
+					return nil, err
+				}
+
+				names := make([]string, len(envs))
+				for i, d := range envs {
+					names[i] = d.Name
+				}
+				return names, nil
+			})
+		}
+
+		return completion.SharedValuesCompletion(toComplete, func() ([]string, error) {
+			return docker.Services(args[0])
+		})
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := common.NotifyInterrupt(cmd.Context())
+		defer stop()
+
+		err := docker.Logs(ctx, docker.LogsOpts{
+			Name:        args[0],
+			Services:    args[1:],
+			Follow:      logsFollow,
+			Since:       logsSince,
+			Tail:        logsTail,
+			Grep:        logsGrep,
+			InvertMatch: logsInvertMatch,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	LogsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep streaming new log lines until Ctrl-C")
+	LogsCmd.Flags().StringVar(&logsSince, "since", "", "Only show the lines logged since a timestamp (2026-01-02T15:04:05) or a relative time (10m, 2h)")
+	LogsCmd.Flags().StringVarP(&logsTail, "tail", "n", "all", "Number of lines to show from the end of the logs of every service, or 'all'")
+	LogsCmd.Flags().StringVar(&logsGrep, "grep", "", "Only show the lines matching this regular expression")
+	LogsCmd.Flags().BoolVarP(&logsInvertMatch, "invert-match", "v", false, "Show the lines that do not match --grep instead")
+}
@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+var OntologiesCmd = &cobra.Command{
+	Use:   "ontologies",
+	Short: "Manage the ontologies of an environment.",
+	Long:  "Manage the ontologies of an environment. The ontologies registered in the ingestor are part of the environment config (the ontologies list); entries without a url use the copies embedded in the CLI. Use list to show them, add to register a new one and reload to register all of them again.",
+}
+
+var ontologiesListCmd = &cobra.Command{
+	Use:               "list <env-name>",
+	Short:             "List the ontologies of an environment.",
+	Long:              "List the ontologies of an environment. Shows the name, type and source of every ontology in the environment config.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		ontologies, err := docker.ListOntologies(name)
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+
+		rows := make([][]any, len(ontologies))
+		for i, ont := range ontologies {
+			source := ont.URL
+			if ont.Embedded() {
+				source = "embedded"
+			}
+			rows[i] = []any{ont.Name, ont.Type, source}
+		}
+
+		display.InfraList(rows, []string{"Name", "Type", "Source"}, fmt.Sprintf("Ontologies of environment %s", name))
+	},
+}
+
+var ontologiesAddCmd = &cobra.Command{
+	Use:               "add <env-name> <ontology-name>",
+	Short:             "Register a new ontology in an environment.",
+	Long:              "Register a new ontology in an environment. The ingestor fetches the ontology from --url, which can point at your own shapes or at a fork or tag of the default ontologies, and the ontology is added to the environment config so that it is registered again when the environment is cleaned or recreated. Without --url the name must be one of the ontologies embedded in the CLI. Use --type MAPPING for mappings.",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		_, err := docker.AddOntology(docker.AddOntologyOpts{
+			Name: name,
+			Ontology: common.Ontology{
+				Name: args[1],
+				Type: ontologyType,
+				URL:  ontologyURL,
+			},
+			RemoteOntologies: remoteOntologies,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var ontologiesReloadCmd = &cobra.Command{
+	Use:               "reload <env-name>",
+	Short:             "Register the ontologies of an environment again.",
+	Long:              "Register the ontologies of an environment again. Registers every ontology in the environment config in the ingestor, e.g. after the file behind a url changed. Embedded ontologies are uploaded from the copies in the CLI unless --remote-ontologies is set.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		_, err := docker.ReloadOntologies(docker.ReloadOntologiesOpts{
+			Name:             name,
+			RemoteOntologies: remoteOntologies,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	ontologiesAddCmd.Flags().StringVar(&ontologyType, "type", common.OntologyTypeBase, "Type of the ontology: BASE or MAPPING")
+	ontologiesAddCmd.Flags().StringVar(&ontologyURL, "url", "", "URL the ingestor fetches the ontology from")
+	ontologiesAddCmd.Flags().BoolVar(&remoteOntologies, "remote-ontologies", false, "Have the ingestor fetch an embedded ontology from GitHub instead of uploading the embedded copy")
+	ontologiesReloadCmd.Flags().BoolVar(&remoteOntologies, "remote-ontologies", false, "Have the ingestor fetch the embedded ontologies from GitHub instead of uploading the embedded copies")
+
+	OntologiesCmd.AddCommand(ontologiesListCmd)
+	OntologiesCmd.AddCommand(ontologiesAddCmd)
+	OntologiesCmd.AddCommand(ontologiesReloadCmd)
+}
@@ -3,28 +3,42 @@ package docker
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/EPOS-ERIC/epos-opensource/display"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
 	"github.com/spf13/cobra"
 )
 
 var PopulateCmd = &cobra.Command{
-	Use:               "populate <env-name> [ttl-paths...]",
-	Short:             "Load TTL data into an environment.",
-	Long:              "Load TTL data into an environment. Imports .ttl files from the given files or directories, or loads bundled example data with --example. Pass at least one TTL path unless --example is set.",
+	Use:   "populate <env-name> [ttl-paths...]",
+	Short: "Load TTL data into an environment.",
+	Long:  "Load TTL data into an environment. Imports RDF files (Turtle, N-Triples, JSON-LD, RDF/XML) and zip or tar.gz archives from the given files or directories, or loads bundled example data with --example.",
+	Example: `  # Ingest a directory, only uploading the files changed since the last populate
+  epos-opensource docker populate my-env ./metadata --incremental
+
+  # Check the syntax of a delivery without uploading it
+  epos-opensource docker populate my-env ./delivery.zip --dry-run
+
+  # Ingest the stations of several directories, leaving out invalid files, and write a JUnit report
+  epos-opensource docker populate my-env ./a ./b --include 'stations/**/*.ttl' --keep-going --report junit --report-file report.xml
+
+  # Repeat the populate described by a dataset manifest
+  epos-opensource docker populate my-env --manifest datasets.yaml`,
 	ValidArgsFunction: validArgsFunction,
 	Args: func(cmd *cobra.Command, args []string) error {
-		if populateExamples {
+		if populateExamples || datasetManifest != "" {
 			if len(args) < 1 {
-				display.Error("requires environment name when using --example")
-				return fmt.Errorf("requires environment name when using --example")
+				display.Error("requires environment name when using --example or --manifest")
+				return fmt.Errorf("requires environment name when using --example or --manifest")
 			}
 			return nil
 		}
 		if len(args) < 2 {
-			display.Error("requires environment name and at least one TTL path (or use --example flag)")
-			return fmt.Errorf("requires environment name and at least one TTL path (or use --example flag)")
+			display.Error("requires environment name and at least one TTL path (or use --example or --manifest flag)")
+			return fmt.Errorf("requires environment name and at least one TTL path (or use --example or --manifest flag)")
 		}
 		return nil
 	},
@@ -32,17 +46,49 @@ var PopulateCmd = &cobra.Command{
 		name := args[0]
 		ttlPaths := args[1:]
 
-		env, err := docker.Populate(docker.PopulateOpts{
+		ctx, stop := common.NotifyInterrupt(cmd.Context())
+		defer stop()
+
+		env, err := docker.Populate(ctx, docker.PopulateOpts{
 			TTLDirs:          ttlPaths,
 			Name:             name,
 			Parallel:         parallel,
 			PopulateExamples: populateExamples,
+			RemoteExamples:   remoteExamples,
+			Incremental:      incremental,
+			DryRun:           dryRun,
+			Retry: common.RetryPolicy{
+				MaxRetries:           retries,
+				Backoff:              retryBackoff,
+				MaxBackoff:           retryMaxBackoff,
+				RetryableStatusCodes: retryOn,
+			},
+			ReportFile:   reportFile,
+			ReportFormat: common.ReportFormat(reportFormat),
+			Ingestion: common.IngestionSettings{
+				Type:    ingestionType,
+				Model:   ingestionModel,
+				Mapping: ingestionMapping,
+			},
+			Filter: common.PathFilter{
+				Include: includePatterns,
+				Exclude: excludePatterns,
+			},
+			KeepGoing:     keepGoing,
+			Watch:         watch,
+			WatchDebounce: watchDebounce,
+			Manifest:      datasetManifest,
+			Compress:      compress,
 		})
 		if err != nil {
 			display.Error("%v", err)
 			os.Exit(1)
 		}
 
+		if dryRun || watch {
+			return
+		}
+
 		urls, err := env.BuildEnvURLs()
 		if err != nil {
 			display.Error("failed to build environment URLs: %v", err)
@@ -56,4 +102,29 @@ var PopulateCmd = &cobra.Command{
 func init() {
 	PopulateCmd.Flags().IntVarP(&parallel, "parallel", "p", 1, "Parallel TTL uploads (1-20)")
 	PopulateCmd.Flags().BoolVar(&populateExamples, "example", false, "Load bundled example data")
+	PopulateCmd.Flags().BoolVar(&remoteExamples, "remote-examples", false, "With --example, have the gateway fetch the examples from GitHub instead of uploading the embedded copies")
+	PopulateCmd.Flags().BoolVar(&incremental, "incremental", false, "Only upload files that are new or changed since the last populate")
+	PopulateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only check the syntax of the files without uploading them")
+	PopulateCmd.Flags().IntVar(&retries, "retries", 3, "Number of times an upload failing with a network error or a --retry-on status is retried, with exponential backoff (0 disables retries)")
+	PopulateCmd.Flags().DurationVar(&retryBackoff, "retry-backoff", 2*time.Second, "Delay before the first retry, doubled on every further retry")
+	PopulateCmd.Flags().DurationVar(&retryMaxBackoff, "retry-max-backoff", 30*time.Second, "Maximum delay between two retries")
+	PopulateCmd.Flags().IntSliceVar(&retryOn, "retry-on", common.DefaultRetryableStatusCodes, "HTTP status codes that trigger a retry (network errors are always retried)")
+	PopulateCmd.Flags().StringVar(&reportFormat, "report", "", "Write a report of the outcome of every file: json or junit (requires --report-file)")
+	PopulateCmd.Flags().StringVar(&reportFile, "report-file", "", "Path of the report written with --report")
+	PopulateCmd.MarkFlagsRequiredTogether("report", "report-file")
+	PopulateCmd.Flags().StringVar(&ingestionModel, "model", common.DefaultIngestionModel, "Metadata model of the files, e.g. "+strings.Join(common.IngestionModels, " or ")+"; a .eposingest.yaml file in a directory overrides it for the files below it")
+	PopulateCmd.Flags().StringVar(&ingestionMapping, "mapping", common.DefaultIngestionMapping, "Mapping applied to the files by the ingestor")
+	PopulateCmd.Flags().StringVar(&ingestionType, "ingestion-type", common.DefaultIngestionType, "Ingestion type passed to the ingestor")
+	PopulateCmd.Flags().StringSliceVar(&includePatterns, "include", nil, "Only ingest the files of directories matching these glob patterns, e.g. 'stations/**/*.ttl'")
+	PopulateCmd.Flags().StringSliceVar(&excludePatterns, "exclude", nil, "Skip the files and directories matching these glob patterns, e.g. 'drafts/' or '*.draft.ttl', as well as the patterns in a .eposignore file in the root of a directory")
+	PopulateCmd.Flags().BoolVar(&failFast, "fail-fast", false, "Stop at the first file that fails to upload, aborting the other uploads, and upload nothing from a directory with invalid files (default)")
+	PopulateCmd.Flags().BoolVar(&keepGoing, "keep-going", false, "Ingest every path and valid file even when some fail, then summarize the failures")
+	PopulateCmd.MarkFlagsMutuallyExclusive("fail-fast", "keep-going")
+	PopulateCmd.Flags().BoolVar(&watch, "watch", false, "Keep running and re-ingest files as they change")
+	PopulateCmd.Flags().DurationVar(&watchDebounce, "watch-debounce", common.DefaultWatchDebounce, "Quiet period after the last change before changed files are re-ingested")
+	PopulateCmd.MarkFlagsMutuallyExclusive("watch", "dry-run")
+	PopulateCmd.MarkFlagsMutuallyExclusive("watch", "report")
+	PopulateCmd.Flags().StringVar(&datasetManifest, "manifest", "", "Dataset manifest (YAML listing named datasets with their paths, patterns, model, mapping and order) to populate after the given paths")
+	PopulateCmd.MarkFlagsMutuallyExclusive("watch", "manifest")
+	PopulateCmd.Flags().BoolVar(&compress, "compress", false, "Compress uploads with gzip, falling back to uncompressed uploads when the gateway does not accept them")
 }
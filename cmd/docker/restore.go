@@ -0,0 +1,44 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+var RestoreCmd = &cobra.Command{
+	Use:               "restore <env-name> <backup-file>",
+	Short:             "Restore the data of an environment from a backup.",
+	Long:              "Restore the data of an environment from a backup. Replaces the metadata database of an existing Docker Compose environment with the dump of an archive written by 'backup', and its ingested file records with the ones of the archive. The dump is loaded into a separate database that replaces the current one only once fully loaded, so a failed restore keeps the current data. The services using the database are stopped while it is restored and started again once done. The backup can come from another environment; use --config to also update the environment with the configuration stored in the backup.",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		env, err := docker.Restore(docker.RestoreOpts{
+			Name:          name,
+			Input:         args[1],
+			RestoreConfig: restoreConfig,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+
+		urls, err := env.BuildEnvURLs()
+		if err != nil {
+			display.Error("failed to build environment URLs: %v", err)
+			os.Exit(1)
+		}
+
+		display.URLs(urls.GUIURL, urls.APIURL, fmt.Sprintf("epos-opensource docker restore %s", name), urls.BackofficeURL)
+	},
+}
+
+func init() {
+	RestoreCmd.Flags().BoolVar(&restoreConfig, "config", false, "Update the environment with the configuration stored in the backup before restoring its data")
+}
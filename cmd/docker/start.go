@@ -0,0 +1,27 @@
+package docker
+
+import (
+	"os"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+var StartCmd = &cobra.Command{
+	Use:               "start <env-name>...",
+	Short:             "Start one or more stopped environments.",
+	Long:              "Start one or more stopped environments. Starts the containers of a Docker Compose environment stopped with 'stop', from its stored configuration. Containers removed in the meantime are created again from the local images; volumes and ingested metadata are kept.",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := docker.Start(docker.StartOpts{
+			Name: args,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
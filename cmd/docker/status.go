@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+var StatusCmd = &cobra.Command{
+	Use:               "status <env-name>",
+	Short:             "Show the status of the services of an environment.",
+	Long:              "Show the status of the services of an environment. Lists the container of every service of a Docker Compose environment with its state, healthcheck status, restart count, uptime and image digest. Exits with a non-zero code when the environment is stopped or any service is not running, is unhealthy or is still starting.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		services, err := docker.Status(args[0])
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+
+		if len(services) == 0 {
+			display.Error("Environment %s has no containers, start it with 'start'", args[0])
+			os.Exit(1)
+		}
+
+		now := time.Now()
+		unhealthy := 0
+		rows := make([][]any, len(services))
+		for i, s := range services {
+			health := s.Health
+			if health == "" {
+				health = "-"
+			}
+
+			uptime := "-"
+			if d := s.Uptime(now); d > 0 {
+				uptime = d.String()
+			}
+
+			if !s.Healthy() {
+				unhealthy++
+			}
+
+			rows[i] = []any{s.Service, s.State, health, strconv.Itoa(s.RestartCount), uptime, s.Image, s.ShortImageDigest()}
+		}
+
+		headers := []string{"Service", "State", "Health", "Restarts", "Uptime", "Image", "Digest"}
+		display.InfraList(rows, headers, "Services of environment "+args[0])
+
+		if unhealthy > 0 {
+			display.Error("%d of %d services are not healthy", unhealthy, len(services))
+			os.Exit(1)
+		}
+	},
+}
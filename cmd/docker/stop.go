@@ -0,0 +1,27 @@
+package docker
+
+import (
+	"os"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+var StopCmd = &cobra.Command{
+	Use:               "stop <env-name>...",
+	Short:             "Stop one or more environments.",
+	Long:              "Stop one or more environments. Stops the containers of the Docker Compose environment to free memory and CPU, keeping its containers, volumes, ingested metadata and stored configuration. Use 'start' to start it again.",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := docker.Stop(docker.StopOpts{
+			Name: args,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
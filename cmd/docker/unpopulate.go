@@ -0,0 +1,63 @@
+package docker
+
+import (
+	"os"
+
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+
+	"github.com/spf13/cobra"
+)
+
+// accessTokenEnv is the environment variable the backoffice access token is read from when --token is not set.
+const accessTokenEnv = "EPOS_ACCESS_TOKEN"
+
+var UnpopulateCmd = &cobra.Command{
+	Use:               "unpopulate <env-name> <paths...>",
+	Short:             "Remove ingested metadata from an environment.",
+	Long:              "Remove ingested metadata from an environment. Takes files, or directories containing files, that were ingested with populate, determines the entities (data products, distributions, web services, organizations...) they define and deletes them through the backoffice API, then stops tracking the files. Entities also defined by another ingested file or an ingested example are kept. The files must still exist, unchanged since they were ingested, and the environment must have the backoffice enabled; when its API is behind auth, pass the access token of a backoffice administrator with --token or EPOS_ACCESS_TOKEN. Use --dry-run to only list the entities that would be removed. Prompts for confirmation unless --force or --dry-run is set.",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: validArgsFunction,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		if !unpopulateForce && !unpopulateDryRun {
+			display.Warn("This will permanently delete the metadata ingested from the given paths from environment '%s'.", name)
+			confirmed, err := common.Confirm("Are you sure you want to continue? (y/n):")
+			if err != nil {
+				display.Error("Failed to read confirmation: %v", err)
+				os.Exit(1)
+			}
+			if !confirmed {
+				display.Info("Unpopulate operation cancelled.")
+				return
+			}
+		}
+
+		ctx, stop := common.NotifyInterrupt(cmd.Context())
+		defer stop()
+
+		token := unpopulateToken
+		if token == "" {
+			token = os.Getenv(accessTokenEnv)
+		}
+
+		_, err := docker.Unpopulate(ctx, docker.UnpopulateOpts{
+			Name:   name,
+			Paths:  args[1:],
+			DryRun: unpopulateDryRun,
+			Token:  token,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	UnpopulateCmd.Flags().BoolVar(&unpopulateDryRun, "dry-run", false, "Only list the entities that would be removed")
+	UnpopulateCmd.Flags().BoolVarP(&unpopulateForce, "force", "f", false, "Skip the confirmation prompt")
+	UnpopulateCmd.Flags().StringVar(&unpopulateToken, "token", "", "Access token of a backoffice administrator, required when the backoffice API is behind auth (default $"+accessTokenEnv+")")
+}
@@ -19,7 +19,7 @@ var (
 var UpdateCmd = &cobra.Command{
 	Use:   "update <env-name>",
 	Short: "Update an existing environment.",
-	Long:  "Update an existing environment. Updates the deployed environment using the current applied configuration or a file passed with --config. Use --reset to start from the default configuration, --force to recreate containers, or --update-images to pull images before starting.",
+	Long:  "Update an existing environment. Updates the deployed environment using the current applied configuration or a file passed with --config. Use --reset to start from the default configuration, --force to recreate containers, or --update-images to pull images before starting. With --force the base ontologies are repopulated from the copies embedded in the binary, or from GitHub with --remote-ontologies. Since --force removes the database, use --backup-dir with it to back up the environment to a timestamped archive in a directory first, which 'restore' can load back.",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
@@ -35,11 +35,14 @@ var UpdateCmd = &cobra.Command{
 		}
 
 		env, err := docker.Update(docker.UpdateOpts{
-			PullImages: pullImages,
-			Force:      force,
-			Reset:      reset,
-			OldEnvName: name,
-			NewConfig:  cfg,
+			PullImages:       pullImages,
+			Force:            force,
+			Reset:            reset,
+			RemoteOntologies: remoteOntologies,
+			BackupDir:        backupDir,
+			ReadyTimeout:     readyTimeout,
+			OldEnvName:       name,
+			NewConfig:        cfg,
 		})
 		if err != nil {
 			display.Error("%v", err)
@@ -61,4 +64,7 @@ func init() {
 	UpdateCmd.Flags().BoolVarP(&pullImages, "update-images", "u", false, "Pull Docker images before starting")
 	UpdateCmd.Flags().BoolVar(&reset, "reset", false, "Use the embedded default config")
 	UpdateCmd.Flags().StringVar(&configFilePath, "config", "", "Path to YAML configuration file")
+	UpdateCmd.Flags().BoolVar(&remoteOntologies, "remote-ontologies", false, "With --force, have the ingestor fetch the base ontologies from GitHub instead of uploading the embedded copies")
+	UpdateCmd.Flags().DurationVar(&readyTimeout, "ready-timeout", docker.DefaultReadyTimeout, "With --force, how long to wait for the services to become ready before initializing the ontologies")
+	UpdateCmd.Flags().StringVar(&backupDir, "backup-dir", "", "With --force, back up the environment to a timestamped archive in this directory before removing its containers and volumes")
 }
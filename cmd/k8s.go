@@ -25,5 +25,6 @@ func init() {
 	k8sCmd.AddCommand(k8s.ListCmd)
 	k8sCmd.AddCommand(k8s.CleanCmd)
 	k8sCmd.AddCommand(k8s.RenderCmd)
+	k8sCmd.AddCommand(k8s.OntologiesCmd)
 	rootCmd.AddCommand(k8sCmd)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/EPOS-ERIC/epos-opensource/display"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/k8s"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/k8s/config"
@@ -15,7 +16,7 @@ import (
 var DeployCmd = &cobra.Command{
 	Use:   "deploy <env-name>",
 	Short: "Deploy a new environment.",
-	Long:  "Deploy a new environment. Creates a new namespace and deploys the EPOS services to it. Uses the default configuration unless --config is set.",
+	Long:  "Deploy a new environment. Creates a new namespace and deploys the EPOS services to it. Uses the default configuration unless --config is set. When the configuration sets seed_manifest, the new environment is populated from that dataset manifest, as with populate --manifest; use --no-seed to skip it.",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
@@ -32,10 +33,15 @@ var DeployCmd = &cobra.Command{
 
 		cfg.Name = name
 
-		env, err := k8s.Deploy(k8s.DeployOpts{
-			Context: context,
-			Timeout: timeout,
-			Config:  cfg,
+		ctx, stop := common.NotifyInterrupt(cmd.Context())
+		defer stop()
+
+		env, err := k8s.Deploy(ctx, k8s.DeployOpts{
+			Context:      context,
+			Timeout:      timeout,
+			Config:       cfg,
+			SkipSeed:     noSeed,
+			SeedParallel: seedParallel,
 		})
 		if err != nil {
 			display.Error("%v", err)
@@ -56,4 +62,6 @@ func init() {
 	addContextFlag(DeployCmd)
 	DeployCmd.Flags().StringVar(&configFilePath, "config", "", "Path to YAML configuration file")
 	DeployCmd.Flags().DurationVar(&timeout, "timeout", 0, "Operation timeout (default: 5m)")
+	DeployCmd.Flags().BoolVar(&noSeed, "no-seed", false, "Do not populate the environment from the seed manifest of the configuration")
+	DeployCmd.Flags().IntVar(&seedParallel, "seed-parallel", 1, "Parallel TTL uploads when populating the environment from its seed manifest (1-20)")
 }
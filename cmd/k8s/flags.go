@@ -8,6 +8,29 @@ var (
 	timeout          time.Duration
 	parallel         int
 	populateExamples bool
+	remoteExamples   bool
+	remoteOntologies bool
+	ontologyType     string
+	ontologyURL      string
+	incremental      bool
+	dryRun           bool
+	retries          int
+	retryBackoff     time.Duration
+	retryMaxBackoff  time.Duration
+	retryOn          []int
+	reportFormat     string
+	reportFile       string
+	ingestionType    string
+	ingestionModel   string
+	ingestionMapping string
+	includePatterns  []string
+	excludePatterns  []string
+	failFast         bool
+	keepGoing        bool
+	datasetManifest  string
+	compress         bool
+	noSeed           bool
+	seedParallel     int
 	deleteForce      bool
 	cleanForce       bool
 )
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateShapes []string
+	validateFormat string
+)
+
+// validateMetadataCmd represents the validate-metadata command
+var validateMetadataCmd = &cobra.Command{
+	Use:   "validate-metadata <ttl-paths...>",
+	Short: "Validate RDF metadata against SHACL shapes offline.",
+	Long:  "Validate RDF metadata against SHACL shapes offline. Loads the shapes (the upstream EPOS-DCAT-AP V1 shapes the ingestor registers, embedded in the binary, by default, or the shape graphs passed with --shapes), validates every RDF file (Turtle, N-Triples, JSON-LD or RDF/XML) in the given files or directories and prints a report per focus node. Exits with a non-zero status when any violation is found.",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if validateFormat != "text" && validateFormat != "json" {
+			display.Error("invalid format %q, must be one of: text, json", validateFormat)
+			os.Exit(1)
+		}
+
+		report, err := common.ValidateMetadata(common.ValidateMetadataOpts{
+			Paths:  args,
+			Shapes: validateShapes,
+		})
+		if err != nil {
+			display.Error("%v", err)
+			os.Exit(1)
+		}
+
+		if validateFormat == "json" {
+			out, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				display.Error("failed to encode report: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(out))
+		} else if err := report.WriteText(os.Stdout); err != nil {
+			display.Error("failed to write report: %v", err)
+			os.Exit(1)
+		}
+
+		if report.Violations > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	validateMetadataCmd.Flags().StringSliceVar(&validateShapes, "shapes", nil, fmt.Sprintf("Shape graphs to validate against: embedded names (%s) or paths to Turtle files (default %s); %s only checks the mandatory identifiers", strings.Join(common.EmbeddedShapeNames(), ", "), common.DefaultShapes, common.CoreShapes))
+	validateMetadataCmd.Flags().StringVarP(&validateFormat, "format", "f", "text", "Report format: text or json")
+	rootCmd.AddCommand(validateMetadataCmd)
+}
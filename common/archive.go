@@ -0,0 +1,385 @@
+package common
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// ArchiveEntrySeparator separates the path of an archive from the name of an entry inside it in the paths
+// recorded for ingested archive entries, e.g. "/data/delivery.zip!/stations/a.ttl".
+const ArchiveEntrySeparator = "!/"
+
+// archiveExtensions are the file name suffixes of the supported archives.
+var archiveExtensions = []string{".tar.gz", ".tgz", ".zip"}
+
+// ArchiveExtensions returns the file name suffixes of the archives PopulateEnv reads, sorted.
+func ArchiveExtensions() []string {
+	return append([]string{}, archiveExtensions...)
+}
+
+// IsArchive reports whether path names a supported archive, a zip file or a gzip compressed tarball, by its
+// extension.
+func IsArchive(path string) bool {
+	lower := strings.ToLower(path)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// ArchiveEntryPath returns the path recorded for the entry named entry of the archive at archivePath.
+func ArchiveEntryPath(archivePath, entry string) string {
+	return archivePath + ArchiveEntrySeparator + entry
+}
+
+// SplitArchiveEntryPath splits a path returned by ArchiveEntryPath into the path of the archive and the name of
+// the entry. It returns false for any other path.
+func SplitArchiveEntryPath(path string) (archivePath, entry string, ok bool) {
+	archivePath, entry, ok = strings.Cut(path, ArchiveEntrySeparator)
+	if !ok || entry == "" || !IsArchive(archivePath) {
+		return "", "", false
+	}
+	return archivePath, entry, true
+}
+
+// archiveEntry is an RDF entry of an archive, as read by scanArchive.
+type archiveEntry struct {
+	// path of the entry, see ArchiveEntryPath
+	path string
+	// format of the entry, from its extension
+	format RDFFormat
+	// size of the entry content in bytes
+	size int64
+	// syntax error of the entry, nil when it is valid
+	invalid error
+}
+
+// populateArchive ingests the RDF entries of the archive at archivePath. Like the files of a directory, the
+// entries are selected with opts.Filter and checked for syntax errors before anything is uploaded, and then
+// uploaded in parallel. The archive is read once to check every entry, and every entry is then streamed to
+// the gateway from another read of the archive with archiveEntryBody, so that no entry is held in memory or
+// extracted to disk. The entries are returned with the paths of ArchiveEntryPath.
+//
+// The ingestion settings of the entries are resolved from opts.Ingestion and the ingestion manifest next to
+// the archive; manifests inside the archive are not read.
+func populateArchive(ctx context.Context, archivePath string, postURL url.URL, opts PopulateEnvOpts) ([]IngestedFile, error) {
+	successfulFiles := []IngestedFile{}
+
+	settings, err := resolveIngestionSettings(filepath.Dir(archivePath), []string{archivePath}, opts.Ingestion.Inherit(DefaultIngestionSettings()))
+	if err != nil {
+		return successfulFiles, err
+	}
+
+	entries, err := scanArchive(archivePath, opts.Filter)
+	if err != nil {
+		return successfulFiles, err
+	}
+
+	if len(entries) == 0 {
+		display.Warn("No RDF files to ingest in archive '%s'", archivePath)
+		return successfulFiles, nil
+	}
+
+	var invalidErrs []error
+	for _, entry := range entries {
+		if entry.invalid != nil {
+			invalidErrs = append(invalidErrs, entry.invalid)
+			opts.Report.Add(FileResult{Path: entry.path, Status: FileInvalid, Error: entry.invalid.Error(), SizeBytes: entry.size})
+		}
+	}
+
+	if len(invalidErrs) > 0 && !opts.KeepGoing {
+		for _, entry := range entries {
+			if entry.invalid == nil {
+				opts.Report.Add(FileResult{Path: entry.path, Status: FileSkipped, Error: "not uploaded because other files are not valid", SizeBytes: entry.size})
+			}
+		}
+		return successfulFiles, fmt.Errorf("%d of %d file(s) are not valid RDF, first error: %w", len(invalidErrs), len(entries), invalidErrs[0])
+	}
+	if len(invalidErrs) == len(entries) {
+		return successfulFiles, fmt.Errorf("no valid RDF files to ingest in '%s': %w", archivePath, errors.Join(invalidErrs...))
+	}
+	if len(invalidErrs) > 0 {
+		display.Warn("Leaving out %d invalid file(s), ingesting the other %d", len(invalidErrs), len(entries)-len(invalidErrs))
+	}
+
+	display.Step("Starting ingestion of %d RDF file(s) from archive '%s'", len(entries)-len(invalidErrs), archivePath)
+
+	eg, uploadCtx := uploadGroup(ctx, opts)
+	var mu sync.Mutex
+	skipped := 0
+	var failedErrs []error
+	started := map[string]bool{}
+
+	for _, entry := range entries {
+		if entry.invalid != nil {
+			continue
+		}
+		if uploadCtx.Err() != nil {
+			break
+		}
+		started[entry.path] = true
+
+		eg.Go(func() error {
+			_, name, _ := SplitArchiveEntryPath(entry.path)
+			start := time.Now()
+			file, changed, err := postBody(uploadCtx, entry.path, entry.format, archiveEntryBody(archivePath, name, entry.size), postURL, settings[archivePath], opts)
+			reportIngestion(opts.Report, entry.path, start, file, changed, err)
+			if err != nil && uploadCtx.Err() != nil {
+				display.Warn("Upload of '%s' aborted", name)
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				display.Error("Failed to ingest '%s': %v", name, err)
+				failedErrs = append(failedErrs, err)
+				return err
+			}
+			if !changed {
+				skipped++
+				return nil
+			}
+			successfulFiles = append(successfulFiles, file)
+			return nil
+		})
+	}
+	err = eg.Wait()
+
+	for _, entry := range entries {
+		if entry.invalid == nil && !started[entry.path] {
+			opts.Report.Add(FileResult{Path: entry.path, Status: FileSkipped, Error: notUploadedReason(ctx), SizeBytes: entry.size})
+		}
+	}
+	if ctx.Err() != nil {
+		return successfulFiles, fmt.Errorf("ingestion of archive '%s' cancelled after %d of %d file(s): %w", archivePath, len(successfulFiles)+skipped, len(entries), ctx.Err())
+	}
+	if opts.KeepGoing && len(failedErrs)+len(invalidErrs) > 0 {
+		return successfulFiles, fmt.Errorf("%d of %d file(s) failed to ingest in archive '%s': %w", len(failedErrs)+len(invalidErrs), len(entries), archivePath, errors.Join(append(invalidErrs, failedErrs...)...))
+	}
+	if err != nil {
+		return successfulFiles, fmt.Errorf("one or more files failed to ingest in archive '%s': %w", archivePath, err)
+	}
+
+	if skipped > 0 {
+		display.Done("Successfully ingested %d RDF file(s) from archive '%s', skipped %d unchanged", len(successfulFiles), archivePath, skipped)
+	} else {
+		display.Done("Successfully ingested all RDF files from archive '%s'", archivePath)
+	}
+
+	return successfulFiles, nil
+}
+
+// scanArchive reads the RDF entries of the archive at archivePath selected by filter, checking the syntax of
+// every entry as a stream while it is read and printing every syntax error. It returns the entries in the
+// order they are stored.
+func scanArchive(archivePath string, filter PathFilter) ([]archiveEntry, error) {
+	display.Step("Checking syntax of the files in archive '%s'", filepath.Base(archivePath))
+
+	var entries []archiveEntry
+	err := walkArchive(archivePath, filter, func(name string, r io.Reader) error {
+		format, _ := RDFFormatFromExtension(name)
+		entry := archiveEntry{path: ArchiveEntryPath(archivePath, name), format: format}
+
+		size := &countingWriter{}
+		tee := io.TeeReader(r, size)
+		entry.invalid = validateRDFReader(entry.path, format, tee)
+		// the entry is read to its end when the check stops early, to know its size
+		if _, err := io.Copy(io.Discard, tee); err != nil {
+			return fmt.Errorf("failed to read '%s' in archive '%s': %w", name, archivePath, err)
+		}
+		entry.size = size.n
+
+		if entry.invalid != nil {
+			display.Error("%v", entry.invalid)
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// countingWriter discards what is written to it, counting the bytes.
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// loadArchiveEntry parses the entry named entry of the archive at archivePath, streaming it from the archive,
+// and adds its triples to g.
+func loadArchiveEntry(g *Graph, archivePath, entry string) error {
+	found := false
+	err := walkArchive(archivePath, PathFilter{}, func(name string, r io.Reader) error {
+		if name != entry || found {
+			return nil
+		}
+		found = true
+		format, _ := RDFFormatFromExtension(name)
+		return g.loadReader(ArchiveEntryPath(archivePath, name), format, r)
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no file '%s' in archive '%s'", entry, archivePath)
+	}
+	return nil
+}
+
+// errEntryRead stops walking an archive once the entry read by openArchiveEntry is copied.
+var errEntryRead = errors.New("archive entry read")
+
+// archiveEntryBody returns the content of the entry named entry of the archive at archivePath, of size bytes, as
+// a request body read with openArchiveEntry.
+func archiveEntryBody(archivePath, entry string, size int64) requestBody {
+	return requestBody{
+		open: func() (io.ReadCloser, error) {
+			return openArchiveEntry(archivePath, entry), nil
+		},
+		size: size,
+	}
+}
+
+// openArchiveEntry returns a reader of the entry named entry of the archive at archivePath, streamed from the
+// archive while it is read. Zip entries are read directly, while a gzip compressed tarball is decompressed up
+// to the entry. Reading fails when the archive has no such entry, and closing the reader early stops reading
+// the archive.
+func openArchiveEntry(archivePath, entry string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		found := false
+		err := walkArchive(archivePath, PathFilter{}, func(name string, r io.Reader) error {
+			if name != entry {
+				return nil
+			}
+			found = true
+			if _, err := io.Copy(pw, r); err != nil {
+				return err
+			}
+			return errEntryRead
+		})
+		switch {
+		case errors.Is(err, errEntryRead):
+			err = nil
+		case err == nil && !found:
+			err = fmt.Errorf("no file '%s' in archive '%s'", entry, archivePath)
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// walkArchive calls fn with the name and content of every RDF entry of the archive at archivePath selected by
+// filter, in the order they are stored. Directories, links, files without an RDF extension and the resource
+// forks macOS adds to zip files are skipped. The reader passed to fn is only valid until fn returns.
+func walkArchive(archivePath string, filter PathFilter, fn func(name string, r io.Reader) error) error {
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		return walkZip(archivePath, filter, fn)
+	}
+	return walkTarGz(archivePath, filter, fn)
+}
+
+func walkZip(archivePath string, filter PathFilter, fn func(name string, r io.Reader) error) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive '%s': %w", archivePath, err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		name, ok := archiveEntryName(f.Name, f.Mode(), filter)
+		if !ok {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read '%s' in archive '%s': %w", f.Name, archivePath, err)
+		}
+		err = fn(name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkTarGz(archivePath string, filter PathFilter, fn func(name string, r io.Reader) error) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive '%s': %w", archivePath, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to decompress archive '%s': %w", archivePath, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive '%s': %w", archivePath, err)
+		}
+
+		name, ok := archiveEntryName(header.Name, header.FileInfo().Mode(), filter)
+		if !ok {
+			continue
+		}
+		if err := fn(name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// archiveEntryName returns the cleaned, slash separated name of an archive entry and whether the entry is an
+// RDF file selected by filter.
+func archiveEntryName(name string, mode fs.FileMode, filter PathFilter) (string, bool) {
+	if !mode.IsRegular() {
+		return "", false
+	}
+
+	name = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, `\`, "/")), "/")
+	if name == "" || strings.HasPrefix(name, "__MACOSX/") {
+		return "", false
+	}
+	if _, ok := RDFFormatFromExtension(name); !ok {
+		return "", false
+	}
+	if !filter.selects(name) {
+		display.Debug("skipping filtered archive entry: %s", name)
+		return "", false
+	}
+	return name, true
+}
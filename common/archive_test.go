@@ -0,0 +1,301 @@
+package common
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// archiveEntries are the entries of the archives written by writeTestArchive, in order.
+var archiveEntries = []struct {
+	name    string
+	content string
+}{
+	{name: "./a.ttl", content: ttl("content a")},
+	{name: "sub/b.jsonld", content: `{"@id": "urn:ex:s", "urn:ex:p": "content b"}`},
+	{name: "sub/notes.txt", content: "not metadata"},
+	{name: "__MACOSX/._a.ttl", content: "resource fork"},
+	{name: "drafts/c.ttl", content: ttl("content c")},
+}
+
+// writeTestArchive writes archiveEntries, plus invalid when set, to a new archive named name in dir and
+// returns its path.
+func writeTestArchive(t *testing.T, dir, name, invalid string) string {
+	t.Helper()
+
+	archivePath := filepath.Join(dir, name)
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	defer f.Close()
+
+	entries := slices.Clone(archiveEntries)
+	if invalid != "" {
+		entries = append(entries, struct {
+			name    string
+			content string
+		}{name: "invalid.ttl", content: invalid})
+	}
+
+	if filepath.Ext(name) == ".zip" {
+		zw := zip.NewWriter(f)
+		if _, err := zw.Create("sub/"); err != nil {
+			t.Fatalf("Failed to write archive: %v", err)
+		}
+		for _, entry := range entries {
+			w, err := zw.Create(entry.name)
+			if err != nil {
+				t.Fatalf("Failed to write archive: %v", err)
+			}
+			if _, err := io.WriteString(w, entry.content); err != nil {
+				t.Fatalf("Failed to write archive: %v", err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("Failed to write archive: %v", err)
+		}
+		return archivePath
+	}
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: "sub/", Typeflag: tar.TypeDir, Mode: 0o750}); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+	for _, entry := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: entry.name, Typeflag: tar.TypeReg, Mode: 0o600, Size: int64(len(entry.content))}); err != nil {
+			t.Fatalf("Failed to write archive: %v", err)
+		}
+		if _, err := io.WriteString(tw, entry.content); err != nil {
+			t.Fatalf("Failed to write archive: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+	return archivePath
+}
+
+func TestPopulateArchive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		archive          string
+		invalid          string
+		filter           PathFilter
+		ingested         map[string]string // entry -> previously ingested content, enables incremental mode
+		expectErr        bool
+		expectedEntries  []string
+		expectedRequests int
+	}{
+		{
+			name:             "zip",
+			archive:          "delivery.zip",
+			expectedEntries:  []string{"a.ttl", "drafts/c.ttl", "sub/b.jsonld"},
+			expectedRequests: 3,
+		},
+		{
+			name:             "tarball",
+			archive:          "delivery.tar.gz",
+			expectedEntries:  []string{"a.ttl", "drafts/c.ttl", "sub/b.jsonld"},
+			expectedRequests: 3,
+		},
+		{
+			name:             "filtered",
+			archive:          "delivery.tgz",
+			filter:           PathFilter{Exclude: []string{"drafts/"}},
+			expectedEntries:  []string{"a.ttl", "sub/b.jsonld"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "incremental",
+			archive:          "delivery.zip",
+			ingested:         map[string]string{"a.ttl": ttl("content a"), "drafts/c.ttl": ttl("old content c")},
+			expectedEntries:  []string{"drafts/c.ttl", "sub/b.jsonld"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "invalid_entry_prevents_upload",
+			archive:          "delivery.tar.gz",
+			invalid:          "<urn:ex:s> <urn:ex:p> .",
+			expectErr:        true,
+			expectedEntries:  []string{},
+			expectedRequests: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			archivePath := writeTestArchive(t, tmpDir, tc.archive, tc.invalid)
+
+			var mu sync.Mutex
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				mu.Lock()
+				bodies = append(bodies, string(body))
+				mu.Unlock()
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			ingested := map[string]string{}
+			for entry, content := range tc.ingested {
+				sum := sha256.Sum256([]byte(content))
+				ingested[ArchiveEntryPath(archivePath, entry)] = hex.EncodeToString(sum[:])
+			}
+
+			files, err := PopulateEnv(t.Context(), PopulateEnvOpts{
+				Path:        archivePath,
+				EndpointURL: server.URL,
+				Parallel:    2,
+				Ingested:    ingested,
+				Filter:      tc.filter,
+			})
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("Expected error %v, got: %v", tc.expectErr, err)
+			}
+
+			got := []string{}
+			for _, file := range files {
+				gotArchive, entry, ok := SplitArchiveEntryPath(file.Path)
+				if !ok || gotArchive != archivePath {
+					t.Errorf("Expected an entry of %s, got %s", archivePath, file.Path)
+				}
+				if file.ContentHash == "" || file.SizeBytes == 0 {
+					t.Errorf("Expected the hash and size of %s to be recorded, got %+v", file.Path, file)
+				}
+				got = append(got, entry)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tc.expectedEntries) {
+				t.Errorf("Expected entries %v, got %v", tc.expectedEntries, got)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(bodies) != tc.expectedRequests {
+				t.Errorf("Expected %d requests, got %d", tc.expectedRequests, len(bodies))
+			}
+			for _, body := range bodies {
+				if err := ValidateTurtle(strings.NewReader(body)); err != nil {
+					t.Errorf("Expected Turtle to be posted, got %q: %v", body, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitArchiveEntryPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path    string
+		archive string
+		entry   string
+		ok      bool
+	}{
+		{path: "/data/delivery.zip!/stations/a.ttl", archive: "/data/delivery.zip", entry: "stations/a.ttl", ok: true},
+		{path: "/data/delivery.TAR.GZ!/a.ttl", archive: "/data/delivery.TAR.GZ", entry: "a.ttl", ok: true},
+		{path: "/data/delivery.zip", ok: false},
+		{path: "/data/wow!/a.ttl", ok: false},
+		{path: "/data/delivery.zip!/", ok: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			archivePath, entry, ok := SplitArchiveEntryPath(tc.path)
+			if ok != tc.ok || archivePath != tc.archive || entry != tc.entry {
+				t.Errorf("Expected (%q, %q, %v), got (%q, %q, %v)", tc.archive, tc.entry, tc.ok, archivePath, entry, ok)
+			}
+		})
+	}
+}
+
+func TestLoadArchiveEntry(t *testing.T) {
+	t.Parallel()
+
+	archivePath := writeTestArchive(t, t.TempDir(), "delivery.tar.gz", "")
+
+	g := NewGraph()
+	if err := loadArchiveEntry(g, archivePath, "drafts/c.ttl"); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	expected := NewGraph()
+	if err := expected.LoadTurtle(strings.NewReader(ttl("content c")), ""); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if got, want := len(g.Triples()), len(expected.Triples()); got != want || got == 0 {
+		t.Errorf("Expected %d triples, got %d", want, got)
+	}
+
+	if err := loadArchiveEntry(NewGraph(), archivePath, "missing.ttl"); err == nil {
+		t.Error("Expected an error for a missing entry, but got nil")
+	}
+}
+
+func TestOpenArchiveEntry(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"delivery.zip", "delivery.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			archivePath := writeTestArchive(t, t.TempDir(), name, "")
+
+			entries, err := scanArchive(archivePath, PathFilter{})
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if len(entries) != 3 {
+				t.Fatalf("Expected 3 entries, got %d", len(entries))
+			}
+			for _, entry := range entries {
+				if entry.invalid != nil {
+					t.Errorf("Expected %s to be valid, got: %v", entry.path, entry.invalid)
+				}
+				_, entryName, _ := SplitArchiveEntryPath(entry.path)
+				r := openArchiveEntry(archivePath, entryName)
+				content, err := io.ReadAll(r)
+				r.Close()
+				if err != nil {
+					t.Fatalf("Expected no error reading %s, but got: %v", entry.path, err)
+				}
+				if int64(len(content)) != entry.size || entry.size == 0 {
+					t.Errorf("Expected %s to have its size %d, got %d", entry.path, entry.size, len(content))
+				}
+			}
+
+			// closing the reader before the end stops reading the archive
+			r := openArchiveEntry(archivePath, "drafts/c.ttl")
+			if err := r.Close(); err != nil {
+				t.Errorf("Expected no error closing the entry, but got: %v", err)
+			}
+
+			if _, err := io.ReadAll(openArchiveEntry(archivePath, "missing.ttl")); err == nil {
+				t.Error("Expected an error for a missing entry, but got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dataset is a named group of paths populated with the same filter and ingestion settings.
+type Dataset struct {
+	// Name of the dataset, shown while it is populated
+	Name string `yaml:"name"`
+	// Directories, archives or RDF files of the dataset. Relative paths are relative to the manifest
+	Paths []string `yaml:"paths"`
+	// Glob patterns selecting the files ingested from the directories and archives of the dataset, see PathFilter
+	Include []string `yaml:"include,omitempty"`
+	// Glob patterns of the files skipped in the directories and archives of the dataset, see PathFilter
+	Exclude []string `yaml:"exclude,omitempty"`
+	// Ingestion type, model and mapping of the files. Empty fields inherit the settings given to populate
+	Ingestion IngestionSettings `yaml:",inline"`
+	// Position of the dataset in the populate order. Datasets with a lower order are populated first, and
+	// datasets with the same order in the order they are listed
+	Order int `yaml:"order,omitempty"`
+}
+
+// Filter returns the filter selecting the files of the dataset.
+func (d Dataset) Filter() PathFilter {
+	return PathFilter{Include: d.Include, Exclude: d.Exclude}
+}
+
+// DatasetManifest lists the datasets an environment is populated with, so that the same populate can be
+// repeated.
+type DatasetManifest struct {
+	// Whether the examples embedded in the binary are populated before the datasets
+	Examples bool `yaml:"examples,omitempty"`
+	// Datasets to populate
+	Datasets []Dataset `yaml:"datasets"`
+}
+
+// LoadDatasetManifest reads and validates the dataset manifest at path. The relative paths of its datasets
+// are resolved against the directory of the manifest.
+func LoadDatasetManifest(path string) (DatasetManifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return DatasetManifest{}, fmt.Errorf("failed to read dataset manifest '%s': %w", path, err)
+	}
+
+	m, err := ParseDatasetManifest(content, filepath.Dir(path))
+	if err != nil {
+		return DatasetManifest{}, fmt.Errorf("invalid dataset manifest '%s': %w", path, err)
+	}
+	return m, nil
+}
+
+// ParseDatasetManifest parses and validates a dataset manifest, resolving the relative paths of its datasets
+// against baseDir.
+func ParseDatasetManifest(content []byte, baseDir string) (DatasetManifest, error) {
+	var m DatasetManifest
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return DatasetManifest{}, err
+	}
+
+	if err := m.Validate(); err != nil {
+		return DatasetManifest{}, err
+	}
+
+	for i, d := range m.Datasets {
+		paths := make([]string, len(d.Paths))
+		for j, p := range d.Paths {
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(baseDir, p)
+			}
+			paths[j] = p
+		}
+		m.Datasets[i].Paths = paths
+	}
+	return m, nil
+}
+
+// Validate checks that every dataset has a unique name and at least one path, and that its filter and
+// ingestion settings are valid.
+func (m DatasetManifest) Validate() error {
+	if len(m.Datasets) == 0 && !m.Examples {
+		return fmt.Errorf("no datasets or examples to populate")
+	}
+
+	names := map[string]bool{}
+	for _, d := range m.Datasets {
+		if d.Name == "" {
+			return fmt.Errorf("dataset name is required")
+		}
+		if names[d.Name] {
+			return fmt.Errorf("dataset %s is listed more than once", d.Name)
+		}
+		names[d.Name] = true
+
+		if len(d.Paths) == 0 {
+			return fmt.Errorf("dataset %s has no paths", d.Name)
+		}
+		if err := d.Filter().Validate(); err != nil {
+			return fmt.Errorf("dataset %s: %w", d.Name, err)
+		}
+		if err := d.Ingestion.Validate(); err != nil {
+			return fmt.Errorf("dataset %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+// Ordered returns the datasets of the manifest in the order they are populated.
+func (m DatasetManifest) Ordered() []Dataset {
+	datasets := slices.Clone(m.Datasets)
+	slices.SortStableFunc(datasets, func(a, b Dataset) int {
+		return a.Order - b.Order
+	})
+	return datasets
+}
+
+// PopulateDatasets returns the datasets of a populate in the order they are ingested: the given paths, as an
+// unnamed dataset with the given filter and ingestion settings, followed by the datasets of the manifest, whose
+// empty ingestion settings inherit the given ones.
+func PopulateDatasets(paths []string, filter PathFilter, ingestion IngestionSettings, manifest DatasetManifest) []Dataset {
+	var datasets []Dataset
+	if len(paths) > 0 {
+		datasets = append(datasets, Dataset{
+			Paths:     paths,
+			Include:   filter.Include,
+			Exclude:   filter.Exclude,
+			Ingestion: ingestion,
+		})
+	}
+
+	for _, d := range manifest.Ordered() {
+		d.Ingestion = d.Ingestion.Inherit(ingestion)
+		datasets = append(datasets, d)
+	}
+	return datasets
+}
+
+// DatasetPaths returns the paths of all the given datasets.
+func DatasetPaths(datasets []Dataset) []string {
+	var paths []string
+	for _, d := range datasets {
+		paths = append(paths, d.Paths...)
+	}
+	return paths
+}
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseDatasetManifest(t *testing.T) {
+	t.Parallel()
+
+	baseDir := filepath.Join(string(filepath.Separator), "data")
+
+	tests := []struct {
+		name      string
+		content   string
+		wantErr   bool
+		examples  bool
+		wantNames []string
+		wantPaths []string
+	}{
+		{
+			name: "ordered datasets",
+			content: `examples: true
+datasets:
+  - name: volcanoes
+    order: 2
+    paths: [volcanoes, /shared/volcanoes.zip]
+    include: ["**/*.ttl"]
+    model: EPOS-DCAT-AP-V3
+  - name: stations
+    order: 1
+    paths: [stations]
+  - name: faults
+    order: 2
+    paths: [faults.ttl]
+`,
+			examples:  true,
+			wantNames: []string{"stations", "volcanoes", "faults"},
+			wantPaths: []string{
+				filepath.Join(baseDir, "stations"),
+				filepath.Join(baseDir, "volcanoes"),
+				"/shared/volcanoes.zip",
+				filepath.Join(baseDir, "faults.ttl"),
+			},
+		},
+		{name: "examples only", content: "examples: true\n", examples: true},
+		{name: "empty", content: "", wantErr: true},
+		{name: "unknown field", content: "datasets:\n  - name: a\n    path: a\n", wantErr: true},
+		{name: "missing name", content: "datasets:\n  - paths: [a]\n", wantErr: true},
+		{name: "duplicate name", content: "datasets:\n  - name: a\n    paths: [a]\n  - name: a\n    paths: [b]\n", wantErr: true},
+		{name: "missing paths", content: "datasets:\n  - name: a\n", wantErr: true},
+		{name: "invalid pattern", content: "datasets:\n  - name: a\n    paths: [a]\n    include: ['[']\n", wantErr: true},
+		{name: "invalid model", content: "datasets:\n  - name: a\n    paths: [a]\n    model: 'EPOS DCAT'\n", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := ParseDatasetManifest([]byte(tc.content), baseDir)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Expected error %v, got: %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if m.Examples != tc.examples {
+				t.Errorf("Expected examples %v, got %v", tc.examples, m.Examples)
+			}
+
+			var names []string
+			for _, d := range m.Ordered() {
+				names = append(names, d.Name)
+			}
+			if !reflect.DeepEqual(names, tc.wantNames) {
+				t.Errorf("Expected datasets %v, got %v", tc.wantNames, names)
+			}
+			if paths := DatasetPaths(m.Ordered()); !reflect.DeepEqual(paths, tc.wantPaths) {
+				t.Errorf("Expected paths %v, got %v", tc.wantPaths, paths)
+			}
+		})
+	}
+}
+
+func TestLoadDatasetManifest(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "datasets.yaml")
+	if err := os.WriteFile(path, []byte("datasets:\n  - name: a\n    paths: [metadata]\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	m, err := LoadDatasetManifest(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []string{filepath.Join(tmpDir, "metadata")}; !reflect.DeepEqual(m.Datasets[0].Paths, want) {
+		t.Errorf("Expected paths %v, got %v", want, m.Datasets[0].Paths)
+	}
+
+	if _, err := LoadDatasetManifest(filepath.Join(tmpDir, "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing manifest")
+	}
+}
+
+func TestPopulateDatasets(t *testing.T) {
+	t.Parallel()
+
+	ingestion := IngestionSettings{Model: "EPOS-DCAT-AP-V3", Mapping: "EDM-TO-DCAT-AP"}
+	filter := PathFilter{Exclude: []string{"drafts/"}}
+	manifest := DatasetManifest{Datasets: []Dataset{
+		{Name: "b", Paths: []string{"b"}, Order: 1, Ingestion: IngestionSettings{Model: "EPOS-DCAT-AP-V1"}},
+		{Name: "a", Paths: []string{"a"}, Include: []string{"*.ttl"}},
+	}}
+
+	got := PopulateDatasets([]string{"cli"}, filter, ingestion, manifest)
+	want := []Dataset{
+		{Paths: []string{"cli"}, Exclude: []string{"drafts/"}, Ingestion: ingestion},
+		{Name: "a", Paths: []string{"a"}, Include: []string{"*.ttl"}, Ingestion: ingestion},
+		{Name: "b", Paths: []string{"b"}, Order: 1, Ingestion: IngestionSettings{Model: "EPOS-DCAT-AP-V1", Mapping: "EDM-TO-DCAT-AP"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected datasets %+v, got %+v", want, got)
+	}
+
+	if got := PopulateDatasets(nil, filter, ingestion, DatasetManifest{}); len(got) != 0 {
+		t.Errorf("Expected no datasets, got %+v", got)
+	}
+}
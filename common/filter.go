@@ -0,0 +1,164 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the name of the file, in the root of a populated directory, listing glob patterns of the
+// files and directories to skip, one per line.
+const IgnoreFileName = ".eposignore"
+
+// PathFilter selects the files ingested from a directory with glob patterns.
+//
+// Patterns are matched against the slash separated path of a file relative to the populated directory, with
+// the syntax of path.Match plus "**", which matches any number of directories. A pattern containing a slash
+// matches the whole relative path (a leading slash is ignored); a pattern without a slash matches the name of
+// the file or, for exclusions, of any directory above it. An exclusion ending with a slash only matches
+// directories. Files given directly, rather than through a directory, are never filtered.
+type PathFilter struct {
+	// Patterns of the files to ingest. When empty every RDF file is ingested
+	Include []string
+	// Patterns of the files and directories to skip. Excluded directories are not traversed
+	Exclude []string
+}
+
+// Validate checks that every pattern is a valid glob.
+func (f PathFilter) Validate() error {
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if err := validatePattern(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String returns a short description of the filter for logs.
+func (f PathFilter) String() string {
+	return fmt.Sprintf("include=%v exclude=%v", f.Include, f.Exclude)
+}
+
+// withIgnoreFile returns a copy of f excluding also the patterns of the ignore file in root, if any.
+func (f PathFilter) withIgnoreFile(root string) (PathFilter, error) {
+	content, err := os.ReadFile(filepath.Join(root, IgnoreFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return f, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+
+	patterns, err := parseIgnoreFile(content)
+	if err != nil {
+		return f, fmt.Errorf("invalid %s in '%s': %w", IgnoreFileName, root, err)
+	}
+
+	f.Exclude = append(append([]string{}, f.Exclude...), patterns...)
+	return f, nil
+}
+
+// parseIgnoreFile returns the patterns of an ignore file, skipping blank lines and comments starting with #.
+func parseIgnoreFile(content []byte) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		pattern := strings.TrimSpace(scanner.Text())
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		if strings.HasPrefix(pattern, "!") {
+			return nil, fmt.Errorf("line %d: negated patterns are not supported", line)
+		}
+		if err := validatePattern(pattern); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, scanner.Err()
+}
+
+// excludes reports whether the file or directory at the slash separated relative path rel, or any directory
+// above it, matches an exclusion.
+func (f PathFilter) excludes(rel string, isDir bool) bool {
+	segments := strings.Split(rel, "/")
+	for i := range segments {
+		last := i == len(segments)-1
+		for _, pattern := range f.Exclude {
+			dirOnly := strings.HasSuffix(pattern, "/")
+			if dirOnly && last && !isDir {
+				continue
+			}
+			if matchPattern(strings.TrimSuffix(pattern, "/"), segments[:i+1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selects reports whether the file at the slash separated relative path rel is ingested.
+func (f PathFilter) selects(rel string) bool {
+	if f.excludes(rel, false) {
+		return false
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	segments := strings.Split(rel, "/")
+	for _, pattern := range f.Include {
+		if matchPattern(pattern, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches pattern against a path split in segments. A pattern without a slash only matches the
+// last segment.
+func matchPattern(pattern string, segments []string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, segments[len(segments)-1])
+		return ok
+	}
+	return matchSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), segments)
+}
+
+// matchSegments matches the segments of a pattern, where "**" matches any number of segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], segments[0])
+	return ok && matchSegments(pattern[1:], segments[1:])
+}
+
+// validatePattern checks the syntax of every segment of pattern.
+func validatePattern(pattern string) error {
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" {
+		return fmt.Errorf("invalid pattern %q: pattern is empty", pattern)
+	}
+	for _, segment := range strings.Split(trimmed, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPathFilterSelects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filter   PathFilter
+		path     string
+		expected bool
+	}{
+		{name: "no patterns", filter: PathFilter{}, path: "a/b.ttl", expected: true},
+		{name: "basename exclusion", filter: PathFilter{Exclude: []string{"*.draft.ttl"}}, path: "sub/a.draft.ttl", expected: false},
+		{name: "basename exclusion other file", filter: PathFilter{Exclude: []string{"*.draft.ttl"}}, path: "sub/a.ttl", expected: true},
+		{name: "directory name exclusion", filter: PathFilter{Exclude: []string{"drafts"}}, path: "x/drafts/a.ttl", expected: false},
+		{name: "directory only exclusion", filter: PathFilter{Exclude: []string{"a.ttl/"}}, path: "a.ttl", expected: true},
+		{name: "directory only exclusion of directory", filter: PathFilter{Exclude: []string{"old/"}}, path: "old/a.ttl", expected: false},
+		{name: "rooted exclusion", filter: PathFilter{Exclude: []string{"/old"}}, path: "sub/old/a.ttl", expected: true},
+		{name: "rooted exclusion at root", filter: PathFilter{Exclude: []string{"/old"}}, path: "old/a.ttl", expected: false},
+		{name: "include with double star", filter: PathFilter{Include: []string{"stations/**/*.ttl"}}, path: "stations/eu/it/a.ttl", expected: true},
+		{name: "include with double star no dirs", filter: PathFilter{Include: []string{"stations/**/*.ttl"}}, path: "stations/a.ttl", expected: true},
+		{name: "include not matching", filter: PathFilter{Include: []string{"stations/**/*.ttl"}}, path: "other/a.ttl", expected: false},
+		{name: "exclude wins over include", filter: PathFilter{Include: []string{"*.ttl"}, Exclude: []string{"tmp/"}}, path: "tmp/a.ttl", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.filter.selects(tc.path); got != tc.expected {
+				t.Errorf("Expected %v for %s with %s, got %v", tc.expected, tc.path, tc.filter, got)
+			}
+		})
+	}
+}
+
+func TestPathFilterValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		filter  PathFilter
+		wantErr bool
+	}{
+		{name: "valid patterns", filter: PathFilter{Include: []string{"**/*.ttl"}, Exclude: []string{"drafts/"}}},
+		{name: "malformed include", filter: PathFilter{Include: []string{"[a-"}}, wantErr: true},
+		{name: "empty exclude", filter: PathFilter{Exclude: []string{"/"}}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tc.filter.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("Expected error %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseIgnoreFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "patterns and comments", content: "# drafts\ndrafts/\n\n  *.bak.ttl  \n", expected: []string{"drafts/", "*.bak.ttl"}},
+		{name: "negation", content: "drafts/\n!drafts/keep.ttl\n", wantErr: true},
+		{name: "malformed pattern", content: "[a-\n", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseIgnoreFile([]byte(tc.content))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Expected error %v, got: %v", tc.wantErr, err)
+			}
+			if !tc.wantErr && !slices.Equal(got, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCollectRDFFilesFilter(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	for path, content := range map[string]string{
+		"a.ttl":                ttl("a"),
+		"a.draft.ttl":          ttl("draft"),
+		"drafts/b.ttl":         ttl("b"),
+		"stations/eu/c.ttl":    ttl("c"),
+		"stations/eu/d.jsonld": "{}",
+		IgnoreFileName:         "# work in progress\ndrafts/\n",
+	} {
+		fullPath := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filter   PathFilter
+		expected []string
+	}{
+		{name: "ignore file only", filter: PathFilter{}, expected: []string{"a.draft.ttl", "a.ttl", "stations/eu/c.ttl", "stations/eu/d.jsonld"}},
+		{name: "with exclude", filter: PathFilter{Exclude: []string{"*.draft.ttl"}}, expected: []string{"a.ttl", "stations/eu/c.ttl", "stations/eu/d.jsonld"}},
+		{name: "with include", filter: PathFilter{Include: []string{"stations/**/*.ttl"}}, expected: []string{"stations/eu/c.ttl"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			files, _, err := collectRDFFiles(tmpDir, tc.filter)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			var got []string
+			for _, file := range files {
+				rel, err := filepath.Rel(tmpDir, file)
+				if err != nil {
+					t.Fatalf("Failed to resolve relative path: %v", err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
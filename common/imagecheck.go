@@ -59,7 +59,9 @@ func ImageExistsLocally(ctx context.Context, imageRef string) (bool, error) {
 	return true, nil
 }
 
-func localImageDigest(ctx context.Context, imageRef string) (string, error) {
+// LocalImageDigest returns the repository digest (repo@sha256:...) of the local image imageRef, or
+// ErrImageMissing when the image is not available locally.
+func LocalImageDigest(ctx context.Context, imageRef string) (string, error) {
 	if imageRef == "" {
 		return "", fmt.Errorf("invalid image reference: %q", imageRef)
 	}
@@ -87,7 +89,7 @@ func imageHasUpdate(ctx context.Context, imageRef string) (bool, *time.Time, err
 		return false, nil, fmt.Errorf("invalid image reference: %q", imageRef)
 	}
 
-	digest, err := localImageDigest(ctx, imageRef)
+	digest, err := LocalImageDigest(ctx, imageRef)
 	if err != nil {
 		return false, nil, err
 	}
@@ -0,0 +1,145 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultIngestionType is the ingestion type used when none is configured.
+	DefaultIngestionType = "single"
+	// DefaultIngestionModel is the metadata model used when none is configured.
+	DefaultIngestionModel = "EPOS-DCAT-AP-V1"
+	// DefaultIngestionMapping is the mapping used when none is configured.
+	DefaultIngestionMapping = "EDM-TO-DCAT-AP"
+
+	// IngestionManifestName is the name of the file that overrides the ingestion settings of the files in
+	// its directory and in all of its subdirectories.
+	IngestionManifestName = ".eposingest.yaml"
+)
+
+// IngestionModels are the metadata models of the ontologies registered by default, see DefaultOntologies.
+var IngestionModels = []string{"EPOS-DCAT-AP-V1", "EPOS-DCAT-AP-V3"}
+
+// IngestionSettings selects how the gateway ingests a file. Empty fields inherit the value of the enclosing
+// settings, falling back to the defaults.
+type IngestionSettings struct {
+	// Ingestion type, sent as the "type" query parameter (e.g., "single")
+	Type string `yaml:"type,omitempty"`
+	// Metadata model of the files, one of the ontologies of type BASE (e.g., "EPOS-DCAT-AP-V3")
+	Model string `yaml:"model,omitempty"`
+	// Mapping applied to the files, one of the ontologies of type MAPPING (e.g., "EDM-TO-DCAT-AP")
+	Mapping string `yaml:"mapping,omitempty"`
+}
+
+// DefaultIngestionSettings returns the settings used when nothing else is configured.
+func DefaultIngestionSettings() IngestionSettings {
+	return IngestionSettings{
+		Type:    DefaultIngestionType,
+		Model:   DefaultIngestionModel,
+		Mapping: DefaultIngestionMapping,
+	}
+}
+
+// Inherit returns s with its empty fields set from parent.
+func (s IngestionSettings) Inherit(parent IngestionSettings) IngestionSettings {
+	if s.Type == "" {
+		s.Type = parent.Type
+	}
+	if s.Model == "" {
+		s.Model = parent.Model
+	}
+	if s.Mapping == "" {
+		s.Mapping = parent.Mapping
+	}
+	return s
+}
+
+// Validate checks that no field contains whitespace.
+func (s IngestionSettings) Validate() error {
+	fields := []struct{ name, value string }{{"type", s.Type}, {"model", s.Model}, {"mapping", s.Mapping}}
+	for _, f := range fields {
+		if strings.ContainsFunc(f.value, unicode.IsSpace) {
+			return fmt.Errorf("invalid ingestion %s %q: must not contain whitespace", f.name, f.value)
+		}
+	}
+	return nil
+}
+
+func (s IngestionSettings) String() string {
+	return fmt.Sprintf("type=%s model=%s mapping=%s", s.Type, s.Model, s.Mapping)
+}
+
+// loadIngestionManifest reads the ingestion manifest of dir. It returns false when dir has no manifest.
+func loadIngestionManifest(dir string) (IngestionSettings, bool, error) {
+	path := filepath.Join(dir, IngestionManifestName)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return IngestionSettings{}, false, nil
+	}
+	if err != nil {
+		return IngestionSettings{}, false, fmt.Errorf("failed to read ingestion manifest '%s': %w", path, err)
+	}
+
+	var settings IngestionSettings
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(&settings); err != nil && !errors.Is(err, io.EOF) {
+		return IngestionSettings{}, false, fmt.Errorf("invalid ingestion manifest '%s': %w", path, err)
+	}
+	if err := settings.Validate(); err != nil {
+		return IngestionSettings{}, false, fmt.Errorf("invalid ingestion manifest '%s': %w", path, err)
+	}
+	return settings, true, nil
+}
+
+// resolveIngestionSettings returns the ingestion settings of every file under root. The manifests of root
+// and of every directory between root and a file override base, the nearest manifest taking precedence.
+func resolveIngestionSettings(root string, files []string, base IngestionSettings) (map[string]IngestionSettings, error) {
+	byDir := map[string]IngestionSettings{}
+
+	var settingsOf func(dir string) (IngestionSettings, error)
+	settingsOf = func(dir string) (IngestionSettings, error) {
+		if settings, ok := byDir[dir]; ok {
+			return settings, nil
+		}
+
+		parent := base
+		if rel, err := filepath.Rel(root, dir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			var err error
+			if parent, err = settingsOf(filepath.Dir(dir)); err != nil {
+				return IngestionSettings{}, err
+			}
+		}
+
+		settings := parent
+		manifest, ok, err := loadIngestionManifest(dir)
+		if err != nil {
+			return IngestionSettings{}, err
+		}
+		if ok {
+			settings = manifest.Inherit(parent)
+		}
+		byDir[dir] = settings
+		return settings, nil
+	}
+
+	result := make(map[string]IngestionSettings, len(files))
+	for _, file := range files {
+		settings, err := settingsOf(filepath.Dir(file))
+		if err != nil {
+			return nil, err
+		}
+		result[file] = settings
+	}
+	return result, nil
+}
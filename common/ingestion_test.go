@@ -0,0 +1,143 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestResolveIngestionSettings(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"a.ttl":                              "",
+		"v3/b.ttl":                           "",
+		"v3/" + IngestionManifestName:        "model: EPOS-DCAT-AP-V3\n",
+		"v3/custom/c.ttl":                    "",
+		"v3/custom/" + IngestionManifestName: "mapping: CUSTOM-MAPPING\ntype: multiple\n",
+		"empty/d.ttl":                        "",
+		"empty/" + IngestionManifestName:     "",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	base := IngestionSettings{Mapping: "BASE-MAPPING"}.Inherit(DefaultIngestionSettings())
+	paths := []string{
+		filepath.Join(tmpDir, "a.ttl"),
+		filepath.Join(tmpDir, "v3", "b.ttl"),
+		filepath.Join(tmpDir, "v3", "custom", "c.ttl"),
+		filepath.Join(tmpDir, "empty", "d.ttl"),
+	}
+	settings, err := resolveIngestionSettings(tmpDir, paths, base)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	expected := []IngestionSettings{
+		{Type: "single", Model: "EPOS-DCAT-AP-V1", Mapping: "BASE-MAPPING"},
+		{Type: "single", Model: "EPOS-DCAT-AP-V3", Mapping: "BASE-MAPPING"},
+		{Type: "multiple", Model: "EPOS-DCAT-AP-V3", Mapping: "CUSTOM-MAPPING"},
+		{Type: "single", Model: "EPOS-DCAT-AP-V1", Mapping: "BASE-MAPPING"},
+	}
+	for i, path := range paths {
+		if settings[path] != expected[i] {
+			t.Errorf("Expected %+v for %s, got %+v", expected[i], path, settings[path])
+		}
+	}
+}
+
+func TestResolveIngestionSettingsInvalidManifest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		manifest string
+		expected string
+	}{
+		{name: "unknown_field", manifest: "modell: EPOS-DCAT-AP-V3\n", expected: "field modell not found"},
+		{name: "whitespace", manifest: "model: EPOS DCAT\n", expected: "must not contain whitespace"},
+		{name: "not_yaml", manifest: "model: [\n", expected: "invalid ingestion manifest"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, IngestionManifestName), []byte(tc.manifest), 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			_, err := resolveIngestionSettings(tmpDir, []string{filepath.Join(tmpDir, "a.ttl")}, DefaultIngestionSettings())
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected an error containing %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestPopulateEnvIngestionSettings(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"a.ttl":                       ttl("content a"),
+		"v3/b.ttl":                    ttl("content b"),
+		"v3/" + IngestionManifestName: "model: EPOS-DCAT-AP-V3\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	received := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		mu.Lock()
+		received[q.Get("model")] = q.Get("type") + " " + q.Get("mapping")
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := PopulateEnv(t.Context(), PopulateEnvOpts{
+		Path:        tmpDir,
+		EndpointURL: server.URL,
+		Parallel:    2,
+		Ingestion:   IngestionSettings{Mapping: "CUSTOM-MAPPING"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	expected := map[string]string{
+		"EPOS-DCAT-AP-V1": "single CUSTOM-MAPPING",
+		"EPOS-DCAT-AP-V3": "single CUSTOM-MAPPING",
+	}
+	if len(received) != len(expected) {
+		t.Fatalf("Expected requests for models %v, got %v", expected, received)
+	}
+	for model, params := range expected {
+		if received[model] != params {
+			t.Errorf("Expected %q for model %s, got %q", params, model, received[model])
+		}
+	}
+}
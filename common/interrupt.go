@@ -0,0 +1,35 @@
+package common
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// NotifyInterrupt returns a copy of parent that is cancelled on the first interrupt (Ctrl-C) or termination
+// signal, so that long running operations can stop gracefully. The default signal handling is restored as
+// soon as the context is done: a second Ctrl-C terminates the process immediately.
+//
+// The returned cancel function must be called to release the signal handler.
+func NotifyInterrupt(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			display.Warn("Interrupted, stopping gracefully. Press Ctrl-C again to quit immediately")
+			cancel()
+		case <-ctx.Done():
+			signal.Stop(signals)
+		}
+	}()
+
+	return ctx, cancel
+}
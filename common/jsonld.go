@@ -0,0 +1,732 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonldContext is an active JSON-LD context.
+type jsonldContext struct {
+	base     string
+	vocab    string
+	language string
+	terms    map[string]jsonldTerm
+}
+
+// jsonldTerm is a term definition of a JSON-LD context.
+type jsonldTerm struct {
+	// expanded IRI or keyword the term maps to. Empty when the term is mapped to null
+	id string
+	// type coercion: "@id", "@vocab" or a datatype IRI
+	typ string
+	// "@list", "@set", "@language" or "@index"
+	container string
+	// default language of the term's string values, only meaningful when hasLanguage is set
+	language    string
+	hasLanguage bool
+	// whether the term is a reverse property
+	reverse bool
+}
+
+func (c *jsonldContext) clone() *jsonldContext {
+	terms := make(map[string]jsonldTerm, len(c.terms))
+	for k, v := range c.terms {
+		terms[k] = v
+	}
+	return &jsonldContext{base: c.base, vocab: c.vocab, language: c.language, terms: terms}
+}
+
+// jsonldParser converts expanded JSON-LD node objects to triples.
+type jsonldParser struct {
+	blanks     map[string]string
+	blankCount int
+	prefixes   map[string]string
+	emit       func(Triple)
+}
+
+// parseJSONLD parses a JSON-LD document, passing every triple of its default graph and of its named graphs
+// to emit. Only embedded contexts are supported: remote contexts referenced by URL are rejected. It
+// returns the prefixes defined in the contexts of the document.
+func parseJSONLD(r io.Reader, base string, emit func(Triple)) (map[string]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, jsonSyntaxError(content, err)
+	}
+	if rest := bytes.TrimLeft(content[dec.InputOffset():], " \t\r\n"); len(rest) > 0 {
+		line, col := offsetPosition(content, int64(len(content)-len(rest)))
+		return nil, &SyntaxError{Line: line, Column: col, Msg: "unexpected data after the JSON document"}
+	}
+
+	p := &jsonldParser{
+		blanks:   map[string]string{},
+		prefixes: map[string]string{},
+		emit:     emit,
+	}
+	ctx := &jsonldContext{base: base, terms: map[string]jsonldTerm{}}
+
+	switch doc := doc.(type) {
+	case []any:
+		for _, item := range doc {
+			if err := p.parseTopLevel(ctx, item); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		if err := p.parseTopLevel(ctx, doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.prefixes, nil
+}
+
+// jsonSyntaxError converts an error of the JSON decoder to a *SyntaxError when its position is known.
+func jsonSyntaxError(content []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// the offset points just after the offending character
+		line, col := offsetPosition(content, max(syntaxErr.Offset-1, 0))
+		return &SyntaxError{Line: line, Column: col, Msg: syntaxErr.Error()}
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		line, col := offsetPosition(content, typeErr.Offset)
+		return &SyntaxError{Line: line, Column: col, Msg: typeErr.Error()}
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		line, col := offsetPosition(content, int64(len(content)))
+		return &SyntaxError{Line: line, Column: col, Msg: "unexpected end of JSON input"}
+	}
+	return err
+}
+
+// offsetPosition returns the 1-based line and column (in characters) of the byte offset in content.
+func offsetPosition(content []byte, offset int64) (int, int) {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return line, len([]rune(string(before[lineStart:]))) + 1
+}
+
+func (p *jsonldParser) parseTopLevel(ctx *jsonldContext, value any) error {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("top-level JSON-LD values must be objects, found %s", jsonKind(value))
+	}
+	_, err := p.parseNode(ctx, obj)
+	return err
+}
+
+// parseNode emits the triples of a node object and returns the node.
+func (p *jsonldParser) parseNode(ctx *jsonldContext, obj map[string]any) (Term, error) {
+	if local, ok := obj["@context"]; ok {
+		var err error
+		if ctx, err = p.processContext(ctx, local); err != nil {
+			return Term{}, err
+		}
+	}
+	obj = ctx.resolveAliases(obj)
+
+	var subject Term
+	if id, ok := obj["@id"]; ok {
+		s, ok := id.(string)
+		if !ok {
+			return Term{}, fmt.Errorf("@id must be a string, found %s", jsonKind(id))
+		}
+		subject = p.nodeTerm(ctx.expandIRI(s, true, false))
+	} else {
+		subject = p.newBlank()
+	}
+
+	// keys are visited in order so that the triples and blank node labels do not depend on map iteration
+	for _, key := range sortedKeys(obj) {
+		value := obj[key]
+		switch key {
+		case "@context", "@id", "@index":
+		case "@type":
+			types, err := stringValues(key, value)
+			if err != nil {
+				return Term{}, err
+			}
+			for _, t := range types {
+				p.emit(Triple{Subject: subject, Predicate: IRI(RDFType), Object: p.nodeTerm(ctx.expandIRI(t, true, true))})
+			}
+		case "@graph", "@included":
+			for _, item := range asArray(value) {
+				if err := p.parseTopLevel(ctx, item); err != nil {
+					return Term{}, err
+				}
+			}
+		case "@reverse":
+			reverse, ok := value.(map[string]any)
+			if !ok {
+				return Term{}, fmt.Errorf("@reverse must be an object, found %s", jsonKind(value))
+			}
+			for _, prop := range sortedKeys(reverse) {
+				if err := p.parseProperty(ctx, subject, prop, reverse[prop], true); err != nil {
+					return Term{}, err
+				}
+			}
+		default:
+			if strings.HasPrefix(key, "@") {
+				continue
+			}
+			if err := p.parseProperty(ctx, subject, key, value, false); err != nil {
+				return Term{}, err
+			}
+		}
+	}
+
+	return subject, nil
+}
+
+func (p *jsonldParser) parseProperty(ctx *jsonldContext, subject Term, key string, value any, reverse bool) error {
+	term := ctx.terms[key]
+	predicate := ctx.expandIRI(key, false, true)
+	// properties that do not expand to an absolute IRI are dropped, as in JSON-LD expansion
+	if predicate == "" || strings.HasPrefix(predicate, "_:") || !strings.Contains(predicate, ":") {
+		return nil
+	}
+	if term.reverse {
+		reverse = !reverse
+	}
+
+	objects, err := p.parseValues(ctx, term, value)
+	if err != nil {
+		return fmt.Errorf("invalid value of '%s': %w", key, err)
+	}
+	for _, object := range objects {
+		if reverse {
+			p.emit(Triple{Subject: object, Predicate: IRI(predicate), Object: subject})
+		} else {
+			p.emit(Triple{Subject: subject, Predicate: IRI(predicate), Object: object})
+		}
+	}
+	return nil
+}
+
+// parseValues returns the RDF terms of a property value, emitting the triples of embedded nodes and lists.
+func (p *jsonldParser) parseValues(ctx *jsonldContext, term jsonldTerm, value any) ([]Term, error) {
+	if term.container == "@language" {
+		if languages, ok := value.(map[string]any); ok {
+			var objects []Term
+			for _, lang := range sortedKeys(languages) {
+				for _, item := range asArray(languages[lang]) {
+					s, ok := item.(string)
+					if !ok {
+						return nil, fmt.Errorf("language map values must be strings, found %s", jsonKind(item))
+					}
+					objects = append(objects, Literal(s, "", lang))
+				}
+			}
+			return objects, nil
+		}
+	}
+
+	if items, ok := value.([]any); ok {
+		if term.container == "@list" {
+			head, err := p.parseList(ctx, term, items)
+			if err != nil {
+				return nil, err
+			}
+			return []Term{head}, nil
+		}
+		var objects []Term
+		for _, item := range items {
+			itemObjects, err := p.parseValues(ctx, term, item)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, itemObjects...)
+		}
+		return objects, nil
+	}
+
+	if term.container == "@list" {
+		if obj, ok := value.(map[string]any); !ok || obj["@list"] == nil {
+			head, err := p.parseList(ctx, term, []any{value})
+			if err != nil {
+				return nil, err
+			}
+			return []Term{head}, nil
+		}
+	}
+
+	object, ok, err := p.parseValue(ctx, term, value)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return []Term{object}, nil
+}
+
+// parseValue returns the RDF term of a single value. It reports false for null values.
+func (p *jsonldParser) parseValue(ctx *jsonldContext, term jsonldTerm, value any) (Term, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return Term{}, false, nil
+	case string:
+		switch term.typ {
+		case "@id":
+			return p.nodeTerm(ctx.expandIRI(v, true, false)), true, nil
+		case "@vocab":
+			return p.nodeTerm(ctx.expandIRI(v, true, true)), true, nil
+		case "":
+			lang := ctx.language
+			if term.hasLanguage {
+				lang = term.language
+			}
+			return Literal(v, "", lang), true, nil
+		default:
+			return Literal(v, term.typ, ""), true, nil
+		}
+	case bool, json.Number:
+		return jsonScalarLiteral(v, term.typ), true, nil
+	case map[string]any:
+		v = ctx.resolveAliases(v)
+		if list, ok := v["@list"]; ok {
+			head, err := p.parseList(ctx, term, asArray(list))
+			return head, err == nil, err
+		}
+		if set, ok := v["@set"]; ok {
+			objects, err := p.parseValues(ctx, jsonldTerm{typ: term.typ, language: term.language, hasLanguage: term.hasLanguage}, set)
+			if err != nil || len(objects) == 0 {
+				return Term{}, false, err
+			}
+			if len(objects) > 1 {
+				return Term{}, false, fmt.Errorf("@set values with more than one item are only supported directly as property values")
+			}
+			return objects[0], true, nil
+		}
+		if _, ok := v["@value"]; ok {
+			return p.parseValueObject(ctx, v)
+		}
+		node, err := p.parseNode(ctx, v)
+		return node, err == nil, err
+	default:
+		return Term{}, false, fmt.Errorf("unexpected %s", jsonKind(value))
+	}
+}
+
+func (p *jsonldParser) parseValueObject(ctx *jsonldContext, obj map[string]any) (Term, bool, error) {
+	datatype := ""
+	if t, ok := obj["@type"]; ok {
+		s, ok := t.(string)
+		if !ok {
+			return Term{}, false, fmt.Errorf("@type of a value object must be a string, found %s", jsonKind(t))
+		}
+		datatype = ctx.expandIRI(s, true, true)
+	}
+
+	switch v := obj["@value"].(type) {
+	case nil:
+		return Term{}, false, nil
+	case string:
+		if lang, ok := obj["@language"].(string); ok {
+			return Literal(v, "", lang), true, nil
+		}
+		return Literal(v, datatype, ""), true, nil
+	case bool, json.Number:
+		return jsonScalarLiteral(v, datatype), true, nil
+	default:
+		return Term{}, false, fmt.Errorf("@value must be a string, number or boolean, found %s", jsonKind(v))
+	}
+}
+
+// jsonScalarLiteral converts a JSON boolean or number to a literal, following the JSON-LD to RDF algorithm.
+func jsonScalarLiteral(value any, datatype string) Term {
+	switch v := value.(type) {
+	case bool:
+		if datatype == "" || datatype == "@id" || datatype == "@vocab" {
+			datatype = XSDBoolean
+		}
+		return Literal(strconv.FormatBool(v), datatype, "")
+	case json.Number:
+		s := v.String()
+		isInteger := !strings.ContainsAny(s, ".eE")
+		if datatype == "" || datatype == "@id" || datatype == "@vocab" {
+			datatype = XSDInteger
+			if !isInteger {
+				datatype = XSDDouble
+			}
+		}
+		if datatype == XSDDouble || (datatype != XSDInteger && !isInteger) {
+			if f, err := v.Float64(); err == nil {
+				s = canonicalDouble(f)
+			}
+		}
+		return Literal(s, datatype, "")
+	}
+	return Literal(fmt.Sprint(value), datatype, "")
+}
+
+// canonicalDouble returns the canonical xsd:double lexical form of f (e.g. "1.5E1").
+func canonicalDouble(f float64) string {
+	s := strconv.FormatFloat(f, 'E', -1, 64)
+	mantissa, exponent, _ := strings.Cut(s, "E")
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	exp, _ := strconv.Atoi(exponent)
+	return mantissa + "E" + strconv.Itoa(exp)
+}
+
+func (p *jsonldParser) parseList(ctx *jsonldContext, term jsonldTerm, items []any) (Term, error) {
+	term.container = ""
+	var objects []Term
+	for _, item := range items {
+		itemObjects, err := p.parseValues(ctx, term, item)
+		if err != nil {
+			return Term{}, err
+		}
+		objects = append(objects, itemObjects...)
+	}
+
+	if len(objects) == 0 {
+		return IRI(RDFNil), nil
+	}
+
+	head := p.newBlank()
+	current := head
+	for i, object := range objects {
+		p.emit(Triple{Subject: current, Predicate: IRI(RDFFirst), Object: object})
+		rest := IRI(RDFNil)
+		if i < len(objects)-1 {
+			rest = p.newBlank()
+		}
+		p.emit(Triple{Subject: current, Predicate: IRI(RDFRest), Object: rest})
+		current = rest
+	}
+	return head, nil
+}
+
+func (p *jsonldParser) newBlank() Term {
+	p.blankCount++
+	return Blank(fmt.Sprintf("b%d", p.blankCount))
+}
+
+// nodeTerm returns the IRI or blank node identified by an expanded identifier.
+func (p *jsonldParser) nodeTerm(id string) Term {
+	label, ok := strings.CutPrefix(id, "_:")
+	if !ok {
+		return IRI(id)
+	}
+	blank, ok := p.blanks[label]
+	if !ok {
+		blank = p.newBlank().Value
+		p.blanks[label] = blank
+	}
+	return Blank(blank)
+}
+
+// processContext returns the context resulting from applying a local context to the active one.
+func (p *jsonldParser) processContext(active *jsonldContext, local any) (*jsonldContext, error) {
+	switch local := local.(type) {
+	case nil:
+		return &jsonldContext{base: active.base, terms: map[string]jsonldTerm{}}, nil
+	case string:
+		return nil, fmt.Errorf("remote context '%s' is not supported, embed the context in the document", local)
+	case []any:
+		ctx := active
+		for _, item := range local {
+			var err error
+			if ctx, err = p.processContext(ctx, item); err != nil {
+				return nil, err
+			}
+		}
+		return ctx, nil
+	case map[string]any:
+		ctx := active.clone()
+		if base, ok := local["@base"]; ok {
+			switch base := base.(type) {
+			case nil:
+				ctx.base = ""
+			case string:
+				ctx.base = resolveIRI(ctx.base, base)
+			default:
+				return nil, fmt.Errorf("@base must be a string, found %s", jsonKind(base))
+			}
+		}
+		if vocab, ok := local["@vocab"]; ok {
+			switch vocab := vocab.(type) {
+			case nil:
+				ctx.vocab = ""
+			case string:
+				ctx.vocab = ctx.expandIRI(vocab, true, true)
+			default:
+				return nil, fmt.Errorf("@vocab must be a string, found %s", jsonKind(vocab))
+			}
+		}
+		if lang, ok := local["@language"]; ok {
+			switch lang := lang.(type) {
+			case nil:
+				ctx.language = ""
+			case string:
+				ctx.language = lang
+			default:
+				return nil, fmt.Errorf("@language must be a string, found %s", jsonKind(lang))
+			}
+		}
+		if _, ok := local["@import"]; ok {
+			return nil, fmt.Errorf("@import is not supported, embed the context in the document")
+		}
+
+		defined := map[string]bool{}
+		for _, term := range sortedKeys(local) {
+			if strings.HasPrefix(term, "@") {
+				continue
+			}
+			if err := p.defineTerm(ctx, local, term, defined); err != nil {
+				return nil, err
+			}
+		}
+		return ctx, nil
+	default:
+		return nil, fmt.Errorf("@context must be an object, array or string, found %s", jsonKind(local))
+	}
+}
+
+// defineTerm creates the definition of term from the local context, defining the terms it depends on first.
+func (p *jsonldParser) defineTerm(ctx *jsonldContext, local map[string]any, term string, defined map[string]bool) error {
+	if done, ok := defined[term]; ok {
+		if !done {
+			return fmt.Errorf("cyclic definition of term '%s'", term)
+		}
+		return nil
+	}
+	defined[term] = false
+
+	// expand IRIs of the definition, creating the definitions of the local terms they reference first
+	expand := func(value string, vocab bool) (string, error) {
+		dependency := value
+		if prefix, _, ok := strings.Cut(value, ":"); ok {
+			dependency = prefix
+		}
+		if _, ok := local[dependency]; ok && dependency != term {
+			if err := p.defineTerm(ctx, local, dependency, defined); err != nil {
+				return "", err
+			}
+		}
+		return ctx.expandIRI(value, !vocab, vocab), nil
+	}
+
+	def := jsonldTerm{}
+	var id any
+	hasID := false
+	switch value := local[term].(type) {
+	case nil:
+		ctx.terms[term] = def
+		defined[term] = true
+		return nil
+	case string:
+		id, hasID = value, true
+	case map[string]any:
+		if reverse, ok := value["@reverse"]; ok {
+			id, hasID = reverse, true
+			def.reverse = true
+		} else {
+			id, hasID = value["@id"]
+		}
+		if t, ok := value["@type"]; ok {
+			s, ok := t.(string)
+			if !ok {
+				return fmt.Errorf("@type of term '%s' must be a string, found %s", term, jsonKind(t))
+			}
+			if s == "@id" || s == "@vocab" {
+				def.typ = s
+			} else {
+				expanded, err := expand(s, true)
+				if err != nil {
+					return err
+				}
+				def.typ = expanded
+			}
+		}
+		if container, ok := value["@container"]; ok {
+			containers, err := stringValues("@container", container)
+			if err != nil {
+				return fmt.Errorf("invalid definition of term '%s': %w", term, err)
+			}
+			for _, c := range containers {
+				if c != "@set" || def.container == "" {
+					def.container = c
+				}
+			}
+		}
+		if lang, ok := value["@language"]; ok {
+			s, _ := lang.(string)
+			def.language, def.hasLanguage = s, true
+		}
+	default:
+		return fmt.Errorf("definition of term '%s' must be a string or an object, found %s", term, jsonKind(value))
+	}
+
+	switch {
+	case hasID && id == nil:
+		// explicitly mapped to null: the term is ignored
+	case hasID:
+		s, ok := id.(string)
+		if !ok {
+			return fmt.Errorf("@id of term '%s' must be a string, found %s", term, jsonKind(id))
+		}
+		expanded, err := expand(s, true)
+		if err != nil {
+			return err
+		}
+		def.id = expanded
+	case strings.Contains(term, ":"):
+		expanded, err := expand(term, true)
+		if err != nil {
+			return err
+		}
+		def.id = expanded
+	case ctx.vocab != "":
+		def.id = ctx.vocab + term
+	default:
+		return fmt.Errorf("term '%s' has no IRI mapping and no @vocab is defined", term)
+	}
+
+	ctx.terms[term] = def
+	defined[term] = true
+
+	// terms mapped to a namespace are kept as Turtle prefixes
+	if !strings.Contains(term, ":") && (strings.HasSuffix(def.id, "/") || strings.HasSuffix(def.id, "#")) {
+		if _, ok := p.prefixes[term]; !ok {
+			p.prefixes[term] = def.id
+		}
+	}
+	return nil
+}
+
+// resolveAliases returns obj with the keys that are aliases of keywords (e.g. "id": "@id") replaced by the
+// keywords themselves.
+func (c *jsonldContext) resolveAliases(obj map[string]any) map[string]any {
+	var resolved map[string]any
+	for key, value := range obj {
+		term, ok := c.terms[key]
+		if !ok || !strings.HasPrefix(term.id, "@") {
+			continue
+		}
+		if resolved == nil {
+			resolved = make(map[string]any, len(obj))
+			for k, v := range obj {
+				resolved[k] = v
+			}
+		}
+		delete(resolved, key)
+		resolved[term.id] = value
+	}
+	if resolved == nil {
+		return obj
+	}
+	return resolved
+}
+
+// expandIRI expands a term, compact IRI or relative IRI. With vocab set, terms and @vocab are used; with
+// documentRelative set, relative IRIs are resolved against the base IRI.
+func (c *jsonldContext) expandIRI(value string, documentRelative, vocab bool) string {
+	if strings.HasPrefix(value, "@") {
+		return value
+	}
+	if vocab {
+		if term, ok := c.terms[value]; ok {
+			return term.id
+		}
+	}
+	if prefix, suffix, ok := strings.Cut(value, ":"); ok {
+		if prefix == "_" || strings.HasPrefix(suffix, "//") {
+			return value
+		}
+		if term, ok := c.terms[prefix]; ok && term.id != "" {
+			return term.id + suffix
+		}
+		return value
+	}
+	if vocab && c.vocab != "" {
+		return c.vocab + value
+	}
+	if documentRelative {
+		return resolveIRI(c.base, value)
+	}
+	return value
+}
+
+// resolveIRI resolves a relative IRI reference against base. The reference is returned unchanged when base
+// is empty or either IRI cannot be parsed.
+func resolveIRI(base, ref string) string {
+	if base == "" {
+		return ref
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil || r.IsAbs() {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
+
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func asArray(value any) []any {
+	if items, ok := value.([]any); ok {
+		return items
+	}
+	return []any{value}
+}
+
+// stringValues returns value as a list of strings, accepting either a string or an array of strings.
+func stringValues(key string, value any) ([]string, error) {
+	var values []string
+	for _, item := range asArray(value) {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string or an array of strings, found %s", key, jsonKind(item))
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+func jsonKind(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
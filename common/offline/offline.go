@@ -0,0 +1,123 @@
+// Package offline embeds copies of the example datasets and base ontologies that environments are initialized
+// and populated with, so that this works without access to GitHub.
+//
+// The sources are listed with their pinned revisions in sources.yaml, and their copies are kept in data/.
+// Both are updated with "make offline-data".
+package offline
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed sources.yaml
+var manifest []byte
+
+//go:embed data
+var dataFS embed.FS
+
+// DataDir is the directory, relative to this package, holding the copies of the sources.
+const DataDir = "data"
+
+// Source is an upstream file embedded in the binary.
+type Source struct {
+	// Name of the example dataset or ontology
+	Name string `yaml:"name"`
+	// Ontology type registered in the ingestor (BASE or MAPPING). Empty for examples
+	Type string `yaml:"type,omitempty"`
+	// GitHub repository of the file, as owner/name
+	Repository string `yaml:"repository"`
+	// Branch the revision is pinned from
+	Branch string `yaml:"branch"`
+	// Pinned commit of the copy. Empty until the source is pinned
+	Revision string `yaml:"revision,omitempty"`
+	// Path of the file in the repository
+	Path string `yaml:"path"`
+	// Path of the copy, relative to DataDir
+	File string `yaml:"file"`
+	// Hex encoded SHA-256 of the copy. Empty until the source is pinned
+	SHA256 string `yaml:"sha256,omitempty"`
+}
+
+// Manifest lists the embedded sources.
+type Manifest struct {
+	Ontologies []Source `yaml:"ontologies"`
+	Examples   []Source `yaml:"examples"`
+}
+
+// Sources returns the manifest of the embedded sources.
+func Sources() Manifest {
+	m, err := ParseManifest(manifest)
+	if err != nil {
+		panic(fmt.Errorf("error parsing embedded sources manifest: %w", err))
+	}
+	return m
+}
+
+// ParseManifest parses and checks a sources manifest.
+func ParseManifest(data []byte) (Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return Manifest{}, err
+	}
+
+	names := map[string]bool{}
+	for _, s := range append(append([]Source{}, m.Ontologies...), m.Examples...) {
+		if s.Name == "" || s.Repository == "" || s.Branch == "" || s.Path == "" || s.File == "" {
+			return Manifest{}, fmt.Errorf("source %q is missing a name, repository, branch, path or file", s.Name)
+		}
+		if names[s.Name] {
+			return Manifest{}, fmt.Errorf("duplicate source %q", s.Name)
+		}
+		names[s.Name] = true
+	}
+	return m, nil
+}
+
+// Pinned reports whether the source has a pinned revision and checksum, and therefore an embedded copy.
+func (s Source) Pinned() bool {
+	return s.Revision != "" && s.SHA256 != ""
+}
+
+// RemoteURL returns the URL of the file at its pinned revision, or at the head of its branch when the source
+// is not pinned.
+func (s Source) RemoteURL() string {
+	ref := s.Revision
+	if ref == "" {
+		ref = s.Branch
+	}
+	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", s.Repository, ref, s.Path)
+}
+
+// Content returns the embedded copy of the source, after checking it against the pinned checksum.
+func (s Source) Content() ([]byte, error) {
+	if !s.Pinned() {
+		return nil, fmt.Errorf("no offline copy of %s is embedded, run 'make offline-data' to download it", s.Name)
+	}
+
+	content, err := dataFS.ReadFile(path.Join(DataDir, s.File))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("the offline copy of %s is missing, run 'make offline-data' to download it", s.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading the offline copy of %s: %w", s.Name, err)
+	}
+
+	if sum := Checksum(content); sum != s.SHA256 {
+		return nil, fmt.Errorf("the offline copy of %s does not match its pinned checksum: got %s, expected %s", s.Name, sum, s.SHA256)
+	}
+	return content, nil
+}
+
+// Checksum returns the hex encoded SHA-256 of content, as recorded in the manifest.
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,104 @@
+package offline
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  bool
+	}{
+		{
+			name: "valid manifest",
+			manifest: `
+ontologies:
+  - {name: A, type: BASE, repository: o/r, branch: main, path: a.ttl, file: ontologies/a.ttl}
+examples:
+  - {name: B, repository: o/r, branch: main, revision: abc, path: b.ttl, file: examples/b.ttl, sha256: "00"}
+`,
+		},
+		{
+			name:     "missing file",
+			manifest: "examples:\n  - {name: B, repository: o/r, branch: main, path: b.ttl}\n",
+			wantErr:  true,
+		},
+		{
+			name: "duplicate name",
+			manifest: `
+ontologies:
+  - {name: A, repository: o/r, branch: main, path: a.ttl, file: a.ttl}
+examples:
+  - {name: A, repository: o/r, branch: main, path: b.ttl, file: b.ttl}
+`,
+			wantErr: true,
+		},
+		{name: "malformed", manifest: "ontologies: {", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ParseManifest([]byte(tc.manifest)); (err != nil) != tc.wantErr {
+				t.Errorf("Expected error %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSourceRemoteURL(t *testing.T) {
+	t.Parallel()
+
+	src := Source{Repository: "epos-eu/EPOS-DCAT-AP", Branch: "main", Path: "docs/shapes.ttl"}
+	if got, expected := src.RemoteURL(), "https://raw.githubusercontent.com/epos-eu/EPOS-DCAT-AP/main/docs/shapes.ttl"; got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+
+	src.Revision = "0123abc"
+	if got, expected := src.RemoteURL(), "https://raw.githubusercontent.com/epos-eu/EPOS-DCAT-AP/0123abc/docs/shapes.ttl"; got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestEmbeddedSources(t *testing.T) {
+	t.Parallel()
+
+	m := Sources()
+
+	var names []string
+	for _, ont := range m.Ontologies {
+		names = append(names, ont.Name)
+		if ont.Type != "BASE" && ont.Type != "MAPPING" {
+			t.Errorf("Expected ontology %s to be of type BASE or MAPPING, got %q", ont.Name, ont.Type)
+		}
+	}
+	for _, expected := range []string{"EPOS-DCAT-AP-V1", "EPOS-DCAT-AP-V3", "EDM-TO-DCAT-AP"} {
+		if !slices.Contains(names, expected) {
+			t.Errorf("Expected ontology %s in the embedded manifest", expected)
+		}
+	}
+	if len(m.Examples) == 0 {
+		t.Error("Expected examples in the embedded manifest")
+	}
+
+	// every source must be embedded, otherwise environments silently depend on GitHub again
+	for _, src := range append(m.Ontologies, m.Examples...) {
+		if !src.Pinned() {
+			t.Errorf("Expected %s to be pinned to a revision and checksum, run 'make offline-data'", src.Name)
+			continue
+		}
+		content, err := src.Content()
+		if err != nil {
+			t.Errorf("Expected the embedded copy of %s to be readable: %v", src.Name, err)
+			continue
+		}
+		if sum := Checksum(content); sum != src.SHA256 {
+			t.Errorf("Expected the embedded copy of %s to have checksum %s, got %s", src.Name, src.SHA256, sum)
+		}
+	}
+}
@@ -1,23 +1,136 @@
 package common
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"time"
 
+	"github.com/EPOS-ERIC/epos-opensource/common/offline"
 	"github.com/EPOS-ERIC/epos-opensource/display"
 )
 
-type ontology struct {
-	path    string
-	name    string
-	ontType string
+// Ontology types registered in the ingestor.
+const (
+	// OntologyTypeBase is the type of the ontologies defining a metadata model
+	OntologyTypeBase = "BASE"
+	// OntologyTypeMapping is the type of the ontologies mapping a metadata model to the EPOS data model
+	OntologyTypeMapping = "MAPPING"
+)
+
+// validOntologyName restricts ontology names to characters that are safe in URLs and in the shell script of
+// the k8s ontology populator job.
+var validOntologyName = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Ontology is an ontology registered in the ingestor when an environment is initialized.
+type Ontology struct {
+	// Name the ontology is registered with, used as the model or mapping of the ingested files
+	Name string `yaml:"name"`
+	// Type of the ontology, BASE or MAPPING
+	Type string `yaml:"type"`
+	// URL the ingestor fetches the ontology from. When empty the copy embedded in the binary with the same name
+	// is used
+	URL string `yaml:"url,omitempty"`
+}
+
+// Embedded reports whether the ontology is loaded from a copy embedded in the binary.
+func (o Ontology) Embedded() bool {
+	return o.URL == ""
+}
+
+// SourceURL returns the URL the ingestor fetches the ontology from when it is not uploaded: URL, or the
+// upstream URL of the embedded copy.
+func (o Ontology) SourceURL() string {
+	if o.URL != "" {
+		return o.URL
+	}
+	if src, ok := embeddedOntology(o.Name); ok {
+		return src.RemoteURL()
+	}
+	return ""
+}
+
+// DefaultOntologies returns the ontologies registered when an environment does not configure any: the
+// ontologies embedded in the binary.
+func DefaultOntologies() []Ontology {
+	var ontologies []Ontology
+	for _, src := range offline.Sources().Ontologies {
+		ontologies = append(ontologies, Ontology{Name: src.Name, Type: src.Type})
+	}
+	return ontologies
+}
+
+// OntologiesOrDefault returns ontologies, or DefaultOntologies when none are configured.
+func OntologiesOrDefault(ontologies []Ontology) []Ontology {
+	if len(ontologies) == 0 {
+		return DefaultOntologies()
+	}
+	return ontologies
+}
+
+// ValidateOntology checks that the ontology has a name made of letters, digits, '.', '_' and '-', a valid type,
+// and either an http(s) URL or the name of an embedded ontology.
+func ValidateOntology(o Ontology) error {
+	if o.Name == "" {
+		return fmt.Errorf("ontology name is required")
+	}
+	if !validOntologyName.MatchString(o.Name) {
+		return fmt.Errorf("ontology name %q is invalid, only letters, digits, '.', '_' and '-' are allowed", o.Name)
+	}
+	if o.Type != OntologyTypeBase && o.Type != OntologyTypeMapping {
+		return fmt.Errorf("ontology %s type must be %s or %s", o.Name, OntologyTypeBase, OntologyTypeMapping)
+	}
+	if o.URL == "" {
+		if _, ok := embeddedOntology(o.Name); !ok {
+			return fmt.Errorf("ontology %s url is required, it is not one of the embedded ontologies", o.Name)
+		}
+		return nil
+	}
+
+	u, err := url.Parse(o.URL)
+	if err != nil {
+		return fmt.Errorf("ontology %s url is invalid: %w", o.Name, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("ontology %s url must be an absolute http or https url", o.Name)
+	}
+	return nil
+}
+
+// ValidateOntologies checks every ontology with ValidateOntology and that their names are unique. An empty
+// list is valid and stands for DefaultOntologies.
+func ValidateOntologies(ontologies []Ontology) error {
+	names := map[string]bool{}
+	for _, o := range ontologies {
+		if err := ValidateOntology(o); err != nil {
+			return err
+		}
+		if names[o.Name] {
+			return fmt.Errorf("ontology %s is configured more than once", o.Name)
+		}
+		names[o.Name] = true
+	}
+	return nil
 }
 
-// PopulateOntologies populates an environment deployed in a dir with the base ontologies for the ingestor
-func PopulateOntologies(baseURL string) error {
+// embeddedOntology returns the embedded ontology called name.
+func embeddedOntology(name string) (offline.Source, bool) {
+	for _, src := range offline.Sources().Ontologies {
+		if src.Name == name {
+			return src, true
+		}
+	}
+	return offline.Source{}, false
+}
+
+// PopulateOntologies registers the given ontologies in the ingestor of an environment, or DefaultOntologies
+// when none are given. Ontologies with a URL are fetched by the ingestor. The others are uploaded from the
+// copies embedded in the binary, and an error is returned when the binary has no copy of one of them; when
+// remote is set the ingestor fetches them from GitHub instead.
+func PopulateOntologies(baseURL string, ontologies []Ontology, remote bool) error {
 	httpClient := &http.Client{
 		Timeout: 1 * time.Minute,
 	}
@@ -28,22 +141,17 @@ func PopulateOntologies(baseURL string) error {
 	}
 	apiURL = apiURL.JoinPath("ontology")
 
-	ontologies := []ontology{
-		{
-			path:    "https://raw.githubusercontent.com/epos-eu/EPOS-DCAT-AP/EPOS-DCAT-AP-shapes/epos-dcat-ap_shapes.ttl",
-			name:    "EPOS-DCAT-AP-V1",
-			ontType: "BASE",
-		},
-		{
-			path:    "https://raw.githubusercontent.com/epos-eu/EPOS-DCAT-AP/EPOS-DCAT-AP-v3.0/docs/epos-dcat-ap_v3.0.0_shacl.ttl",
-			name:    "EPOS-DCAT-AP-V3",
-			ontType: "BASE",
-		},
-		{
-			path:    "https://raw.githubusercontent.com/epos-eu/EPOS_Data_Model_Mapping/main/edm-schema-shapes.ttl",
-			name:    "EDM-TO-DCAT-AP",
-			ontType: "MAPPING",
-		},
+	ontologies = OntologiesOrDefault(ontologies)
+	if err := ValidateOntologies(ontologies); err != nil {
+		return fmt.Errorf("invalid ontologies: %w", err)
+	}
+
+	if !remote {
+		for _, ont := range ontologies {
+			if src, ok := embeddedOntology(ont.Name); ok && ont.Embedded() && !src.Pinned() {
+				return fmt.Errorf("no offline copy of the %s ontology is embedded, run 'make offline-data' before building or fetch the ontologies from GitHub", ont.Name)
+			}
+		}
 	}
 
 	display.Step("Populating the environment with base ontologies")
@@ -51,24 +159,37 @@ func PopulateOntologies(baseURL string) error {
 	for i, ont := range ontologies {
 		reqURL := *apiURL
 		q := reqURL.Query()
-		q.Set("path", ont.path)
-		q.Set("name", ont.name)
-		q.Set("type", ont.ontType)
+		q.Set("name", ont.Name)
+		q.Set("type", ont.Type)
+
+		var content []byte
+		if ont.Embedded() && !remote {
+			src, _ := embeddedOntology(ont.Name)
+			if content, err = src.Content(); err != nil {
+				return fmt.Errorf("error loading the %s ontology: %w", ont.Name, err)
+			}
+		}
+		if content == nil {
+			q.Set("path", ont.SourceURL())
+		}
 		reqURL.RawQuery = q.Encode()
 
-		display.Step("  [%d/3] Loading %s ontology...", i+1, ont.name)
+		display.Step("  [%d/%d] Loading %s ontology...", i+1, len(ontologies), ont.Name)
 
-		req, err := http.NewRequest("POST", reqURL.String(), nil)
+		req, err := http.NewRequest("POST", reqURL.String(), bytes.NewReader(content))
 		if err != nil {
-			return fmt.Errorf("error creating ontology request for %s: %w", ont.name, err)
+			return fmt.Errorf("error creating ontology request for %s: %w", ont.Name, err)
 		}
 		req.Header.Set("Accept", "*/*")
+		if content != nil {
+			req.Header.Set("Content-Type", "text/turtle")
+		}
 
 		display.Debug("sending request: %s", req.URL.String())
 
 		resp, err := httpClient.Do(req)
 		if err != nil {
-			return fmt.Errorf("error making ontology request for %s: %w", ont.name, err)
+			return fmt.Errorf("error making ontology request for %s: %w", ont.Name, err)
 		}
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
@@ -79,7 +200,7 @@ func PopulateOntologies(baseURL string) error {
 			return fmt.Errorf("failed to close body: %w", err)
 		}
 		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("ontology request for %s failed with status %d: %s", ont.name, resp.StatusCode, string(body))
+			return fmt.Errorf("ontology request for %s failed with status %d: %s", ont.Name, resp.StatusCode, string(body))
 		}
 	}
 	display.Done("All ontologies loaded successfully")
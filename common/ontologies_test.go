@@ -0,0 +1,122 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestValidateOntologies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		ontologies []Ontology
+		wantErr    bool
+	}{
+		{name: "empty uses defaults", ontologies: nil},
+		{name: "defaults", ontologies: DefaultOntologies()},
+		{name: "custom url", ontologies: []Ontology{{Name: "MY-SHAPES", Type: OntologyTypeBase, URL: "https://example.com/shapes.ttl"}}},
+		{name: "missing name", ontologies: []Ontology{{Type: OntologyTypeBase, URL: "https://example.com/shapes.ttl"}}, wantErr: true},
+		{name: "name with quote", ontologies: []Ontology{{Name: "MY'SHAPES", Type: OntologyTypeBase, URL: "https://example.com/shapes.ttl"}}, wantErr: true},
+		{name: "name with query characters", ontologies: []Ontology{{Name: "MY&SHAPES=1", Type: OntologyTypeBase, URL: "https://example.com/shapes.ttl"}}, wantErr: true},
+		{name: "invalid type", ontologies: []Ontology{{Name: "MY-SHAPES", Type: "SHAPES", URL: "https://example.com/shapes.ttl"}}, wantErr: true},
+		{name: "unknown embedded name", ontologies: []Ontology{{Name: "MY-SHAPES", Type: OntologyTypeBase}}, wantErr: true},
+		{name: "relative url", ontologies: []Ontology{{Name: "MY-SHAPES", Type: OntologyTypeBase, URL: "shapes.ttl"}}, wantErr: true},
+		{name: "file url", ontologies: []Ontology{{Name: "MY-SHAPES", Type: OntologyTypeBase, URL: "file:///shapes.ttl"}}, wantErr: true},
+		{name: "duplicate name", ontologies: append(DefaultOntologies(), Ontology{Name: "EPOS-DCAT-AP-V1", Type: OntologyTypeBase, URL: "https://example.com/fork.ttl"}), wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := ValidateOntologies(tc.ontologies); (err != nil) != tc.wantErr {
+				t.Errorf("Expected error %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPopulateOntologies(t *testing.T) {
+	t.Parallel()
+
+	custom := Ontology{Name: "MY-SHAPES", Type: OntologyTypeBase, URL: "https://example.com/shapes.ttl?ref=v1&x=y"}
+
+	tests := []struct {
+		name          string
+		ontologies    []Ontology
+		remote        bool
+		status        int
+		expectErr     bool
+		expectedNames []string
+	}{
+		{
+			name:          "defaults",
+			status:        http.StatusOK,
+			expectedNames: []string{"EPOS-DCAT-AP-V1", "EPOS-DCAT-AP-V3", "EDM-TO-DCAT-AP"},
+		},
+		{
+			name:          "defaults remote",
+			remote:        true,
+			status:        http.StatusOK,
+			expectedNames: []string{"EPOS-DCAT-AP-V1", "EPOS-DCAT-AP-V3", "EDM-TO-DCAT-AP"},
+		},
+		{
+			name:          "custom url",
+			ontologies:    []Ontology{custom},
+			status:        http.StatusOK,
+			expectedNames: []string{"MY-SHAPES"},
+		},
+		{
+			name:          "ingestor failure",
+			ontologies:    []Ontology{custom},
+			status:        http.StatusInternalServerError,
+			expectErr:     true,
+			expectedNames: []string{"MY-SHAPES"},
+		},
+		{
+			name:       "invalid ontology",
+			ontologies: []Ontology{{Name: "MY-SHAPES", Type: OntologyTypeBase}},
+			status:     http.StatusOK,
+			expectErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var names []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				mu.Lock()
+				names = append(names, q.Get("name"))
+				mu.Unlock()
+
+				if r.URL.Path != "/ontology" {
+					t.Errorf("Expected request to /ontology, got %s", r.URL.Path)
+				}
+				if q.Get("name") == custom.Name && q.Get("path") != custom.URL {
+					t.Errorf("Expected path %s, got %s", custom.URL, q.Get("path"))
+				}
+				if tc.remote && q.Get("path") == "" {
+					t.Errorf("Expected a path for %s with remote set", q.Get("name"))
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			err := PopulateOntologies(server.URL, tc.ontologies, tc.remote)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("Expected error %v, got: %v", tc.expectErr, err)
+			}
+			if !slices.Equal(names, tc.expectedNames) {
+				t.Errorf("Expected ontologies %v, got %v", tc.expectedNames, names)
+			}
+		})
+	}
+}
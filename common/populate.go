@@ -1,7 +1,10 @@
 package common
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -13,136 +16,610 @@ import (
 	"sync"
 	"time"
 
+	"github.com/EPOS-ERIC/epos-opensource/common/offline"
 	"github.com/EPOS-ERIC/epos-opensource/display"
 	"golang.org/x/sync/errgroup"
 )
 
-// PopulateEnv ingests TTL (Turtle) files into an environment by posting them to the gateway endpoint.
+// PopulateEnvOpts defines inputs for PopulateEnv.
+type PopulateEnvOpts struct {
+	// Required. path to an RDF file (Turtle, N-Triples, JSON-LD or RDF/XML) or a directory containing RDF files
+	Path string
+	// Required. base URL of the EPOS gateway (e.g., "http://gateway/api/v1")
+	EndpointURL string
+	// Required. maximum number of concurrent file ingestions (use 1 for sequential processing)
+	Parallel int
+	// Optional. content hashes of previously ingested files keyed by absolute path. When set, files whose
+	// current content hash matches the recorded one are skipped (incremental populate)
+	Ingested map[string]string
+	// Optional. how failed uploads are retried. The zero value disables retries
+	Retry RetryPolicy
+	// Optional. when set, the outcome of every file is recorded in the report
+	Report *PopulateReport
+	// Optional. ingestion type, model and mapping of the files. Empty fields use the defaults, and the
+	// ingestion manifests found in the populated directories override them
+	Ingestion IngestionSettings
+	// Optional. glob patterns selecting the files ingested from a directory. The patterns of the ignore file in
+	// the directory (see IgnoreFileName) are excluded too
+	Filter PathFilter
+	// Optional. upload the valid files even when some files fail the syntax check, and return every failure
+	// instead of the first one. By default nothing is uploaded when any file is invalid
+	KeepGoing bool
+	// Optional. compress the uploaded files with gzip. Files the gateway rejects as an unsupported media type
+	// are sent again uncompressed
+	Compress bool
+}
+
+// IngestedFile describes a file that was successfully posted to an environment.
+type IngestedFile struct {
+	// Absolute path of the ingested file
+	Path string
+	// Hex encoded SHA-256 of the file content, before any conversion to Turtle
+	ContentHash string
+	// Size of the file content in bytes, before any conversion to Turtle
+	SizeBytes int64
+}
+
+// PopulateEnv ingests RDF files into an environment by posting them to the gateway endpoint.
 // It accepts either a single file or a directory path. When given a directory, it recursively walks through
-// all subdirectories and ingests all *.ttl files found, processing them in parallel according to the
-// specified concurrency limit.
+// all subdirectories and ingests all files with an RDF extension (see RDFExtensions), processing them in
+// parallel according to the specified concurrency limit. The format of a single file without a known
+// extension is detected from its content. The files of a directory are selected with opts.Filter and the
+// ignore file of the directory, see PathFilter.
 //
-// On partial failure (e.g., some files fail to ingest), the function returns successfully ingested file paths
-// along with an error indicating the failure. This allows callers to handle partial successes (e.g., save
-// successful ingestions) while still being notified of issues. The returned slice is always non-nil.
+// A zip file or gzip compressed tarball (see IsArchive) is read like a directory: its entries are checked in
+// one read of the archive and then streamed to the gateway from the archive, so that they are never held in
+// memory or extracted to disk. They are returned with paths of the form "archive.zip!/path/inside.ttl" (see
+// ArchiveEntryPath).
 //
-// Parameters:
-//   - ttlPath: Path to a TTL file or directory containing TTL files
-//   - gatewayURL: Base URL of the EPOS gateway (e.g., "http://gateway/api/v1")
-//   - parallel: Maximum number of concurrent file ingestions (use 1 for sequential processing)
+// The gateway only accepts Turtle: JSON-LD and RDF/XML files are converted to Turtle locally before being
+// posted, N-Triples files are posted as they are.
 //
-// Returns a list of successfully ingested file paths and an error if any file fails to ingest or if the path is invalid.
-func PopulateEnv(ttlPath, endpointURL string, parallel int) ([]string, error) {
-	successfulFiles := []string{}
+// Before any file is sent, the syntax of every file is checked locally; if any file is not valid nothing is
+// uploaded and the syntax errors are reported with their file:line:column position. With opts.KeepGoing set
+// the invalid files are left out and the valid ones are still uploaded.
+//
+// Uploads failing with a network error or with one of the retryable status codes of opts.Retry are retried
+// with exponential backoff, honoring the Retry-After header sent by the server.
+//
+// When opts.Ingested is set, every file is hashed before upload and files whose content did not change since
+// the recorded ingestion are skipped. Skipped files are not part of the returned slice.
+//
+// Every file is posted with the ingestion type, model and mapping of opts.Ingestion. An ingestion manifest
+// (see IngestionManifestName) in the populated directory, or in any of its subdirectories, overrides them for
+// the files below it; the manifest next to a single file applies to that file.
+//
+// When opts.Report is set, the status, HTTP status code, gateway error body, duration and size of every
+// file are recorded in it, including the files that fail the syntax check.
+//
+// When ctx is cancelled no further file is started and the requests in flight are aborted. The files that
+// were ingested before are returned along with an error wrapping ctx.Err().
+//
+// On partial failure (e.g., some files fail to ingest), the function returns successfully ingested files
+// along with an error indicating the failure. This allows callers to handle partial successes (e.g., save
+// successful ingestions) while still being notified of issues. The returned slice is always non-nil. With
+// opts.KeepGoing set the error joins the failures of every file; otherwise the first failure aborts the
+// uploads in flight, no further file is started and the error wraps it.
+//
+// Returns a list of successfully ingested files and an error if any file fails to ingest or if the path is invalid.
+func PopulateEnv(ctx context.Context, opts PopulateEnvOpts) ([]IngestedFile, error) {
+	successfulFiles := []IngestedFile{}
 
-	if parallel == 0 {
-		return successfulFiles, fmt.Errorf("invalid parallel value: %d", parallel)
+	if opts.Parallel == 0 {
+		return successfulFiles, fmt.Errorf("invalid parallel value: %d", opts.Parallel)
 	}
 
-	endpointURL = strings.TrimSuffix(endpointURL, "/ui")
+	endpointURL := strings.TrimSuffix(opts.EndpointURL, "/ui")
 	postURL, err := url.Parse(endpointURL)
 	if err != nil {
 		return successfulFiles, fmt.Errorf("invalid endpoint URL '%s': %w", endpointURL, err)
 	}
 	postURL = postURL.JoinPath("/populate")
 
-	absPath, err := filepath.Abs(ttlPath)
+	ttlPath := opts.Path
+	if IsArchive(ttlPath) {
+		absPath, err := filepath.Abs(ttlPath)
+		if err != nil {
+			return successfulFiles, fmt.Errorf("failed to resolve absolute path: %w", err)
+		}
+		return populateArchive(ctx, absPath, *postURL, opts)
+	}
+
+	files, isDir, err := collectRDFFiles(ttlPath, opts.Filter)
 	if err != nil {
-		return successfulFiles, fmt.Errorf("failed to resolve absolute path: %w", err)
+		return successfulFiles, err
 	}
 
-	fi, err := os.Stat(absPath)
+	if len(files) == 0 {
+		display.Warn("No RDF files to ingest in directory '%s'", ttlPath)
+		return successfulFiles, nil
+	}
+
+	root := filepath.Dir(files[0])
+	if isDir {
+		root, _ = filepath.Abs(ttlPath)
+	}
+	settings, err := resolveIngestionSettings(root, files, opts.Ingestion.Inherit(DefaultIngestionSettings()))
 	if err != nil {
-		return successfulFiles, fmt.Errorf("cannot access path '%s': %w", ttlPath, err)
+		return successfulFiles, err
 	}
 
-	if fi.IsDir() {
-		display.Step("Starting ingestion of *.ttl files from directory '%s'", ttlPath)
+	var invalidErrs []error
+	if opts.KeepGoing {
+		files, invalidErrs = dropInvalidRDFFiles(files, opts.Parallel, opts.Report)
+		if len(files) == 0 {
+			return successfulFiles, fmt.Errorf("no valid RDF files to ingest in '%s': %w", ttlPath, errors.Join(invalidErrs...))
+		}
+	} else if err := validateRDFFiles(files, opts.Parallel, opts.Report); err != nil {
+		return successfulFiles, err
+	}
 
-		walkError := false
-		var eg errgroup.Group
-		eg.SetLimit(parallel)
-		var mu sync.Mutex
+	if !isDir {
+		file, changed, err := ingestFile(ctx, files[0], *postURL, settings[files[0]], opts)
+		if err != nil {
+			return successfulFiles, fmt.Errorf("failed to ingest file '%s': %w", filepath.Base(ttlPath), err)
+		}
+		if changed {
+			display.Done("Successfully ingested '%s'", filepath.Base(ttlPath))
+			successfulFiles = append(successfulFiles, file)
+		}
+		return successfulFiles, nil
+	}
 
-		err = filepath.WalkDir(ttlPath, func(path string, d fs.DirEntry, walkErr error) error {
-			if walkErr != nil {
-				display.Error("Failed to access path during directory traversal: %v", walkErr)
-				walkError = true
-				return nil
+	display.Step("Starting ingestion of %d RDF file(s) from directory '%s'", len(files), ttlPath)
+
+	eg, uploadCtx := uploadGroup(ctx, opts)
+	var mu sync.Mutex
+	skipped := 0
+	var failedErrs []error
+
+	for _, path := range files {
+		eg.Go(func() error {
+			if err := uploadCtx.Err(); err != nil {
+				opts.Report.Add(FileResult{Path: path, Status: FileSkipped, Error: notUploadedReason(ctx)})
+				return err
 			}
-			if !strings.HasSuffix(d.Name(), ".ttl") {
+			file, changed, err := ingestFile(uploadCtx, path, *postURL, settings[path], opts)
+			if err != nil && uploadCtx.Err() != nil {
+				display.Warn("Upload of '%s' aborted", filepath.Base(path))
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				display.Error("Failed to ingest '%s': %v", filepath.Base(path), err)
+				failedErrs = append(failedErrs, err)
+				return err
+			}
+			if !changed {
+				skipped++
 				return nil
 			}
+			successfulFiles = append(successfulFiles, file)
+			return nil
+		})
+	}
+	err = eg.Wait()
+	if ctx.Err() != nil {
+		return successfulFiles, fmt.Errorf("ingestion of directory '%s' cancelled after %d of %d file(s): %w", ttlPath, len(successfulFiles)+skipped, len(files), ctx.Err())
+	}
+	if opts.KeepGoing && len(failedErrs)+len(invalidErrs) > 0 {
+		return successfulFiles, fmt.Errorf("%d of %d file(s) failed to ingest in directory '%s': %w", len(failedErrs)+len(invalidErrs), len(files)+len(invalidErrs), ttlPath, errors.Join(append(invalidErrs, failedErrs...)...))
+	}
+	if err != nil {
+		return successfulFiles, fmt.Errorf("one or more files failed to ingest in directory '%s': %w", ttlPath, err)
+	}
+
+	if skipped > 0 {
+		display.Done("Successfully ingested %d RDF file(s) from directory '%s', skipped %d unchanged", len(successfulFiles), ttlPath, skipped)
+	} else {
+		display.Done("Successfully ingested all RDF files from directory '%s'", ttlPath)
+	}
+
+	return successfulFiles, nil
+}
+
+// uploadGroup returns the group the files of a directory or archive are uploaded in, limited to opts.Parallel
+// uploads, and the context of the uploads. Unless opts.KeepGoing is set, the first failed upload cancels the
+// context, aborting the uploads in flight and the ones not started yet.
+func uploadGroup(ctx context.Context, opts PopulateEnvOpts) (*errgroup.Group, context.Context) {
+	eg, uploadCtx := &errgroup.Group{}, ctx
+	if !opts.KeepGoing {
+		eg, uploadCtx = errgroup.WithContext(ctx)
+	}
+	eg.SetLimit(opts.Parallel)
+	return eg, uploadCtx
+}
+
+// notUploadedReason returns why a file was not uploaded after its upload context was cancelled: populate was
+// cancelled when ctx, the context of the populate, is, and another file failed otherwise.
+func notUploadedReason(ctx context.Context) string {
+	if ctx.Err() != nil {
+		return "not uploaded because populate was cancelled"
+	}
+	return "not uploaded because another file failed to ingest"
+}
+
+// ValidateTTLPaths checks the syntax of the given RDF files, and of every RDF file under the given
+// directories and in the given archives, without sending anything to an environment. It returns the number of
+// checked files and an error if any file could not be read or is not valid in its format. Every syntax error
+// is reported with its file:line:column position. The files of the directories and archives are selected with
+// filter, as PopulateEnv does.
+func ValidateTTLPaths(ttlPaths []string, parallel int, filter PathFilter) (int, error) {
+	if parallel == 0 {
+		return 0, fmt.Errorf("invalid parallel value: %d", parallel)
+	}
 
-			eg.Go(func() error {
-				display.Step("Ingesting file: %s", d.Name())
-				if err := postFile(path, *postURL); err != nil {
-					display.Error("Failed to ingest '%s': %v", d.Name(), err)
-					return err
+	var files []string
+	archived := 0
+	var archiveErrs []error
+	for _, ttlPath := range ttlPaths {
+		if IsArchive(ttlPath) {
+			absPath, err := filepath.Abs(ttlPath)
+			if err != nil {
+				return 0, fmt.Errorf("failed to resolve absolute path: %w", err)
+			}
+			entries, err := scanArchive(absPath, filter)
+			if err != nil {
+				return 0, err
+			}
+			archived += len(entries)
+			for _, entry := range entries {
+				if entry.invalid != nil {
+					archiveErrs = append(archiveErrs, entry.invalid)
 				}
-				mu.Lock()
-				successfulFiles = append(successfulFiles, path)
-				mu.Unlock()
-				return nil
-			})
+			}
+			continue
+		}
+
+		pathFiles, _, err := collectRDFFiles(ttlPath, filter)
+		if err != nil {
+			return 0, err
+		}
+		files = append(files, pathFiles...)
+	}
+
+	total := len(files) + archived
+	if len(files) > 0 {
+		if err := validateRDFFiles(files, parallel, nil); err != nil {
+			return total, err
+		}
+	}
+	if len(archiveErrs) > 0 {
+		return total, fmt.Errorf("%d of %d archived file(s) are not valid RDF, first error: %w", len(archiveErrs), archived, archiveErrs[0])
+	}
+
+	display.Done("Checked %d file(s) from %d path(s), no syntax errors found", total, len(ttlPaths))
+	return total, nil
+}
+
+// collectRDFFiles resolves ttlPath to the absolute paths of the RDF files to ingest. A directory is walked
+// recursively and every file with an RDF extension selected by filter and by the ignore file of the directory
+// is returned; excluded directories are not traversed. It also reports whether ttlPath is a directory.
+func collectRDFFiles(ttlPath string, filter PathFilter) ([]string, bool, error) {
+	absPath, err := filepath.Abs(ttlPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+
+	fi, err := os.Stat(absPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot access path '%s': %w", ttlPath, err)
+	}
+
+	if !fi.IsDir() {
+		return []string{absPath}, false, nil
+	}
+
+	filter, err = filter.withIgnoreFile(absPath)
+	if err != nil {
+		return nil, true, err
+	}
+
+	var files []string
+	walkError := false
+	err = filepath.WalkDir(absPath, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			display.Error("Failed to access path during directory traversal: %v", walkErr)
+			walkError = true
 			return nil
-		})
+		}
+		if path == absPath {
+			return nil
+		}
+		rel, err := filepath.Rel(absPath, path)
 		if err != nil {
-			return successfulFiles, fmt.Errorf("directory traversal failed for '%s': %w", ttlPath, err)
+			return err
 		}
-		if walkError {
-			return successfulFiles, fmt.Errorf("encountered errors while traversing directory '%s'", ttlPath)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if filter.excludes(rel, true) {
+				display.Debug("skipping excluded directory: %s", rel)
+				return filepath.SkipDir
+			}
+			return nil
 		}
-		if err := eg.Wait(); err != nil {
-			return successfulFiles, fmt.Errorf("one or more files failed to ingest in directory '%s': %w", ttlPath, err)
+		if _, ok := RDFFormatFromExtension(d.Name()); !ok {
+			return nil
+		}
+		if !filter.selects(rel) {
+			display.Debug("skipping filtered file: %s", rel)
+			return nil
 		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, true, fmt.Errorf("directory traversal failed for '%s': %w", ttlPath, err)
+	}
+	if walkError {
+		return nil, true, fmt.Errorf("encountered errors while traversing directory '%s'", ttlPath)
+	}
 
-		display.Done("Successfully ingested all *.ttl files from directory '%s'", ttlPath)
-	} else {
-		display.Step("Ingesting single file: %s", filepath.Base(ttlPath))
-		err := postFile(absPath, *postURL)
+	return files, true, nil
+}
+
+// validateRDFFiles checks the syntax of all files in parallel, reporting every invalid file. When any file is
+// invalid, the invalid files and the valid ones, which are not uploaded either, are recorded in report.
+func validateRDFFiles(files []string, parallel int, report *PopulateReport) error {
+	results := checkRDFFiles(files, parallel)
+
+	var errs []error
+	for _, err := range results {
 		if err != nil {
-			return successfulFiles, fmt.Errorf("failed to ingest file '%s': %w", filepath.Base(ttlPath), err)
+			errs = append(errs, err)
 		}
-		display.Done("Successfully ingested '%s'", filepath.Base(ttlPath))
-		successfulFiles = append(successfulFiles, absPath)
 	}
 
-	return successfulFiles, nil
+	if len(errs) > 0 {
+		for i, path := range files {
+			result := FileResult{Path: path, Status: FileSkipped, Error: "not uploaded because other files are not valid"}
+			if results[i] != nil {
+				result = FileResult{Path: path, Status: FileInvalid, Error: results[i].Error()}
+			}
+			result.SizeBytes = fileSize(path)
+			report.Add(result)
+		}
+		return fmt.Errorf("%d of %d file(s) are not valid RDF, first error: %w", len(errs), len(files), errs[0])
+	}
+
+	return nil
+}
+
+// dropInvalidRDFFiles checks the syntax of all files in parallel like validateRDFFiles, but records only the
+// invalid files in report. It returns the valid files and the syntax errors of the invalid ones.
+func dropInvalidRDFFiles(files []string, parallel int, report *PopulateReport) ([]string, []error) {
+	results := checkRDFFiles(files, parallel)
+
+	var valid []string
+	var errs []error
+	for i, path := range files {
+		if results[i] == nil {
+			valid = append(valid, path)
+			continue
+		}
+		errs = append(errs, results[i])
+		report.Add(FileResult{Path: path, Status: FileInvalid, Error: results[i].Error(), SizeBytes: fileSize(path)})
+	}
+
+	if len(errs) > 0 {
+		display.Warn("Leaving out %d invalid file(s), ingesting the other %d", len(errs), len(valid))
+	}
+
+	return valid, errs
+}
+
+// checkRDFFiles checks the syntax of all files in parallel, printing every syntax error. It returns the error
+// of every file, nil for the valid ones.
+func checkRDFFiles(files []string, parallel int) []error {
+	display.Step("Checking syntax of %d file(s)", len(files))
+
+	var eg errgroup.Group
+	eg.SetLimit(parallel)
+	results := make([]error, len(files))
+
+	for i, path := range files {
+		eg.Go(func() error {
+			results[i] = ValidateRDFFile(path)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	for _, err := range results {
+		if err != nil {
+			display.Error("%v", err)
+		}
+	}
+
+	return results
+}
+
+// fileSize returns the size of the file at path, or zero if it cannot be read.
+func fileSize(path string) int64 {
+	if info, err := os.Stat(path); err == nil {
+		return info.Size()
+	}
+	return 0
 }
 
 var client = http.Client{
 	Timeout: 3 * time.Minute,
 }
 
-func postFile(path string, url url.URL) error {
-	file, err := os.ReadFile(path)
+// ingestFile posts a single file with postFile and records its outcome in opts.Report.
+func ingestFile(ctx context.Context, path string, url url.URL, settings IngestionSettings, opts PopulateEnvOpts) (IngestedFile, bool, error) {
+	start := time.Now()
+	file, changed, err := postFile(ctx, path, url, settings, opts)
+	reportIngestion(opts.Report, path, start, file, changed, err)
+	return file, changed, err
+}
+
+// reportIngestion records in report the outcome of the upload of the file at path started at start.
+func reportIngestion(report *PopulateReport, path string, start time.Time, file IngestedFile, changed bool, err error) {
+	switch {
+	case err != nil:
+		report.Add(fileResultFromError(path, file.SizeBytes, time.Since(start), err))
+	case !changed:
+		report.Add(FileResult{Path: path, Status: FileSkipped, Error: "unchanged since the last ingestion", SizeBytes: file.SizeBytes})
+	default:
+		report.Add(FileResult{Path: path, Status: FileIngested, Duration: time.Since(start), SizeBytes: file.SizeBytes})
+	}
+}
+
+// postFile posts a single file with the given ingestion settings, streaming it from disk with postBody. It
+// returns false without posting anything when the content of the file matches the hash recorded in
+// opts.Ingested.
+func postFile(ctx context.Context, path string, url url.URL, settings IngestionSettings, opts PopulateEnvOpts) (IngestedFile, bool, error) {
+	format, err := DetectRDFFormat(path)
 	if err != nil {
-		return fmt.Errorf("failed to read file '%s': %w", filepath.Base(path), err)
+		return IngestedFile{}, false, err
 	}
-	return postRequest(path, url, bytes.NewReader(file), false)
+	return postBody(ctx, path, format, fileBody(path, 0), url, settings, opts)
 }
 
-func postURL(path string, url url.URL) error {
-	return postRequest(path, url, nil, true)
+// postBody posts body, the content in format of the file at path, with the given ingestion settings. Turtle
+// and N-Triples content is streamed from body, so that large files are never held in memory; content in other
+// formats is read and converted to Turtle with postContent. It returns false without posting anything when
+// the content matches the hash recorded in opts.Ingested.
+func postBody(ctx context.Context, path string, format RDFFormat, body requestBody, url url.URL, settings IngestionSettings, opts PopulateEnvOpts) (IngestedFile, bool, error) {
+	if format != FormatTurtle && format != FormatNTriples {
+		content, err := readBody(path, body)
+		if err != nil {
+			return IngestedFile{}, false, err
+		}
+		return postContent(ctx, path, content, url, settings, opts)
+	}
+
+	file, err := hashBody(path, body)
+	if err != nil {
+		return IngestedFile{}, false, err
+	}
+	body.size = file.SizeBytes
+
+	if hash, ok := opts.Ingested[path]; ok && hash == file.ContentHash {
+		display.Info("Skipping unchanged file: %s", filepath.Base(path))
+		return file, false, nil
+	}
+
+	display.Step("Ingesting file: %s", filepath.Base(path))
+	display.Debug("ingesting %s with %s (%d bytes, streamed)", filepath.Base(path), settings, file.SizeBytes)
+	if err := postRequest(ctx, path, url, body, false, settings, opts.Retry, opts.Compress); err != nil {
+		return file, false, err
+	}
+	return file, true, nil
+}
+
+// postContent hashes and posts the content of the file at path with the given ingestion settings, converting
+// it to Turtle first when needed. It returns false without posting anything when the content matches the hash
+// recorded in opts.Ingested.
+func postContent(ctx context.Context, path string, content []byte, url url.URL, settings IngestionSettings, opts PopulateEnvOpts) (IngestedFile, bool, error) {
+	sum := sha256.Sum256(content)
+	file := IngestedFile{
+		Path:        path,
+		ContentHash: hex.EncodeToString(sum[:]),
+		SizeBytes:   int64(len(content)),
+	}
+
+	if hash, ok := opts.Ingested[path]; ok && hash == file.ContentHash {
+		display.Info("Skipping unchanged file: %s", filepath.Base(path))
+		return file, false, nil
+	}
+
+	format, err := detectRDFFormat(path, content)
+	if err != nil {
+		return file, false, err
+	}
+	body, err := ConvertToTurtle(content, format)
+	if err != nil {
+		return file, false, fmt.Errorf("failed to convert '%s' from %s to Turtle: %w", filepath.Base(path), format, err)
+	}
+	if format != FormatTurtle {
+		display.Debug("converted %s from %s to Turtle (%d bytes)", filepath.Base(path), format, len(body))
+	}
+
+	display.Step("Ingesting file: %s", filepath.Base(path))
+	display.Debug("ingesting %s with %s", filepath.Base(path), settings)
+	if err := postRequest(ctx, path, url, bytesBody(body), false, settings, opts.Retry, opts.Compress); err != nil {
+		return file, false, err
+	}
+	return file, true, nil
 }
 
-func postRequest(path string, url url.URL, body io.Reader, setPathQuery bool) error {
+func postURL(ctx context.Context, path string, url url.URL, retry RetryPolicy) error {
+	return postRequest(ctx, path, url, bytesBody(nil), true, DefaultIngestionSettings(), retry, false)
+}
+
+// postRequest posts body to url with the given ingestion settings, retrying according to retry. The body is
+// opened again on every attempt. With compress set the body is sent compressed with gzip, and sent again
+// uncompressed when the gateway rejects it as an unsupported media type. Cancelling ctx aborts the request in
+// flight and any pending retry.
+func postRequest(ctx context.Context, path string, url url.URL, body requestBody, setPathQuery bool, settings IngestionSettings, retry RetryPolicy, compress bool) error {
 	q := url.Query()
-	q.Set("type", "single")
-	q.Set("model", "EPOS-DCAT-AP-V1")
-	q.Set("mapping", "EDM-TO-DCAT-AP")
+	q.Set("type", settings.Type)
+	q.Set("model", settings.Model)
+	q.Set("mapping", settings.Mapping)
 	if setPathQuery {
 		q.Set("path", path)
 	}
 	url.RawQuery = q.Encode()
 
-	r, err := http.NewRequest("POST", url.String(), body)
+	for attempt := 0; ; attempt++ {
+		err := sendRequest(ctx, path, url, body, compress)
+		if statusErr, ok := err.(*httpStatusError); ok && compress && statusErr.StatusCode == http.StatusUnsupportedMediaType {
+			display.Warn("The gateway does not accept compressed uploads, sending '%s' uncompressed", filepath.Base(path))
+			compress = false
+			err = sendRequest(ctx, path, url, body, compress)
+		}
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil || attempt >= retry.MaxRetries || !retry.retryable(err) {
+			return err
+		}
+
+		var retryAfter time.Duration
+		if statusErr, ok := err.(*httpStatusError); ok {
+			retryAfter = statusErr.RetryAfter
+		}
+		delay := retry.delay(attempt, retryAfter)
+		display.Warn("Retrying '%s' in %s (attempt %d/%d): %v", filepath.Base(path), delay, attempt+1, retry.MaxRetries, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return fmt.Errorf("retry of '%s' cancelled: %w", filepath.Base(path), ctx.Err())
+		}
+	}
+}
+
+// sendRequest posts body to url once. Uncompressed bodies are sent with their Content-Length, compressed ones
+// are compressed while they are sent.
+func sendRequest(ctx context.Context, path string, url url.URL, body requestBody, compress bool) error {
+	reader, err := body.open()
 	if err != nil {
+		return err
+	}
+	size := body.size
+	switch {
+	case compress:
+		reader = gzipReader(reader)
+		size = -1
+	case size == 0:
+		reader.Close()
+		reader = http.NoBody
+	}
+
+	r, err := http.NewRequestWithContext(ctx, "POST", url.String(), reader)
+	if err != nil {
+		reader.Close()
 		return fmt.Errorf("failed to create HTTP request for '%s': %w", filepath.Base(path), err)
 	}
+	r.ContentLength = size
 	r.Header.Add("accept", "*/*")
 	r.Header.Add("Content-Type", "text/turtle")
+	if compress {
+		r.Header.Add("Content-Encoding", "gzip")
+	}
 
 	res, err := client.Do(r)
 	if err != nil {
@@ -155,36 +632,52 @@ func postRequest(path string, url url.URL, body io.Reader, setPathQuery bool) er
 		if err != nil {
 			return fmt.Errorf("failed to read response body for '%s': %w", filepath.Base(path), err)
 		}
-		return fmt.Errorf("server rejected '%s' with status %d: %s", filepath.Base(path), res.StatusCode, string(body))
+		return &httpStatusError{
+			Name:       filepath.Base(path),
+			StatusCode: res.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 	return nil
 }
 
-// PopulateExample ingests example TTL files from predefined URLs into an environment.
-// It processes the examples in parallel according to the specified concurrency limit.
+// PopulateExample ingests the example TTL files embedded in the binary into an environment.
+// It processes the examples in parallel according to the specified concurrency limit. The examples are
+// EPOS-DCAT-AP V1 metadata and are always ingested with DefaultIngestionSettings. When remote is set the
+// gateway fetches them from GitHub instead; otherwise nothing is ingested when the binary has no copy of one of
+// them.
 //
 // On partial failure (e.g., some files fail to ingest), the function returns successfully ingested file paths
 // along with an error indicating the failure. This allows callers to handle partial successes (e.g., save
 // successful ingestions) while still being notified of issues. The returned slice is always non-nil.
 //
+// When ctx is cancelled no further example is started and the requests in flight are aborted.
+//
 // Parameters:
+//   - ctx: Cancels the ingestion
 //   - endpointURL: Base URL of the EPOS gateway (e.g., "http://gateway/api/v1")
 //   - parallel: Maximum number of concurrent example ingestions (use 1 for sequential processing)
+//   - retry: How failed uploads are retried
+//   - report: Optional. Records the outcome of every example when set
+//   - remote: Makes the gateway fetch the examples from GitHub instead of uploading the embedded copies
 //
-// Returns a list of successfully ingested example URLs and an error if any example fails to ingest.
-func PopulateExample(endpointURL string, parallel int) ([]string, error) {
+// Returns a list of successfully ingested example URLs, the upstream URLs at the pinned revisions, and an
+// error if any example fails to ingest.
+func PopulateExample(ctx context.Context, endpointURL string, parallel int, retry RetryPolicy, report *PopulateReport, remote bool) ([]string, error) {
 	successfulFiles := []string{}
 
 	if parallel == 0 {
 		return successfulFiles, fmt.Errorf("invalid parallel value: %d", parallel)
 	}
 
-	examples := map[string]string{
-		"EPOS GeoJSON":      "https://raw.githubusercontent.com/EPOS-ERIC/opensource-docs/refs/heads/main/static/examples/example-geojson.ttl",
-		"Coverage JSON":     "https://raw.githubusercontent.com/EPOS-ERIC/opensource-docs/refs/heads/main/static/examples/example-covjson.ttl",
-		"Downloadable File": "https://raw.githubusercontent.com/EPOS-ERIC/opensource-docs/refs/heads/main/static/examples/example-downloadablefile.ttl",
-		"OGC WMS":           "https://raw.githubusercontent.com/EPOS-ERIC/opensource-docs/refs/heads/main/static/examples/ogc-wms.ttl",
-		"OGC WFS":           "https://raw.githubusercontent.com/EPOS-ERIC/opensource-docs/refs/heads/main/static/examples/ogc-wfs.ttl",
+	examples := offline.Sources().Examples
+	if !remote {
+		for _, example := range examples {
+			if !example.Pinned() {
+				return successfulFiles, fmt.Errorf("no offline copy of example '%s' is embedded, run 'make offline-data' before building or fetch the examples from GitHub", example.Name)
+			}
+		}
 	}
 
 	endpointURL = strings.TrimSuffix(endpointURL, "/ui")
@@ -201,13 +694,23 @@ func PopulateExample(endpointURL string, parallel int) ([]string, error) {
 	var mu sync.Mutex
 	var successfulExamples []string
 
-	for name, exampleURL := range examples {
+	for _, example := range examples {
+		exampleURL := example.RemoteURL()
+
 		eg.Go(func() error {
-			display.Step("Ingesting example: %s", name)
-			if err := postURL(exampleURL, *populateURL); err != nil {
-				display.Error("Failed to ingest example '%s': %v", name, err)
+			if err := ctx.Err(); err != nil {
+				report.Add(FileResult{Path: exampleURL, Status: FileSkipped, Error: "not uploaded because populate was cancelled"})
+				return err
+			}
+			display.Step("Ingesting example: %s", example.Name)
+			start := time.Now()
+			size, err := postExample(ctx, example, *populateURL, retry, remote)
+			if err != nil {
+				report.Add(fileResultFromError(exampleURL, size, time.Since(start), err))
+				display.Error("Failed to ingest example '%s': %v", example.Name, err)
 				return err
 			}
+			report.Add(FileResult{Path: exampleURL, Status: FileIngested, SizeBytes: size, Duration: time.Since(start)})
 			mu.Lock()
 			successfulExamples = append(successfulExamples, exampleURL)
 			mu.Unlock()
@@ -215,10 +718,28 @@ func PopulateExample(endpointURL string, parallel int) ([]string, error) {
 		})
 	}
 
-	if err := eg.Wait(); err != nil {
+	err = eg.Wait()
+	if ctx.Err() != nil {
+		return successfulExamples, fmt.Errorf("ingestion of examples cancelled: %w", ctx.Err())
+	}
+	if err != nil {
 		return successfulExamples, fmt.Errorf("one or more examples failed to ingest: %w", err)
 	}
 
 	display.Done("Successfully ingested all example files")
 	return successfulExamples, nil
 }
+
+// postExample uploads the embedded copy of example to url, or makes the gateway fetch it from GitHub when
+// remote is set. It returns the size of the uploaded copy, and an error when the binary has no copy of example.
+func postExample(ctx context.Context, example offline.Source, url url.URL, retry RetryPolicy, remote bool) (int64, error) {
+	if remote {
+		return 0, postURL(ctx, example.RemoteURL(), url, retry)
+	}
+
+	content, err := example.Content()
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(content)), postRequest(ctx, example.RemoteURL(), url, bytesBody(content), false, DefaultIngestionSettings(), retry, false)
+}
@@ -1,16 +1,30 @@
 package common
 
 import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/common/offline"
 )
 
+// ttl returns a minimal valid Turtle document holding value as its only literal.
+func ttl(value string) string {
+	return `<urn:ex:s> <urn:ex:p> "` + value + `" .`
+}
+
 func TestPopulateEnv(t *testing.T) {
 	t.Parallel()
 
@@ -18,6 +32,8 @@ func TestPopulateEnv(t *testing.T) {
 		name                    string
 		files                   map[string]string // path -> content
 		targetPath              string            // path to pass to PopulateEnv
+		ingested                map[string]string // path -> previously ingested content, enables incremental mode
+		keepGoing               bool
 		serverHandler           http.HandlerFunc
 		expectErr               bool
 		expectedPaths           []string // paths expected at the server
@@ -26,11 +42,11 @@ func TestPopulateEnv(t *testing.T) {
 		{
 			name: "single_file_success",
 			files: map[string]string{
-				"single.ttl": "file content",
+				"single.ttl": ttl("file content"),
 			},
 			targetPath: "single.ttl",
 			serverHandler: func(w http.ResponseWriter, r *http.Request) {
-				if body, _ := io.ReadAll(r.Body); string(body) != "file content" {
+				if body, _ := io.ReadAll(r.Body); string(body) != ttl("file content") {
 					w.WriteHeader(http.StatusBadRequest)
 					return
 				}
@@ -43,12 +59,12 @@ func TestPopulateEnv(t *testing.T) {
 		{
 			name: "directory_success",
 			files: map[string]string{
-				"a.ttl":          "content a",
-				"b.ttl":          "content b",
+				"a.ttl":          ttl("content a"),
+				"b.ttl":          ttl("content b"),
 				"c.txt":          "content c",
-				"sub/d.ttl":      "content d",
+				"sub/d.ttl":      ttl("content d"),
 				"sub/e.json":     "content e",
-				"sub/sub2/f.ttl": "content f",
+				"sub/sub2/f.ttl": ttl("content f"),
 			},
 			targetPath: ".",
 			serverHandler: func(w http.ResponseWriter, r *http.Request) {
@@ -66,17 +82,18 @@ func TestPopulateEnv(t *testing.T) {
 		{
 			name: "directory_partial_failure",
 			files: map[string]string{
-				"a.ttl":          "content a",
-				"b.ttl":          "content b",
+				"a.ttl":          ttl("content a"),
+				"b.ttl":          ttl("content b"),
 				"c.txt":          "content c",
-				"sub/d.ttl":      "content d",
+				"sub/d.ttl":      ttl("content d"),
 				"sub/e.json":     "content e",
-				"sub/sub2/f.ttl": "content f",
+				"sub/sub2/f.ttl": ttl("content f"),
 			},
 			targetPath: ".",
+			keepGoing:  true,
 			serverHandler: func(w http.ResponseWriter, r *http.Request) {
 				body, _ := io.ReadAll(r.Body)
-				if string(body) == "content a" {
+				if string(body) == ttl("content a") {
 					w.WriteHeader(http.StatusInternalServerError)
 				} else {
 					w.WriteHeader(http.StatusOK)
@@ -94,7 +111,7 @@ func TestPopulateEnv(t *testing.T) {
 		{
 			name: "server_error",
 			files: map[string]string{
-				"bad.ttl": "this will fail",
+				"bad.ttl": ttl("this will fail"),
 			},
 			targetPath: "bad.ttl",
 			serverHandler: func(w http.ResponseWriter, r *http.Request) {
@@ -104,6 +121,129 @@ func TestPopulateEnv(t *testing.T) {
 			expectedPaths:           []string{"/populate"},
 			expectedSuccessfulFiles: []string{},
 		},
+		{
+			name: "directory_incremental_skips_unchanged",
+			files: map[string]string{
+				"a.ttl":     ttl("content a"),
+				"b.ttl":     ttl("content b changed"),
+				"sub/c.ttl": ttl("content c"),
+			},
+			targetPath: ".",
+			ingested: map[string]string{
+				"a.ttl": ttl("content a"),
+				"b.ttl": ttl("content b"),
+			},
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			expectErr:               false,
+			expectedPaths:           []string{"/populate", "/populate"},
+			expectedSuccessfulFiles: []string{"b.ttl", "sub/c.ttl"},
+		},
+		{
+			name: "single_file_incremental_unchanged",
+			files: map[string]string{
+				"single.ttl": ttl("file content"),
+			},
+			targetPath: "single.ttl",
+			ingested: map[string]string{
+				"single.ttl": ttl("file content"),
+			},
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			expectErr:               false,
+			expectedPaths:           nil,
+			expectedSuccessfulFiles: []string{},
+		},
+		{
+			name: "syntax_error_prevents_upload",
+			files: map[string]string{
+				"a.ttl":     ttl("content a"),
+				"sub/b.ttl": "<urn:ex:s> <urn:ex:p> \"missing dot\"",
+			},
+			targetPath: ".",
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			expectErr:               true,
+			expectedPaths:           nil,
+			expectedSuccessfulFiles: []string{},
+		},
+		{
+			name: "keep_going_uploads_valid_files",
+			files: map[string]string{
+				"a.ttl":     ttl("content a"),
+				"sub/b.ttl": "<urn:ex:s> <urn:ex:p> \"missing dot\"",
+				"sub/c.ttl": ttl("content c"),
+			},
+			targetPath: ".",
+			keepGoing:  true,
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) == ttl("content c") {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			},
+			expectErr:               true,
+			expectedPaths:           []string{"/populate", "/populate"},
+			expectedSuccessfulFiles: []string{"a.ttl"},
+		},
+		{
+			name: "keep_going_all_invalid",
+			files: map[string]string{
+				"a.ttl": "<urn:ex:s> <urn:ex:p> .",
+			},
+			targetPath: ".",
+			keepGoing:  true,
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			expectErr:               true,
+			expectedPaths:           nil,
+			expectedSuccessfulFiles: []string{},
+		},
+		{
+			name: "directory_converts_other_formats",
+			files: map[string]string{
+				"a.ttl":     ttl("content a"),
+				"b.nt":      ttl("content b"),
+				"c.jsonld":  `{"@id": "urn:ex:s", "urn:ex:p": "content c"}`,
+				"sub/d.rdf": `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="urn:ex:"><rdf:Description rdf:about="urn:ex:s"><ex:p>content d</ex:p></rdf:Description></rdf:RDF>`,
+			},
+			targetPath: ".",
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				// every upload must be Turtle holding the same single triple
+				triples, err := ParseTurtle(r.Body, "")
+				if err != nil || len(triples) != 1 || triples[0].Subject != IRI("urn:ex:s") || triples[0].Predicate != IRI("urn:ex:p") {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			},
+			expectErr:               false,
+			expectedPaths:           []string{"/populate", "/populate", "/populate", "/populate"},
+			expectedSuccessfulFiles: []string{"a.ttl", "b.nt", "c.jsonld", "sub/d.rdf"},
+		},
+		{
+			name: "single_file_format_detected_from_content",
+			files: map[string]string{
+				"data.json": `{"@context": {"ex": "urn:ex:"}, "@id": "ex:s", "ex:p": "content"}`,
+			},
+			targetPath: "data.json",
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				if triples, err := ParseTurtle(r.Body, ""); err != nil || len(triples) != 1 {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			},
+			expectErr:               false,
+			expectedPaths:           []string{"/populate"},
+			expectedSuccessfulFiles: []string{"data.json"},
+		},
 		{
 			name:                    "file_not_found",
 			files:                   nil,
@@ -148,8 +288,23 @@ func TestPopulateEnv(t *testing.T) {
 				serverURL = server.URL
 			}
 
+			var ingested map[string]string
+			if tc.ingested != nil {
+				ingested = make(map[string]string, len(tc.ingested))
+				for path, content := range tc.ingested {
+					sum := sha256.Sum256([]byte(content))
+					ingested[filepath.Join(tmpDir, path)] = hex.EncodeToString(sum[:])
+				}
+			}
+
 			target := filepath.Join(tmpDir, tc.targetPath)
-			successfulFiles, err := PopulateEnv(target, serverURL, 2)
+			ingestedFiles, err := PopulateEnv(t.Context(), PopulateEnvOpts{
+				Path:        target,
+				EndpointURL: serverURL,
+				Parallel:    2,
+				Ingested:    ingested,
+				KeepGoing:   tc.keepGoing,
+			})
 
 			if tc.expectErr {
 				if err == nil {
@@ -162,6 +317,23 @@ func TestPopulateEnv(t *testing.T) {
 			}
 
 			// Check successful files
+			successfulFiles := make([]string, len(ingestedFiles))
+			for i, file := range ingestedFiles {
+				successfulFiles[i] = file.Path
+
+				content, err := os.ReadFile(file.Path)
+				if err != nil {
+					t.Fatalf("Failed to read ingested file: %v", err)
+				}
+				sum := sha256.Sum256(content)
+				if file.ContentHash != hex.EncodeToString(sum[:]) {
+					t.Errorf("Unexpected content hash for %s: %s", file.Path, file.ContentHash)
+				}
+				if file.SizeBytes != int64(len(content)) {
+					t.Errorf("Expected size %d for %s, got %d", len(content), file.Path, file.SizeBytes)
+				}
+			}
+
 			expectedAbs := make([]string, len(tc.expectedSuccessfulFiles))
 			for i, p := range tc.expectedSuccessfulFiles {
 				expectedAbs[i] = filepath.Join(tmpDir, p)
@@ -181,27 +353,276 @@ func TestPopulateEnv(t *testing.T) {
 			}
 
 			// Check if the received paths at the server match the expected paths
+			mu.Lock()
 			if len(receivedPaths) != len(tc.expectedPaths) {
 				t.Errorf("Expected %d requests, but got %d", len(tc.expectedPaths), len(receivedPaths))
 			}
+			mu.Unlock()
 		})
 	}
 }
 
+func TestPopulateEnvCancelled(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	for _, name := range []string{"a.ttl", "b.ttl", "c.ttl"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(ttl("content "+name)), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	// a.ttl is accepted, the upload of b.ttl hangs until populate is cancelled and c.ttl is never started
+	var mu sync.Mutex
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		if string(body) == ttl("content a.ttl") {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	report := NewPopulateReport("test-env")
+	files, err := PopulateEnv(ctx, PopulateEnvOpts{
+		Path:        tmpDir,
+		EndpointURL: server.URL,
+		Parallel:    1,
+		Retry:       RetryPolicy{MaxRetries: 3, Backoff: time.Minute, MaxBackoff: time.Minute},
+		Report:      report,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a cancellation error, got %v", err)
+	}
+	if len(files) != 1 || files[0].Path != filepath.Join(tmpDir, "a.ttl") {
+		t.Errorf("Expected only a.ttl to be ingested, got %+v", files)
+	}
+	mu.Lock()
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	mu.Unlock()
+
+	statuses := map[string]FileStatus{}
+	for _, result := range report.Files {
+		statuses[filepath.Base(result.Path)] = result.Status
+	}
+	expected := map[string]FileStatus{"a.ttl": FileIngested, "b.ttl": FileFailed, "c.ttl": FileSkipped}
+	for name, status := range expected {
+		if statuses[name] != status {
+			t.Errorf("Expected %s to be %s, got %s", name, status, statuses[name])
+		}
+	}
+}
+
+func TestPopulateEnvFailFast(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	for _, name := range []string{"a.ttl", "b.ttl", "c.ttl"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(ttl("content "+name)), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	// the upload of b.ttl hangs until it is aborted, a.ttl is rejected once b.ttl is in flight and c.ttl is
+	// never started
+	inFlight := make(chan struct{})
+	var mu sync.Mutex
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		if string(body) == ttl("content b.ttl") {
+			close(inFlight)
+			<-r.Context().Done()
+			return
+		}
+		<-inFlight
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	report := NewPopulateReport("test-env")
+	_, err := PopulateEnv(t.Context(), PopulateEnvOpts{
+		Path:        tmpDir,
+		EndpointURL: server.URL,
+		Parallel:    2,
+		Report:      report,
+	})
+	if err == nil || errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the failure of a.ttl, got %v", err)
+	}
+	mu.Lock()
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	mu.Unlock()
+
+	statuses := map[string]FileStatus{}
+	for _, result := range report.Files {
+		statuses[filepath.Base(result.Path)] = result.Status
+	}
+	expected := map[string]FileStatus{"a.ttl": FileFailed, "b.ttl": FileFailed, "c.ttl": FileSkipped}
+	for name, status := range expected {
+		if statuses[name] != status {
+			t.Errorf("Expected %s to be %s, got %s", name, status, statuses[name])
+		}
+	}
+}
+
+func TestPopulateEnvStreamsFiles(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat(ttl(strings.Repeat("x", 1000))+"\n", 1000)
+
+	tests := []struct {
+		name             string
+		compress         bool
+		acceptGzip       bool
+		expectedRequests []string
+	}{
+		{name: "uncompressed", expectedRequests: []string{"identity"}},
+		{name: "compressed", compress: true, acceptGzip: true, expectedRequests: []string{"gzip"}},
+		{name: "compression rejected", compress: true, expectedRequests: []string{"gzip", "identity"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "large.ttl")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			var mu sync.Mutex
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				encoding := r.Header.Get("Content-Encoding")
+				if encoding == "" {
+					encoding = "identity"
+				}
+				mu.Lock()
+				requests = append(requests, encoding)
+				mu.Unlock()
+
+				var body io.Reader = r.Body
+				switch {
+				case encoding == "gzip" && !tc.acceptGzip:
+					w.WriteHeader(http.StatusUnsupportedMediaType)
+					return
+				case encoding == "gzip":
+					zr, err := gzip.NewReader(r.Body)
+					if err != nil {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					body = zr
+				case r.ContentLength != int64(len(content)):
+					w.WriteHeader(http.StatusLengthRequired)
+					return
+				}
+
+				received, err := io.ReadAll(body)
+				if err != nil || string(received) != content {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			files, err := PopulateEnv(t.Context(), PopulateEnvOpts{
+				Path:        path,
+				EndpointURL: server.URL,
+				Parallel:    1,
+				Compress:    tc.compress,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(files) != 1 || files[0].SizeBytes != int64(len(content)) {
+				t.Errorf("Expected one file of %d bytes, got %+v", len(content), files)
+			}
+			sum := sha256.Sum256([]byte(content))
+			if files[0].ContentHash != hex.EncodeToString(sum[:]) {
+				t.Errorf("Expected content hash %x, got %s", sum, files[0].ContentHash)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if strings.Join(requests, ",") != strings.Join(tc.expectedRequests, ",") {
+				t.Errorf("Expected requests %v, got %v", tc.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestValidateTTLPaths(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"a.ttl":     ttl("content a"),
+		"b.ttl":     "@prefix ex: <http://example.org/> .\nex:a ex:b ex:c .\n",
+		"c.txt":     "not turtle",
+		"bad/d.ttl": "@prefix ex: <http://example.org/> .\nex:a undefined:b ex:c .\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	checked, err := ValidateTTLPaths([]string{filepath.Join(tmpDir, "a.ttl")}, 1, PathFilter{})
+	if err != nil || checked != 1 {
+		t.Errorf("Expected 1 valid file, got %d and error %v", checked, err)
+	}
+
+	checked, err = ValidateTTLPaths([]string{tmpDir}, 2, PathFilter{})
+	if checked != 3 {
+		t.Errorf("Expected 3 checked files, got %d", checked)
+	}
+	if err == nil {
+		t.Fatal("Expected an error, but got nil")
+	}
+	if !strings.Contains(err.Error(), filepath.Join(tmpDir, "bad", "d.ttl")+":2:6:") {
+		t.Errorf("Expected error to reference bad/d.ttl:2:6, got %v", err)
+	}
+}
+
 func TestPopulateExample(t *testing.T) {
 	t.Parallel()
 
-	examples := map[string]string{
-		"EPOS GeoJSON":      "https://raw.githubusercontent.com/EPOS-ERIC/opensource-docs/refs/heads/main/static/examples/example-geojson.ttl",
-		"Coverage JSON":     "https://raw.githubusercontent.com/EPOS-ERIC/opensource-docs/refs/heads/main/static/examples/example-covjson.ttl",
-		"Downloadable File": "https://raw.githubusercontent.com/EPOS-ERIC/opensource-docs/refs/heads/main/static/examples/example-downloadablefile.ttl",
-		"OGC WMS":           "https://raw.githubusercontent.com/EPOS-ERIC/opensource-docs/refs/heads/main/static/examples/ogc-wms.ttl",
-		"OGC WFS":           "https://raw.githubusercontent.com/EPOS-ERIC/opensource-docs/refs/heads/main/static/examples/ogc-wfs.ttl",
+	examples := map[string]string{}
+	pinned := 0
+	for _, example := range offline.Sources().Examples {
+		examples[example.Name] = example.RemoteURL()
+		if example.Pinned() {
+			pinned++
+		}
 	}
 
 	tests := []struct {
 		name                       string
 		serverHandler              http.HandlerFunc
+		remote                     bool
 		expectErr                  bool
 		expectedPaths              []string
 		expectedSuccessfulExamples []string
@@ -221,6 +642,22 @@ func TestPopulateExample(t *testing.T) {
 				examples["OGC WFS"],
 			},
 		},
+		{
+			name: "remote_success",
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			remote:        true,
+			expectErr:     false,
+			expectedPaths: []string{"/populate", "/populate", "/populate", "/populate", "/populate"},
+			expectedSuccessfulExamples: []string{
+				examples["EPOS GeoJSON"],
+				examples["Coverage JSON"],
+				examples["Downloadable File"],
+				examples["OGC WMS"],
+				examples["OGC WFS"],
+			},
+		},
 		{
 			name: "partial_failure",
 			serverHandler: func(w http.ResponseWriter, r *http.Request) {
@@ -231,6 +668,7 @@ func TestPopulateExample(t *testing.T) {
 					w.WriteHeader(http.StatusOK)
 				}
 			},
+			remote:        true,
 			expectErr:     true,
 			expectedPaths: []string{"/populate", "/populate", "/populate", "/populate", "/populate"},
 			expectedSuccessfulExamples: []string{
@@ -257,18 +695,23 @@ func TestPopulateExample(t *testing.T) {
 
 			var serverURL string
 			var receivedPaths []string
+			uploaded := 0
 			var mu sync.Mutex
 
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
 				mu.Lock()
 				receivedPaths = append(receivedPaths, r.URL.Path)
+				if len(body) > 0 {
+					uploaded++
+				}
 				mu.Unlock()
 				tc.serverHandler(w, r)
 			}))
 			defer server.Close()
 			serverURL = server.URL
 
-			successfulExamples, err := PopulateExample(serverURL, 2)
+			successfulExamples, err := PopulateExample(t.Context(), serverURL, 2, RetryPolicy{}, nil, tc.remote)
 
 			if tc.expectErr {
 				if err == nil {
@@ -285,6 +728,15 @@ func TestPopulateExample(t *testing.T) {
 				t.Errorf("Expected %d requests, but got %d", len(tc.expectedPaths), len(receivedPaths))
 			}
 
+			// The embedded copies are uploaded unless the examples are fetched remotely
+			expectedUploads := pinned
+			if tc.remote {
+				expectedUploads = 0
+			}
+			if uploaded != expectedUploads {
+				t.Errorf("Expected %d uploaded example bodies, but got %d", expectedUploads, uploaded)
+			}
+
 			sort.Strings(successfulExamples)
 			sort.Strings(tc.expectedSuccessfulExamples)
 
@@ -0,0 +1,244 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Well known IRIs used while parsing and serializing RDF.
+const (
+	RDFType    = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+	RDFFirst   = "http://www.w3.org/1999/02/22-rdf-syntax-ns#first"
+	RDFRest    = "http://www.w3.org/1999/02/22-rdf-syntax-ns#rest"
+	RDFNil     = "http://www.w3.org/1999/02/22-rdf-syntax-ns#nil"
+	RDFLangStr = "http://www.w3.org/1999/02/22-rdf-syntax-ns#langString"
+
+	XSDString  = "http://www.w3.org/2001/XMLSchema#string"
+	XSDBoolean = "http://www.w3.org/2001/XMLSchema#boolean"
+	XSDInteger = "http://www.w3.org/2001/XMLSchema#integer"
+	XSDDecimal = "http://www.w3.org/2001/XMLSchema#decimal"
+	XSDDouble  = "http://www.w3.org/2001/XMLSchema#double"
+)
+
+// TermKind identifies the kind of an RDF term.
+type TermKind int
+
+const (
+	// TermIRI is an IRI reference.
+	TermIRI TermKind = iota
+	// TermBlank is a blank node.
+	TermBlank
+	// TermLiteral is a literal value.
+	TermLiteral
+)
+
+// Term is an RDF term: an IRI, a blank node or a literal.
+type Term struct {
+	Kind TermKind
+	// IRI, blank node label or lexical form of the literal
+	Value string
+	// Datatype IRI of a literal. Empty for IRIs and blank nodes
+	Datatype string
+	// Language tag of a literal, if any
+	Lang string
+}
+
+// IRI returns an IRI term.
+func IRI(value string) Term {
+	return Term{Kind: TermIRI, Value: value}
+}
+
+// Blank returns a blank node term with the given label.
+func Blank(label string) Term {
+	return Term{Kind: TermBlank, Value: label}
+}
+
+// Literal returns a literal term. An empty datatype defaults to xsd:string, or rdf:langString when lang is set.
+func Literal(value, datatype, lang string) Term {
+	if lang != "" {
+		datatype = RDFLangStr
+	}
+	if datatype == "" {
+		datatype = XSDString
+	}
+	return Term{Kind: TermLiteral, Value: value, Datatype: datatype, Lang: lang}
+}
+
+// String returns the N-Triples representation of the term.
+func (t Term) String() string {
+	switch t.Kind {
+	case TermIRI:
+		return "<" + escapeIRI(t.Value) + ">"
+	case TermBlank:
+		return "_:" + t.Value
+	default:
+		s := `"` + escapeString(t.Value) + `"`
+		switch {
+		case t.Lang != "":
+			return s + "@" + t.Lang
+		case t.Datatype != "" && t.Datatype != XSDString:
+			return s + "^^<" + escapeIRI(t.Datatype) + ">"
+		}
+		return s
+	}
+}
+
+// Triple is a single RDF statement.
+type Triple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+}
+
+// String returns the N-Triples representation of the triple, without the trailing newline.
+func (t Triple) String() string {
+	return fmt.Sprintf("%s %s %s .", t.Subject, t.Predicate, t.Object)
+}
+
+func escapeString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func escapeIRI(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r <= 0x20, strings.ContainsRune(`<>"{}|^`+"`\\", r):
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Graph is an in-memory set of triples indexed by subject and by object.
+type Graph struct {
+	triples []Triple
+	spo     map[Term]map[Term][]Term
+	ops     map[Term]map[Term][]Term
+	seen    map[Triple]struct{}
+	// number of documents loaded, used to keep blank nodes of different documents apart
+	docs int
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{
+		spo:  map[Term]map[Term][]Term{},
+		ops:  map[Term]map[Term][]Term{},
+		seen: map[Triple]struct{}{},
+	}
+}
+
+// Add inserts a triple into the graph. Duplicate triples are ignored.
+func (g *Graph) Add(t Triple) {
+	if _, ok := g.seen[t]; ok {
+		return
+	}
+	g.seen[t] = struct{}{}
+	g.triples = append(g.triples, t)
+
+	if g.spo[t.Subject] == nil {
+		g.spo[t.Subject] = map[Term][]Term{}
+	}
+	g.spo[t.Subject][t.Predicate] = append(g.spo[t.Subject][t.Predicate], t.Object)
+
+	if g.ops[t.Object] == nil {
+		g.ops[t.Object] = map[Term][]Term{}
+	}
+	g.ops[t.Object][t.Predicate] = append(g.ops[t.Object][t.Predicate], t.Subject)
+}
+
+// Len returns the number of triples in the graph.
+func (g *Graph) Len() int {
+	return len(g.triples)
+}
+
+// Triples returns the triples of the graph in insertion order.
+func (g *Graph) Triples() []Triple {
+	return g.triples
+}
+
+// Objects returns the objects of all triples with the given subject and predicate.
+func (g *Graph) Objects(subject, predicate Term) []Term {
+	return g.spo[subject][predicate]
+}
+
+// Object returns the first object of the triples with the given subject and predicate.
+func (g *Graph) Object(subject, predicate Term) (Term, bool) {
+	objects := g.spo[subject][predicate]
+	if len(objects) == 0 {
+		return Term{}, false
+	}
+	return objects[0], true
+}
+
+// Subjects returns the subjects of all triples with the given predicate and object.
+func (g *Graph) Subjects(predicate, object Term) []Term {
+	return g.ops[object][predicate]
+}
+
+// Predicates returns the predicates used on subject.
+func (g *Graph) Predicates(subject Term) []Term {
+	predicates := make([]Term, 0, len(g.spo[subject]))
+	for p := range g.spo[subject] {
+		predicates = append(predicates, p)
+	}
+	return predicates
+}
+
+// SubjectsWithPredicate returns every subject that has at least one value for predicate.
+func (g *Graph) SubjectsWithPredicate(predicate Term) []Term {
+	var subjects []Term
+	seen := map[Term]struct{}{}
+	for _, t := range g.triples {
+		if t.Predicate != predicate {
+			continue
+		}
+		if _, ok := seen[t.Subject]; !ok {
+			seen[t.Subject] = struct{}{}
+			subjects = append(subjects, t.Subject)
+		}
+	}
+	return subjects
+}
+
+// List returns the members of the RDF collection starting at head.
+func (g *Graph) List(head Term) []Term {
+	var items []Term
+	seen := map[Term]struct{}{}
+	for head != IRI(RDFNil) {
+		if _, ok := seen[head]; ok {
+			break
+		}
+		seen[head] = struct{}{}
+		first, ok := g.Object(head, IRI(RDFFirst))
+		if !ok {
+			break
+		}
+		items = append(items, first)
+		head, ok = g.Object(head, IRI(RDFRest))
+		if !ok {
+			break
+		}
+	}
+	return items
+}
@@ -0,0 +1,248 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RDFFormat identifies the serialization of an RDF document.
+type RDFFormat string
+
+const (
+	// FormatTurtle is Turtle (text/turtle).
+	FormatTurtle RDFFormat = "turtle"
+	// FormatNTriples is N-Triples (application/n-triples). Every N-Triples document is also valid Turtle.
+	FormatNTriples RDFFormat = "n-triples"
+	// FormatJSONLD is JSON-LD (application/ld+json).
+	FormatJSONLD RDFFormat = "json-ld"
+	// FormatRDFXML is RDF/XML (application/rdf+xml).
+	FormatRDFXML RDFFormat = "rdf/xml"
+)
+
+// rdfExtensions maps the file extensions picked up when walking a directory to their format.
+var rdfExtensions = map[string]RDFFormat{
+	".ttl":     FormatTurtle,
+	".nt":      FormatNTriples,
+	".jsonld":  FormatJSONLD,
+	".json-ld": FormatJSONLD,
+	".rdf":     FormatRDFXML,
+	".owl":     FormatRDFXML,
+}
+
+// RDFExtensions returns the file extensions recognized as RDF documents, sorted.
+func RDFExtensions() []string {
+	extensions := make([]string, 0, len(rdfExtensions))
+	for ext := range rdfExtensions {
+		extensions = append(extensions, ext)
+	}
+	sort.Strings(extensions)
+	return extensions
+}
+
+// RDFFormatFromExtension returns the format matching the extension of path.
+func RDFFormatFromExtension(path string) (RDFFormat, bool) {
+	format, ok := rdfExtensions[strings.ToLower(filepath.Ext(path))]
+	return format, ok
+}
+
+// DetectRDFFormat returns the format of the file at path. The extension is used when it is a known RDF
+// extension, otherwise the format is guessed from the beginning of the file content.
+func DetectRDFFormat(path string) (RDFFormat, error) {
+	if format, ok := RDFFormatFromExtension(path); ok {
+		return format, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	head := make([]byte, 1024)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", fmt.Errorf("failed to read file '%s': %w", path, err)
+	}
+
+	return detectRDFFormat(path, head[:n])
+}
+
+// detectRDFFormat returns the format of the file at path from its extension, or from the beginning of its
+// content when the extension is not known.
+func detectRDFFormat(path string, content []byte) (RDFFormat, error) {
+	if format, ok := RDFFormatFromExtension(path); ok {
+		return format, nil
+	}
+	format, ok := sniffRDFFormat(content)
+	if !ok {
+		return "", fmt.Errorf("cannot detect the RDF format of '%s', supported extensions are %s", path, strings.Join(RDFExtensions(), ", "))
+	}
+	return format, nil
+}
+
+// sniffRDFFormat guesses the format of a document from its first bytes.
+func sniffRDFFormat(head []byte) (RDFFormat, bool) {
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	head = bytes.TrimLeft(head, " \t\r\n")
+	if len(head) == 0 {
+		return "", false
+	}
+
+	switch {
+	case head[0] == '{' || head[0] == '[':
+		return FormatJSONLD, true
+	case bytes.HasPrefix(head, []byte("<?xml")), bytes.HasPrefix(head, []byte("<!")):
+		return FormatRDFXML, true
+	case head[0] == '<' && bytes.Contains(head, []byte("xmlns")):
+		return FormatRDFXML, true
+	case head[0] == '<', head[0] == '@', head[0] == '#', head[0] == '_':
+		return FormatTurtle, true
+	}
+
+	lower := bytes.ToLower(head)
+	if bytes.HasPrefix(lower, []byte("prefix")) || bytes.HasPrefix(lower, []byte("base")) {
+		return FormatTurtle, true
+	}
+	return "", false
+}
+
+// ParseRDF parses a document in the given format and returns its triples together with the namespace
+// prefixes declared in the document. Relative IRIs are resolved against base when it is set.
+func ParseRDF(r io.Reader, format RDFFormat, base string) ([]Triple, map[string]string, error) {
+	var triples []Triple
+	emit := func(t Triple) {
+		triples = append(triples, t)
+	}
+
+	var prefixes map[string]string
+	var err error
+	switch format {
+	case FormatTurtle, FormatNTriples:
+		prefixes, err = parseTurtle(r, base, emit)
+	case FormatJSONLD:
+		prefixes, err = parseJSONLD(r, base, emit)
+	case FormatRDFXML:
+		prefixes, err = parseRDFXML(r, base, emit)
+	default:
+		return nil, nil, fmt.Errorf("unsupported RDF format '%s'", format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return triples, prefixes, nil
+}
+
+// ConvertToTurtle converts a document in the given format to Turtle. Turtle and N-Triples documents are
+// returned unchanged, since N-Triples is a subset of Turtle.
+func ConvertToTurtle(content []byte, format RDFFormat) ([]byte, error) {
+	if format == FormatTurtle || format == FormatNTriples {
+		return content, nil
+	}
+
+	triples, prefixes, err := ParseRDF(bytes.NewReader(content), format, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTurtle(&buf, triples, prefixes); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ValidateRDFFile checks that the file at path is a syntactically valid document of its detected format.
+// Syntax errors are returned as *SyntaxError with File set to path.
+func ValidateRDFFile(path string) error {
+	format, err := DetectRDFFormat(path)
+	if err != nil {
+		return err
+	}
+	if format == FormatTurtle || format == FormatNTriples {
+		return ValidateTurtleFile(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	_, _, err = ParseRDF(f, format, "")
+	return fileError(path, format, err)
+}
+
+// validateRDFReader checks that the document in format read from r, the content of the file at path, is
+// syntactically valid. Turtle and N-Triples documents are checked as a stream. Syntax errors are returned as
+// *SyntaxError with File set to path.
+func validateRDFReader(path string, format RDFFormat, r io.Reader) error {
+	if format == FormatTurtle || format == FormatNTriples {
+		return fileError(path, format, ValidateTurtle(r))
+	}
+
+	_, _, err := ParseRDF(r, format, "")
+	return fileError(path, format, err)
+}
+
+// LoadFile parses the file at path in its detected format and adds its triples to the graph.
+// Syntax errors are returned as *SyntaxError with File set to path.
+func (g *Graph) LoadFile(path string) error {
+	format, err := DetectRDFFormat(path)
+	if err != nil {
+		return err
+	}
+	if format == FormatTurtle || format == FormatNTriples {
+		return g.LoadTurtleFile(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	triples, _, err := ParseRDF(f, format, "")
+	if err != nil {
+		return fileError(path, format, err)
+	}
+
+	add := g.documentAdder()
+	for _, t := range triples {
+		add(t)
+	}
+	return nil
+}
+
+// loadReader parses the document in format read from r, the content of the file at path, and adds its
+// triples to the graph. Syntax errors are returned as *SyntaxError with File set to path.
+func (g *Graph) loadReader(path string, format RDFFormat, r io.Reader) error {
+	triples, _, err := ParseRDF(r, format, "")
+	if err != nil {
+		return fileError(path, format, err)
+	}
+
+	add := g.documentAdder()
+	for _, t := range triples {
+		add(t)
+	}
+	return nil
+}
+
+// fileError attaches path to a parse error of a file in the given format.
+func fileError(path string, format RDFFormat, err error) error {
+	if err == nil {
+		return nil
+	}
+	var syntaxErr *SyntaxError
+	if errors.As(err, &syntaxErr) {
+		syntaxErr.File = path
+		return syntaxErr
+	}
+	return fmt.Errorf("invalid %s file '%s': %w", format, path, err)
+}
@@ -0,0 +1,398 @@
+package common
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// tripleStrings returns the N-Triples representation of triples, sorted.
+func tripleStrings(triples []Triple) []string {
+	lines := make([]string, len(triples))
+	for i, t := range triples {
+		lines[i] = t.String()
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestDetectRDFFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		expected  RDFFormat
+		expectErr bool
+	}{
+		{name: "turtle_extension", file: "a.ttl", content: "", expected: FormatTurtle},
+		{name: "ntriples_extension", file: "a.nt", content: "", expected: FormatNTriples},
+		{name: "jsonld_extension", file: "a.JSONLD", content: "", expected: FormatJSONLD},
+		{name: "rdfxml_extension", file: "a.owl", content: "", expected: FormatRDFXML},
+		{name: "json_content", file: "a.json", content: "\ufeff  [{\"@id\": \"urn:ex:s\"}]", expected: FormatJSONLD},
+		{name: "xml_declaration", file: "a.xml", content: `<?xml version="1.0"?><rdf:RDF/>`, expected: FormatRDFXML},
+		{name: "xml_without_declaration", file: "a.xml", content: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"/>`, expected: FormatRDFXML},
+		{name: "ntriples_content", file: "a.txt", content: `<urn:ex:s> <urn:ex:p> "o" .`, expected: FormatTurtle},
+		{name: "turtle_prefix_content", file: "a.txt", content: "PREFIX ex: <urn:ex:>\n", expected: FormatTurtle},
+		{name: "unknown_content", file: "a.txt", content: "hello", expectErr: true},
+		{name: "empty_content", file: "a.txt", content: "", expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), tc.file)
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			format, err := DetectRDFFormat(path)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("Expected an error, got format %s", format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if format != tc.expected {
+				t.Errorf("Expected format %s, got %s", tc.expected, format)
+			}
+		})
+	}
+}
+
+func TestParseJSONLD(t *testing.T) {
+	t.Parallel()
+
+	doc := `{
+  "@context": {
+    "ex": "http://example.org/",
+    "dct": "http://purl.org/dc/terms/",
+    "id": "@id",
+    "type": "@type",
+    "title": {"@id": "dct:title", "@language": "en"},
+    "issued": {"@id": "dct:issued", "@type": "http://www.w3.org/2001/XMLSchema#date"},
+    "rel": {"@id": "ex:rel", "@type": "@id"},
+    "keywords": {"@id": "ex:keyword", "@container": "@language"},
+    "steps": {"@id": "ex:steps", "@container": "@list"},
+    "@base": "http://example.org/base/"
+  },
+  "@graph": [
+    {
+      "id": "ex:dataset",
+      "type": ["ex:Dataset"],
+      "title": "Title",
+      "issued": "2024-01-01",
+      "rel": "relative",
+      "keywords": {"en": "rock", "it": ["roccia"]},
+      "steps": ["a", "b"],
+      "ex:count": 42,
+      "ex:ratio": 1.5,
+      "ex:flag": true,
+      "ex:none": null,
+      "ex:plain": {"@value": "v", "@language": "fr"},
+      "ex:contact": {"type": "ex:Contact", "ex:name": "N"},
+      "undefined": "dropped"
+    },
+    {"@id": "_:x", "ex:p": {"@id": "_:x"}}
+  ]
+}`
+
+	triples, prefixes, err := ParseRDF(strings.NewReader(doc), FormatJSONLD, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{
+		`<http://example.org/dataset> <` + RDFType + `> <http://example.org/Dataset> .`,
+		`<http://example.org/dataset> <http://purl.org/dc/terms/title> "Title"@en .`,
+		`<http://example.org/dataset> <http://purl.org/dc/terms/issued> "2024-01-01"^^<http://www.w3.org/2001/XMLSchema#date> .`,
+		`<http://example.org/dataset> <http://example.org/rel> <http://example.org/base/relative> .`,
+		`<http://example.org/dataset> <http://example.org/keyword> "rock"@en .`,
+		`<http://example.org/dataset> <http://example.org/keyword> "roccia"@it .`,
+		`<http://example.org/dataset> <http://example.org/count> "42"^^<` + XSDInteger + `> .`,
+		`<http://example.org/dataset> <http://example.org/ratio> "1.5E0"^^<` + XSDDouble + `> .`,
+		`<http://example.org/dataset> <http://example.org/flag> "true"^^<` + XSDBoolean + `> .`,
+		`<http://example.org/dataset> <http://example.org/plain> "v"@fr .`,
+	}
+
+	got := map[string]bool{}
+	for _, triple := range triples {
+		got[triple.String()] = true
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("missing triple %s", w)
+		}
+	}
+
+	// 12 statements on ex:dataset, 2 for the contact, 4 for the list, 1 self reference
+	if len(triples) != 19 {
+		t.Errorf("Expected 19 triples, got %d:\n%s", len(triples), strings.Join(tripleStrings(triples), "\n"))
+	}
+
+	if prefixes["ex"] != "http://example.org/" || prefixes["dct"] != "http://purl.org/dc/terms/" {
+		t.Errorf("Expected ex and dct prefixes, got %v", prefixes)
+	}
+}
+
+func TestParseJSONLDErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		doc        string
+		line, col  int
+		errMessage string
+	}{
+		{name: "syntax_error", doc: "{\n  \"@id\": \"urn:ex:s\",\n  \"urn:ex:p\" 1\n}", line: 3, col: 14},
+		{name: "truncated", doc: "{\"@id\": \"urn:ex:s\"", line: 1, col: 19},
+		{name: "trailing_data", doc: "{} {}", line: 1, col: 4},
+		{name: "remote_context", doc: `{"@context": "https://schema.org/", "name": "x"}`, errMessage: "remote context"},
+		{name: "term_without_iri", doc: `{"@context": {"name": {"@type": "@id"}}, "name": "x"}`, errMessage: "no IRI mapping"},
+		{name: "scalar_top_level", doc: `"value"`, errMessage: "must be objects"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := ParseRDF(strings.NewReader(tc.doc), FormatJSONLD, "")
+			if err == nil {
+				t.Fatal("Expected an error, but got nil")
+			}
+
+			if tc.errMessage != "" {
+				if !strings.Contains(err.Error(), tc.errMessage) {
+					t.Errorf("Expected error containing %q, got %v", tc.errMessage, err)
+				}
+				return
+			}
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Expected *SyntaxError, got %T: %v", err, err)
+			}
+			if syntaxErr.Line != tc.line || syntaxErr.Column != tc.col {
+				t.Errorf("Expected error at %d:%d, got %d:%d (%s)", tc.line, tc.col, syntaxErr.Line, syntaxErr.Column, syntaxErr.Msg)
+			}
+		})
+	}
+}
+
+func TestParseRDFXML(t *testing.T) {
+	t.Parallel()
+
+	doc := `<?xml version="1.0" encoding="utf-8"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+         xmlns:ex="http://example.org/"
+         xmlns:dct="http://purl.org/dc/terms/"
+         xml:base="http://example.org/base/">
+  <ex:Dataset rdf:about="http://example.org/dataset" dct:identifier="ID1">
+    <dct:title xml:lang="en">Title</dct:title>
+    <dct:issued rdf:datatype="http://www.w3.org/2001/XMLSchema#date">2024-01-01</dct:issued>
+    <ex:rel rdf:resource="relative"/>
+    <ex:contact>
+      <ex:Contact ex:name="N"/>
+    </ex:contact>
+    <ex:address rdf:parseType="Resource">
+      <ex:city>Rome</ex:city>
+    </ex:address>
+    <ex:steps rdf:parseType="Collection">
+      <rdf:Description rdf:about="http://example.org/a"/>
+      <rdf:Description rdf:about="http://example.org/b"/>
+    </ex:steps>
+    <ex:note rdf:parseType="Literal"><b>bold</b></ex:note>
+  </ex:Dataset>
+  <rdf:Description rdf:ID="local">
+    <ex:p rdf:nodeID="n1"/>
+  </rdf:Description>
+</rdf:RDF>`
+
+	triples, prefixes, err := ParseRDF(strings.NewReader(doc), FormatRDFXML, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{
+		`<http://example.org/dataset> <` + RDFType + `> <http://example.org/Dataset> .`,
+		`<http://example.org/dataset> <http://purl.org/dc/terms/identifier> "ID1" .`,
+		`<http://example.org/dataset> <http://purl.org/dc/terms/title> "Title"@en .`,
+		`<http://example.org/dataset> <http://purl.org/dc/terms/issued> "2024-01-01"^^<http://www.w3.org/2001/XMLSchema#date> .`,
+		`<http://example.org/dataset> <http://example.org/rel> <http://example.org/base/relative> .`,
+		`<http://example.org/dataset> <http://example.org/note> "<b>bold</b>"^^<` + rdfXMLLiteral + `> .`,
+	}
+
+	got := map[string]bool{}
+	for _, triple := range triples {
+		got[triple.String()] = true
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("missing triple %s", w)
+		}
+	}
+
+	var localFound bool
+	for _, triple := range triples {
+		if triple.Subject == IRI("http://example.org/base/#local") && triple.Object.Kind == TermBlank {
+			localFound = true
+		}
+	}
+	if !localFound {
+		t.Error("Expected rdf:ID subject resolved against xml:base with a blank node object")
+	}
+
+	// 9 statements on the dataset, 2 for the contact, 1 for the address, 4 for the list, 1 on #local
+	if len(triples) != 17 {
+		t.Errorf("Expected 17 triples, got %d:\n%s", len(triples), strings.Join(tripleStrings(triples), "\n"))
+	}
+
+	if prefixes["ex"] != "http://example.org/" {
+		t.Errorf("Expected ex prefix, got %v", prefixes)
+	}
+}
+
+func TestParseRDFXMLErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		doc       string
+		line, col int
+	}{
+		{name: "unclosed_element", doc: "<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n  <rdf:Description>\n</rdf:RDF>", line: 3},
+		{name: "unqualified_property", doc: "<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n  <rdf:Description>\n    <title>x</title>\n  </rdf:Description>\n</rdf:RDF>", line: 3, col: 5},
+		{name: "two_node_elements", doc: "<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns:ex=\"urn:ex:\">\n<rdf:Description><ex:p><ex:A/><ex:B/></ex:p></rdf:Description>\n</rdf:RDF>", line: 2, col: 18},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := ParseRDF(strings.NewReader(tc.doc), FormatRDFXML, "")
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Expected *SyntaxError, got %T: %v", err, err)
+			}
+			if syntaxErr.Line != tc.line || (tc.col != 0 && syntaxErr.Column != tc.col) {
+				t.Errorf("Expected error at %d:%d, got %d:%d (%s)", tc.line, tc.col, syntaxErr.Line, syntaxErr.Column, syntaxErr.Msg)
+			}
+		})
+	}
+}
+
+func TestConvertToTurtle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		format   RDFFormat
+		content  string
+		expected []string // prefix declarations expected in the output
+	}{
+		{
+			name:   "jsonld",
+			format: FormatJSONLD,
+			content: `{
+  "@context": {"ex": "http://example.org/", "dcat": "http://www.w3.org/ns/dcat#"},
+  "@id": "ex:dataset",
+  "@type": "dcat:Dataset",
+  "ex:title": ["A \"quoted\"\ntitle", {"@value": "B", "@language": "en"}],
+  "ex:odd": {"@id": "http://example.org/a(b)"},
+  "ex:list": {"@list": [1, 2]},
+  "ex:contact": {"ex:name": "N"}
+}`,
+			expected: []string{"@prefix dcat: <http://www.w3.org/ns/dcat#> .", "@prefix ex: <http://example.org/> ."},
+		},
+		{
+			name:   "rdfxml",
+			format: FormatRDFXML,
+			content: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:dct="http://purl.org/dc/terms/">
+  <rdf:Description rdf:about="http://example.org/dataset">
+    <dct:title>Title</dct:title>
+    <dct:publisher><rdf:Description dct:title="Publisher"/></dct:publisher>
+  </rdf:Description>
+</rdf:RDF>`,
+			expected: []string{"@prefix dct: <http://purl.org/dc/terms/> ."},
+		},
+		{
+			name:    "ntriples_unchanged",
+			format:  FormatNTriples,
+			content: `<urn:ex:s> <urn:ex:p> "o" .`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := ConvertToTurtle([]byte(tc.content), tc.format)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tc.format == FormatNTriples && string(out) != tc.content {
+				t.Errorf("Expected N-Triples to be unchanged, got %s", out)
+			}
+			for _, prefix := range tc.expected {
+				if !strings.Contains(string(out), prefix) {
+					t.Errorf("Expected output to declare %q, got:\n%s", prefix, out)
+				}
+			}
+
+			source, _, err := ParseRDF(strings.NewReader(tc.content), tc.format, "")
+			if err != nil {
+				t.Fatalf("Unexpected error parsing source: %v", err)
+			}
+			converted, err := ParseTurtle(strings.NewReader(string(out)), "")
+			if err != nil {
+				t.Fatalf("Converted output is not valid Turtle: %v\n%s", err, out)
+			}
+
+			// blank node labels differ between the two documents, compare them by their position only
+			normalize := func(triples []Triple) []string {
+				lines := tripleStrings(triples)
+				for i, line := range lines {
+					fields := strings.Fields(line)
+					for j, f := range fields {
+						if strings.HasPrefix(f, "_:") {
+							fields[j] = "_:"
+						}
+					}
+					lines[i] = strings.Join(fields, " ")
+				}
+				sort.Strings(lines)
+				return lines
+			}
+			if got, want := normalize(converted), normalize(source); strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("Converted triples differ from the source:\n got: %v\nwant: %v\noutput:\n%s", got, want, out)
+			}
+		})
+	}
+}
+
+func TestValidateRDFFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "bad.jsonld")
+	if err := os.WriteFile(path, []byte("{\n  \"@id\": \n}"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	err := ValidateRDFFile(path)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Expected *SyntaxError, got %T: %v", err, err)
+	}
+	if syntaxErr.File != path || syntaxErr.Line != 3 {
+		t.Errorf("Expected error at %s:3, got %v", path, err)
+	}
+}
@@ -0,0 +1,336 @@
+package common
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	rdfNS         = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlNS         = "http://www.w3.org/XML/1998/namespace"
+	rdfXMLLiteral = rdfNS + "XMLLiteral"
+)
+
+// xmlElement is an element of an RDF/XML document with the base IRI and language in scope.
+type xmlElement struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*xmlElement
+	text     strings.Builder
+	// raw content between the start and end tags, used for rdf:parseType="Literal"
+	raw      []byte
+	rawStart int64
+	base     string
+	lang     string
+	line     int
+	col      int
+}
+
+func (e *xmlElement) attr(space, local string) (string, bool) {
+	for _, a := range e.attrs {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// rdfxmlParser converts the element tree of an RDF/XML document to triples.
+type rdfxmlParser struct {
+	blanks     map[string]string
+	blankCount int
+	emit       func(Triple)
+}
+
+// rdfxmlFailure carries a syntax error through panics inside the parser.
+type rdfxmlFailure struct {
+	err *SyntaxError
+}
+
+// parseRDFXML parses an RDF/XML document, passing every triple to emit. It returns the namespace prefixes
+// declared in the document.
+func parseRDFXML(r io.Reader, base string, emit func(Triple)) (prefixes map[string]string, err error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root, prefixes, err := readXMLTree(content, base)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &rdfxmlParser{blanks: map[string]string{}, emit: emit}
+	defer func() {
+		if rec := recover(); rec != nil {
+			failure, ok := rec.(rdfxmlFailure)
+			if !ok {
+				panic(rec)
+			}
+			prefixes, err = nil, failure.err
+		}
+	}()
+
+	if root.name.Space == rdfNS && root.name.Local == "RDF" {
+		for _, child := range root.children {
+			p.parseNode(child)
+		}
+	} else {
+		p.parseNode(root)
+	}
+	return prefixes, nil
+}
+
+// readXMLTree reads the element tree of an XML document, resolving xml:base and xml:lang for every element.
+func readXMLTree(content []byte, base string) (*xmlElement, map[string]string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(content))
+	prefixes := map[string]string{}
+
+	var root *xmlElement
+	var stack []*xmlElement
+	for {
+		start := dec.InputOffset()
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				line, col := offsetPosition(content, dec.InputOffset())
+				return nil, nil, &SyntaxError{Line: line, Column: col, Msg: syntaxErr.Msg}
+			}
+			return nil, nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			line, col := offsetPosition(content, start)
+			e := &xmlElement{name: tok.Name, base: base, line: line, col: col}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				e.base, e.lang = parent.base, parent.lang
+				parent.children = append(parent.children, e)
+			} else if root != nil {
+				return nil, nil, &SyntaxError{Line: line, Column: col, Msg: "multiple root elements"}
+			} else {
+				root = e
+			}
+
+			for _, a := range tok.Attr {
+				switch {
+				case a.Name.Space == "xmlns":
+					if _, ok := prefixes[a.Name.Local]; !ok {
+						prefixes[a.Name.Local] = a.Value
+					}
+				case a.Name.Space == "" && a.Name.Local == "xmlns":
+					if _, ok := prefixes[""]; !ok {
+						prefixes[""] = a.Value
+					}
+				case a.Name.Space == xmlNS && a.Name.Local == "base":
+					e.base = resolveIRI(e.base, a.Value)
+				case a.Name.Space == xmlNS && a.Name.Local == "lang":
+					e.lang = a.Value
+				case a.Name.Space == xmlNS:
+				default:
+					e.attrs = append(e.attrs, a)
+				}
+			}
+
+			e.rawStart = dec.InputOffset()
+			stack = append(stack, e)
+		case xml.EndElement:
+			e := stack[len(stack)-1]
+			e.raw = content[e.rawStart:start]
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(tok)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, nil, &SyntaxError{Line: 1, Column: 1, Msg: "document has no root element"}
+	}
+	return root, prefixes, nil
+}
+
+func (p *rdfxmlParser) fail(e *xmlElement, format string, a ...any) {
+	panic(rdfxmlFailure{err: &SyntaxError{Line: e.line, Column: e.col, Msg: fmt.Sprintf(format, a...)}})
+}
+
+func (p *rdfxmlParser) newBlank() Term {
+	p.blankCount++
+	return Blank(fmt.Sprintf("b%d", p.blankCount))
+}
+
+func (p *rdfxmlParser) namedBlank(label string) Term {
+	blank, ok := p.blanks[label]
+	if !ok {
+		blank = p.newBlank().Value
+		p.blanks[label] = blank
+	}
+	return Blank(blank)
+}
+
+// parseNode emits the triples of a node element and returns its subject.
+func (p *rdfxmlParser) parseNode(e *xmlElement) Term {
+	if e.name.Space == "" {
+		p.fail(e, "node element '%s' has no namespace", e.name.Local)
+	}
+	if strings.TrimSpace(e.text.String()) != "" {
+		p.fail(e, "node element '%s' cannot contain text", e.name.Local)
+	}
+
+	var subject Term
+	if about, ok := e.attr(rdfNS, "about"); ok {
+		subject = IRI(resolveIRI(e.base, about))
+	} else if id, ok := e.attr(rdfNS, "ID"); ok {
+		subject = IRI(resolveIRI(e.base, "#"+id))
+	} else if nodeID, ok := e.attr(rdfNS, "nodeID"); ok {
+		subject = p.namedBlank(nodeID)
+	} else {
+		subject = p.newBlank()
+	}
+
+	if e.name.Space != rdfNS || e.name.Local != "Description" {
+		p.emit(Triple{Subject: subject, Predicate: IRI(RDFType), Object: IRI(e.name.Space + e.name.Local)})
+	}
+	p.propertyAttributes(e, subject, "about", "ID", "nodeID")
+
+	li := 0
+	for _, child := range e.children {
+		p.parseProperty(child, subject, &li)
+	}
+	return subject
+}
+
+// propertyAttributes emits the property attributes of e on subject, skipping the given rdf attributes.
+func (p *rdfxmlParser) propertyAttributes(e *xmlElement, subject Term, skip ...string) {
+	for _, a := range e.attrs {
+		if a.Name.Space == "" {
+			continue
+		}
+		if a.Name.Space == rdfNS {
+			switch local := a.Name.Local; {
+			case local == "type":
+				p.emit(Triple{Subject: subject, Predicate: IRI(RDFType), Object: IRI(resolveIRI(e.base, a.Value))})
+				continue
+			case slices.Contains(skip, local), local == "parseType", local == "datatype", local == "resource":
+				continue
+			}
+		}
+		p.emit(Triple{Subject: subject, Predicate: IRI(a.Name.Space + a.Name.Local), Object: Literal(a.Value, "", e.lang)})
+	}
+}
+
+// parseProperty emits the triples of a property element of subject.
+func (p *rdfxmlParser) parseProperty(e *xmlElement, subject Term, li *int) {
+	if e.name.Space == "" {
+		p.fail(e, "property element '%s' has no namespace", e.name.Local)
+	}
+	predicate := IRI(e.name.Space + e.name.Local)
+	if e.name.Space == rdfNS && e.name.Local == "li" {
+		*li++
+		predicate = IRI(rdfNS + "_" + strconv.Itoa(*li))
+	}
+
+	var object Term
+	parseType, hasParseType := e.attr(rdfNS, "parseType")
+	switch {
+	case hasParseType && parseType == "Resource":
+		object = p.newBlank()
+		li := 0
+		for _, child := range e.children {
+			p.parseProperty(child, object, &li)
+		}
+	case hasParseType && parseType == "Collection":
+		var items []Term
+		for _, child := range e.children {
+			items = append(items, p.parseNode(child))
+		}
+		object = p.list(items)
+	case hasParseType:
+		object = Literal(string(e.raw), rdfXMLLiteral, "")
+	case len(e.children) > 1:
+		p.fail(e, "property element '%s' must contain at most one node element", e.name.Local)
+	case len(e.children) == 1:
+		if strings.TrimSpace(e.text.String()) != "" {
+			p.fail(e, "property element '%s' cannot mix text and elements", e.name.Local)
+		}
+		object = p.parseNode(e.children[0])
+	default:
+		resource, hasResource := e.attr(rdfNS, "resource")
+		nodeID, hasNodeID := e.attr(rdfNS, "nodeID")
+		switch {
+		case hasResource:
+			object = IRI(resolveIRI(e.base, resource))
+		case hasNodeID:
+			object = p.namedBlank(nodeID)
+		case hasPropertyAttributes(e):
+			object = p.newBlank()
+		default:
+			datatype, _ := e.attr(rdfNS, "datatype")
+			lang := e.lang
+			if datatype != "" {
+				datatype, lang = resolveIRI(e.base, datatype), ""
+			}
+			object = Literal(e.text.String(), datatype, lang)
+		}
+		if object.Kind != TermLiteral {
+			p.propertyAttributes(e, object, "ID", "nodeID")
+		}
+	}
+
+	statement := Triple{Subject: subject, Predicate: predicate, Object: object}
+	p.emit(statement)
+
+	// rdf:ID on a property element reifies the statement
+	if id, ok := e.attr(rdfNS, "ID"); ok {
+		reified := IRI(resolveIRI(e.base, "#"+id))
+		p.emit(Triple{Subject: reified, Predicate: IRI(RDFType), Object: IRI(rdfNS + "Statement")})
+		p.emit(Triple{Subject: reified, Predicate: IRI(rdfNS + "subject"), Object: statement.Subject})
+		p.emit(Triple{Subject: reified, Predicate: IRI(rdfNS + "predicate"), Object: statement.Predicate})
+		p.emit(Triple{Subject: reified, Predicate: IRI(rdfNS + "object"), Object: statement.Object})
+	}
+}
+
+func (p *rdfxmlParser) list(items []Term) Term {
+	if len(items) == 0 {
+		return IRI(RDFNil)
+	}
+
+	head := p.newBlank()
+	current := head
+	for i, item := range items {
+		p.emit(Triple{Subject: current, Predicate: IRI(RDFFirst), Object: item})
+		rest := IRI(RDFNil)
+		if i < len(items)-1 {
+			rest = p.newBlank()
+		}
+		p.emit(Triple{Subject: current, Predicate: IRI(RDFRest), Object: rest})
+		current = rest
+	}
+	return head
+}
+
+// hasPropertyAttributes reports whether an empty property element has attributes describing a blank node.
+func hasPropertyAttributes(e *xmlElement) bool {
+	for _, a := range e.attrs {
+		if a.Name.Space == "" {
+			continue
+		}
+		if a.Name.Space == rdfNS && a.Name.Local != "type" {
+			continue
+		}
+		return true
+	}
+	return false
+}
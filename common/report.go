@@ -0,0 +1,304 @@
+package common
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// ReportFormat is the output format of a PopulateReport.
+type ReportFormat string
+
+const (
+	// ReportJSON writes the report as a JSON document.
+	ReportJSON ReportFormat = "json"
+	// ReportJUnit writes the report as JUnit XML, with one test case per file.
+	ReportJUnit ReportFormat = "junit"
+)
+
+// ParseReportFormat returns the report format named by s.
+func ParseReportFormat(s string) (ReportFormat, error) {
+	switch f := ReportFormat(s); f {
+	case ReportJSON, ReportJUnit:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid report format %q, must be one of: %s, %s", s, ReportJSON, ReportJUnit)
+}
+
+// FileStatus is the outcome of the ingestion of a single file.
+type FileStatus string
+
+const (
+	// FileIngested means the file was posted successfully.
+	FileIngested FileStatus = "ingested"
+	// FileSkipped means the file was not posted, because it did not change or because other files are invalid.
+	FileSkipped FileStatus = "skipped"
+	// FileInvalid means the file failed the local syntax check and was not posted.
+	FileInvalid FileStatus = "invalid"
+	// FileFailed means the file could not be read or was rejected by the gateway.
+	FileFailed FileStatus = "failed"
+)
+
+// FileResult is the outcome of the ingestion of a single file or example.
+type FileResult struct {
+	// Absolute path of the file, or URL of the example
+	Path   string     `json:"path"`
+	Status FileStatus `json:"status"`
+	// HTTP status code of the last response of the gateway. Zero when no response was received
+	StatusCode int `json:"statusCode,omitempty"`
+	// Error that made the file fail or be skipped
+	Error string `json:"error,omitempty"`
+	// Body of the last error response of the gateway
+	ErrorBody string `json:"errorBody,omitempty"`
+	// Time spent uploading the file, including retries
+	Duration time.Duration `json:"-"`
+	// Size of the file content in bytes
+	SizeBytes int64 `json:"sizeBytes"`
+}
+
+// MarshalJSON encodes the duration of the result in seconds.
+func (r FileResult) MarshalJSON() ([]byte, error) {
+	type result FileResult
+	return json.Marshal(struct {
+		result
+		DurationSeconds float64 `json:"durationSeconds"`
+	}{result(r), r.Duration.Seconds()})
+}
+
+// PopulateReport collects the per-file outcome of a populate run. It is safe for concurrent use, and a nil
+// report ignores every result.
+type PopulateReport struct {
+	mu sync.Mutex
+
+	// Name of the populated environment
+	Environment string       `json:"environment"`
+	StartedAt   time.Time    `json:"startedAt"`
+	FinishedAt  time.Time    `json:"finishedAt"`
+	Files       []FileResult `json:"files"`
+}
+
+// NewPopulateReport returns an empty report for the named environment, started now.
+func NewPopulateReport(environment string) *PopulateReport {
+	return &PopulateReport{
+		Environment: environment,
+		StartedAt:   time.Now(),
+		Files:       []FileResult{},
+	}
+}
+
+// Add records the outcome of a file.
+func (r *PopulateReport) Add(result FileResult) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Files = append(r.Files, result)
+}
+
+// Failures returns the results of the files that are invalid or failed to ingest, in the order they were recorded.
+func (r *PopulateReport) Failures() []FileResult {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var failures []FileResult
+	for _, f := range r.Files {
+		if f.Status == FileInvalid || f.Status == FileFailed {
+			failures = append(failures, f)
+		}
+	}
+	return failures
+}
+
+// PrintFailures prints a summary table of the files that are invalid or failed to ingest, if any.
+func (r *PopulateReport) PrintFailures() {
+	var failures []display.FailedFile
+	for _, f := range r.Failures() {
+		failures = append(failures, display.FailedFile{
+			Path:   f.Path,
+			Status: string(f.Status),
+			Reason: strings.Join(strings.Fields(f.Error), " "),
+		})
+	}
+	display.FailureSummary(failures)
+}
+
+// Write writes the report to w in the given format. The finish time is set to now.
+func (r *PopulateReport) Write(w io.Writer, format ReportFormat) error {
+	r.mu.Lock()
+	r.FinishedAt = time.Now()
+	r.mu.Unlock()
+
+	switch format {
+	case ReportJSON:
+		return r.writeJSON(w)
+	case ReportJUnit:
+		return r.writeJUnit(w)
+	}
+	return fmt.Errorf("invalid report format %q", format)
+}
+
+// WriteFile writes the report to the file at path in the given format, creating the parent directory if needed.
+func (r *PopulateReport) WriteFile(path string, format ReportFormat) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory for report '%s': %w", path, err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file '%s': %w", path, err)
+	}
+
+	if err := r.Write(f, format); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write report file '%s': %w", path, err)
+	}
+	return f.Close()
+}
+
+func (r *PopulateReport) writeJSON(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the report as a single test suite. Files rejected by the gateway are failures, files
+// that could not be read or are invalid are errors.
+func (r *PopulateReport) writeJUnit(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	suite := junitTestSuite{
+		Name:      "populate " + r.Environment,
+		Tests:     len(r.Files),
+		Time:      junitSeconds(r.FinishedAt.Sub(r.StartedAt)),
+		Timestamp: r.StartedAt.UTC().Format("2006-01-02T15:04:05"),
+		Cases:     []junitTestCase{},
+	}
+
+	for _, f := range r.Files {
+		tc := junitTestCase{
+			Name:      f.Path,
+			Classname: "populate." + r.Environment,
+			Time:      junitSeconds(f.Duration),
+			SystemOut: fmt.Sprintf("size: %d bytes", f.SizeBytes),
+		}
+		if f.StatusCode != 0 {
+			tc.SystemOut += fmt.Sprintf("\nHTTP status: %d", f.StatusCode)
+		}
+
+		switch f.Status {
+		case FileSkipped:
+			suite.Skipped++
+			tc.Skipped = &junitMessage{Message: f.Error}
+		case FileInvalid:
+			suite.Errors++
+			tc.Error = &junitMessage{Message: f.Error, Type: "SyntaxError"}
+		case FileFailed:
+			if f.StatusCode != 0 {
+				suite.Failures++
+				tc.Failure = &junitMessage{Message: f.Error, Type: fmt.Sprintf("HTTP %d", f.StatusCode), Text: f.ErrorBody}
+			} else {
+				suite.Errors++
+				tc.Error = &junitMessage{Message: f.Error, Type: "Error"}
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	suites := junitTestSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// fileResultFromError returns the result of a file that failed with err, extracting the HTTP status code
+// and body of gateway rejections.
+func fileResultFromError(path string, size int64, duration time.Duration, err error) FileResult {
+	result := FileResult{
+		Path:      path,
+		Status:    FileFailed,
+		Error:     err.Error(),
+		Duration:  duration,
+		SizeBytes: size,
+	}
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		result.StatusCode = statusErr.StatusCode
+		result.ErrorBody = statusErr.Body
+	}
+	return result
+}
@@ -0,0 +1,219 @@
+package common
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseReportFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input     string
+		expected  ReportFormat
+		expectErr bool
+	}{
+		{input: "json", expected: ReportJSON},
+		{input: "junit", expected: ReportJUnit},
+		{input: "xml", expectErr: true},
+		{input: "", expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			format, err := ParseReportFormat(tc.input)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("Expected an error for %q, but got nil", tc.input)
+				}
+				return
+			}
+			if err != nil || format != tc.expected {
+				t.Errorf("Expected %q, got %q and error %v", tc.expected, format, err)
+			}
+		})
+	}
+}
+
+// populateWithReport populates files in a temporary directory against a gateway rejecting every file whose
+// content contains "bad", with a.ttl marked as unchanged since the last ingestion.
+func populateWithReport(t *testing.T, files map[string]string, keepGoing bool) (string, *PopulateReport) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	for path, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, path), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "bad") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("invalid distribution"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// without keepGoing the first failure aborts the other uploads, so the files are uploaded in order
+	parallel := 1
+	if keepGoing {
+		parallel = 2
+	}
+
+	sum := sha256.Sum256([]byte(files["a.ttl"]))
+	report := NewPopulateReport("test-env")
+	_, err := PopulateEnv(t.Context(), PopulateEnvOpts{
+		Path:        tmpDir,
+		EndpointURL: server.URL,
+		Parallel:    parallel,
+		Ingested:    map[string]string{filepath.Join(tmpDir, "a.ttl"): hex.EncodeToString(sum[:])},
+		Report:      report,
+		KeepGoing:   keepGoing,
+	})
+	if err == nil {
+		t.Fatal("Expected an error, but got nil")
+	}
+	return tmpDir, report
+}
+
+func TestPopulateReport(t *testing.T) {
+	t.Parallel()
+
+	tmpDir, report := populateWithReport(t, map[string]string{
+		"a.ttl": ttl("content a"),
+		"b.ttl": ttl("content b"),
+		"c.ttl": ttl("bad content"),
+	}, false)
+
+	expected := map[string]FileResult{
+		"a.ttl": {Status: FileSkipped},
+		"b.ttl": {Status: FileIngested},
+		"c.ttl": {Status: FileFailed, StatusCode: http.StatusBadRequest, ErrorBody: "invalid distribution"},
+	}
+	if len(report.Files) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(report.Files))
+	}
+	for _, result := range report.Files {
+		want, ok := expected[filepath.Base(result.Path)]
+		if !ok || filepath.Dir(result.Path) != tmpDir {
+			t.Errorf("Unexpected result for %s", result.Path)
+			continue
+		}
+		if result.Status != want.Status || result.StatusCode != want.StatusCode || result.ErrorBody != want.ErrorBody {
+			t.Errorf("Unexpected result for %s: %+v", result.Path, result)
+		}
+		if result.SizeBytes == 0 {
+			t.Errorf("Expected the size of %s to be recorded", result.Path)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, ReportJSON); err != nil {
+		t.Fatalf("Failed to write JSON report: %v", err)
+	}
+	var decoded struct {
+		Environment string `json:"environment"`
+		Files       []struct {
+			Path            string  `json:"path"`
+			Status          string  `json:"status"`
+			StatusCode      int     `json:"statusCode"`
+			DurationSeconds float64 `json:"durationSeconds"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Failed to decode JSON report: %v", err)
+	}
+	if decoded.Environment != "test-env" || len(decoded.Files) != len(expected) {
+		t.Errorf("Unexpected JSON report: %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := report.Write(&buf, ReportJUnit); err != nil {
+		t.Fatalf("Failed to write JUnit report: %v", err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("Failed to decode JUnit report: %v", err)
+	}
+	if suites.Tests != 3 || suites.Failures != 1 || suites.Errors != 0 || suites.Skipped != 1 || len(suites.Suites) != 1 {
+		t.Errorf("Unexpected JUnit totals: %s", buf.String())
+	}
+	for _, tc := range suites.Suites[0].Cases {
+		if filepath.Base(tc.Name) == "c.ttl" && (tc.Failure == nil || tc.Failure.Text != "invalid distribution") {
+			t.Errorf("Expected c.ttl to fail with the gateway response body, got %+v", tc.Failure)
+		}
+	}
+}
+
+func TestPopulateReportInvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	_, report := populateWithReport(t, map[string]string{
+		"a.ttl": ttl("content a"),
+		"b.ttl": "<urn:ex:s> <urn:ex:p> .",
+	}, false)
+
+	statuses := map[string]FileStatus{}
+	for _, result := range report.Files {
+		statuses[filepath.Base(result.Path)] = result.Status
+	}
+	if statuses["a.ttl"] != FileSkipped || statuses["b.ttl"] != FileInvalid {
+		t.Errorf("Expected a.ttl to be skipped and b.ttl invalid, got %v", statuses)
+	}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, ReportJUnit); err != nil {
+		t.Fatalf("Failed to write JUnit report: %v", err)
+	}
+	if !strings.Contains(buf.String(), `<error message=`) {
+		t.Errorf("Expected the invalid file to be reported as an error: %s", buf.String())
+	}
+}
+
+func TestPopulateReportKeepGoing(t *testing.T) {
+	t.Parallel()
+
+	_, report := populateWithReport(t, map[string]string{
+		"a.ttl": ttl("content a"),
+		"b.ttl": "<urn:ex:s> <urn:ex:p> .",
+		"c.ttl": ttl("bad content"),
+		"d.ttl": ttl("content d"),
+	}, true)
+
+	statuses := map[string]FileStatus{}
+	for _, result := range report.Files {
+		statuses[filepath.Base(result.Path)] = result.Status
+	}
+	expected := map[string]FileStatus{"a.ttl": FileSkipped, "b.ttl": FileInvalid, "c.ttl": FileFailed, "d.ttl": FileIngested}
+	if !maps.Equal(statuses, expected) {
+		t.Errorf("Expected %v, got %v", expected, statuses)
+	}
+
+	failed := map[string]FileStatus{}
+	for _, result := range report.Failures() {
+		if result.Error == "" {
+			t.Errorf("Expected the reason of the failure of %s", result.Path)
+		}
+		failed[filepath.Base(result.Path)] = result.Status
+	}
+	if !maps.Equal(failed, map[string]FileStatus{"b.ttl": FileInvalid, "c.ttl": FileFailed}) {
+		t.Errorf("Expected b.ttl and c.ttl to be listed as failures, got %v", failed)
+	}
+}
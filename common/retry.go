@@ -0,0 +1,119 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRetryAfter caps the delay requested by a server through the Retry-After header.
+const maxRetryAfter = 5 * time.Minute
+
+// DefaultRetryableStatusCodes are the HTTP status codes retried by DefaultRetryPolicy.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy configures how failed uploads are retried. The zero value disables retries.
+type RetryPolicy struct {
+	// Maximum number of retries after the first attempt
+	MaxRetries int
+	// Delay before the first retry, doubled on every further retry
+	Backoff time.Duration
+	// Upper bound of the delay between two attempts. Zero means no bound
+	MaxBackoff time.Duration
+	// HTTP status codes that are retried. Network errors are always retried
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured explicitly.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:           3,
+		Backoff:              2 * time.Second,
+		MaxBackoff:           30 * time.Second,
+		RetryableStatusCodes: slices.Clone(DefaultRetryableStatusCodes),
+	}
+}
+
+// Validate checks that the retry policy values are usable.
+func (r RetryPolicy) Validate() error {
+	if r.MaxRetries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	if r.Backoff < 0 || r.MaxBackoff < 0 {
+		return fmt.Errorf("retry backoff must not be negative")
+	}
+	for _, code := range r.RetryableStatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid retryable HTTP status code: %d", code)
+		}
+	}
+	return nil
+}
+
+// delay returns how long to wait before the given retry (0 is the first retry). A positive retryAfter,
+// as requested by the server, takes precedence over the exponential backoff.
+func (r RetryPolicy) delay(retry int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, maxRetryAfter)
+	}
+
+	d := r.Backoff
+	for range retry {
+		d *= 2
+		if r.MaxBackoff > 0 && d >= r.MaxBackoff {
+			break
+		}
+	}
+	if r.MaxBackoff > 0 && d > r.MaxBackoff {
+		d = r.MaxBackoff
+	}
+	return d
+}
+
+func (r RetryPolicy) retryable(err error) bool {
+	if statusErr, ok := err.(*httpStatusError); ok {
+		return slices.Contains(r.RetryableStatusCodes, statusErr.StatusCode)
+	}
+	return true
+}
+
+// httpStatusError is returned when the server answers an upload with a non successful status code.
+type httpStatusError struct {
+	Name       string
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("server rejected '%s' with status %d: %s", e.Name, e.StatusCode, e.Body)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,135 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPopulateEnvRetry(t *testing.T) {
+	t.Parallel()
+
+	retry := RetryPolicy{
+		MaxRetries:           2,
+		Backoff:              time.Millisecond,
+		MaxBackoff:           5 * time.Millisecond,
+		RetryableStatusCodes: DefaultRetryableStatusCodes,
+	}
+
+	tests := []struct {
+		name             string
+		statuses         []int // status returned for each attempt, the last one is repeated
+		retryAfter       string
+		expectErr        bool
+		expectedAttempts int32
+	}{
+		{
+			name:             "retry_after_then_success",
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			retryAfter:       "0",
+			expectedAttempts: 2,
+		},
+		{
+			name:             "gives_up_after_max_retries",
+			statuses:         []int{http.StatusBadGateway},
+			expectErr:        true,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "non_retryable_status",
+			statuses:         []int{http.StatusBadRequest},
+			expectErr:        true,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "a.ttl")
+			if err := os.WriteFile(path, []byte(ttl("content a")), 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := int(attempts.Add(1)) - 1
+				status := tc.statuses[min(attempt, len(tc.statuses)-1)]
+				if tc.retryAfter != "" && status != http.StatusOK {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			files, err := PopulateEnv(t.Context(), PopulateEnvOpts{
+				Path:        path,
+				EndpointURL: server.URL,
+				Parallel:    1,
+				Retry:       retry,
+			})
+
+			if tc.expectErr && err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+			if !tc.expectErr && (err != nil || len(files) != 1) {
+				t.Errorf("Expected the file to be ingested, got %d file(s) and error %v", len(files), err)
+			}
+			if got := attempts.Load(); got != tc.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tc.expectedAttempts, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	tests := []struct {
+		retry      int
+		retryAfter time.Duration
+		expected   time.Duration
+	}{
+		{retry: 0, expected: time.Second},
+		{retry: 1, expected: 2 * time.Second},
+		{retry: 2, expected: 4 * time.Second},
+		{retry: 3, expected: 5 * time.Second},
+		{retry: 100, expected: 5 * time.Second},
+		{retry: 0, retryAfter: 10 * time.Second, expected: 10 * time.Second},
+		{retry: 0, retryAfter: time.Hour, expected: maxRetryAfter},
+	}
+
+	for _, tc := range tests {
+		if got := policy.delay(tc.retry, tc.retryAfter); got != tc.expected {
+			t.Errorf("delay(%d, %s): expected %s, got %s", tc.retry, tc.retryAfter, tc.expected, got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]time.Duration{
+		"":                              0,
+		"5":                             5 * time.Second,
+		"-1":                            0,
+		"Wed, 01 Jan 2025 12:00:30 GMT": 30 * time.Second,
+		"Wed, 01 Jan 2025 11:00:00 GMT": 0,
+		"soon":                          0,
+	}
+
+	for value, expected := range tests {
+		if got := parseRetryAfter(value, now); got != expected {
+			t.Errorf("parseRetryAfter(%q): expected %s, got %s", value, expected, got)
+		}
+	}
+}
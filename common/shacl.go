@@ -0,0 +1,813 @@
+package common
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/EPOS-ERIC/epos-opensource/common/offline"
+)
+
+const (
+	shaclNS        = "http://www.w3.org/ns/shacl#"
+	rdfsSubClassOf = "http://www.w3.org/2000/01/rdf-schema#subClassOf"
+	rdfsClass      = "http://www.w3.org/2000/01/rdf-schema#Class"
+	owlClass       = "http://www.w3.org/2002/07/owl#Class"
+
+	// DefaultShapes is the embedded shape graph used when no shapes are given to ValidateMetadata: the
+	// upstream shapes of the default ingestion model, as registered in the ingestor.
+	DefaultShapes = DefaultIngestionModel
+
+	// CoreShapes is the embedded core subset of the EPOS-DCAT-AP V1 shapes, which only checks the mandatory
+	// identifiers. It is only used when asked for explicitly.
+	CoreShapes = "EPOS-DCAT-AP-V1-CORE"
+
+	// maximum nesting of sh:node, sh:and, sh:or, sh:xone and sh:not evaluations
+	maxShapeDepth = 32
+)
+
+//go:embed shapes/*.ttl
+var shapeFiles embed.FS
+
+// coreShapeFiles maps the names of the shape subsets embedded in the binary to their file. The other embedded
+// shape graphs are the base ontologies of the offline sources.
+var coreShapeFiles = map[string]string{
+	CoreShapes: "shapes/epos-dcat-ap_v1_core.ttl",
+}
+
+// knownPrefixes is used to shorten IRIs in human readable reports.
+var knownPrefixes = []struct{ prefix, namespace string }{
+	{"sh", shaclNS},
+	{"rdf", "http://www.w3.org/1999/02/22-rdf-syntax-ns#"},
+	{"rdfs", "http://www.w3.org/2000/01/rdf-schema#"},
+	{"xsd", "http://www.w3.org/2001/XMLSchema#"},
+	{"owl", "http://www.w3.org/2002/07/owl#"},
+	{"dcat", "http://www.w3.org/ns/dcat#"},
+	{"dct", "http://purl.org/dc/terms/"},
+	{"schema", "http://schema.org/"},
+	{"epos", "https://www.epos-eu.org/epos-dcat-ap#"},
+	{"hydra", "http://www.w3.org/ns/hydra/core#"},
+	{"skos", "http://www.w3.org/2004/02/skos/core#"},
+	{"foaf", "http://xmlns.com/foaf/0.1/"},
+	{"vcard", "http://www.w3.org/2006/vcard/ns#"},
+	{"adms", "http://www.w3.org/ns/adms#"},
+	{"locn", "http://www.w3.org/ns/locn#"},
+}
+
+// EmbeddedShapeNames returns the sorted names of the shape graphs embedded in the binary: the base ontologies
+// of the offline sources and the core subsets.
+func EmbeddedShapeNames() []string {
+	var names []string
+	for _, src := range offline.Sources().Ontologies {
+		if src.Type == OntologyTypeBase {
+			names = append(names, src.Name)
+		}
+	}
+	for name := range coreShapeFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidationResult is a single SHACL validation result.
+type ValidationResult struct {
+	FocusNode  string `json:"focusNode"`
+	Path       string `json:"path,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Severity   string `json:"severity"`
+	Constraint string `json:"constraint"`
+	Shape      string `json:"shape"`
+	Message    string `json:"message"`
+}
+
+// FocusNodeReport groups the validation results of a single focus node.
+type FocusNodeReport struct {
+	FocusNode string             `json:"focusNode"`
+	File      string             `json:"file,omitempty"`
+	Results   []ValidationResult `json:"results"`
+}
+
+// ValidationReport is the outcome of a SHACL validation.
+type ValidationReport struct {
+	// true when no validation result was produced
+	Conforms   bool              `json:"conforms"`
+	Files      []string          `json:"files"`
+	Shapes     []string          `json:"shapes"`
+	Violations int               `json:"violations"`
+	Warnings   int               `json:"warnings"`
+	Infos      int               `json:"infos"`
+	FocusNodes []FocusNodeReport `json:"focusNodes"`
+}
+
+// ValidateMetadataOpts defines inputs for ValidateMetadata.
+type ValidateMetadataOpts struct {
+	// Required. RDF files (Turtle, N-Triples, JSON-LD or RDF/XML) or directories containing RDF files to validate
+	Paths []string
+	// Optional. names of embedded shape graphs or paths to Turtle shape files. Defaults to DefaultShapes
+	Shapes []string
+}
+
+// ValidateMetadata validates the given RDF files against SHACL shape graphs without contacting any
+// environment. All files are merged into a single data graph so that references between files resolve.
+// The report groups the results by focus node. Files that cannot be parsed make the validation fail.
+func ValidateMetadata(opts ValidateMetadataOpts) (*ValidationReport, error) {
+	shapeSources := opts.Shapes
+	if len(shapeSources) == 0 {
+		shapeSources = []string{DefaultShapes}
+	}
+
+	shapes := NewGraph()
+	for _, source := range shapeSources {
+		if err := loadShapes(shapes, source); err != nil {
+			return nil, err
+		}
+	}
+
+	var files []string
+	for _, p := range opts.Paths {
+		pathFiles, _, err := collectRDFFiles(p, PathFilter{})
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, pathFiles...)
+	}
+
+	data := NewGraph()
+	// file in which each subject is first described, keyed by its report representation
+	fileOf := map[string]string{}
+	for _, file := range files {
+		n := data.Len()
+		if err := data.LoadFile(file); err != nil {
+			return nil, err
+		}
+		for _, t := range data.Triples()[n:] {
+			if _, ok := fileOf[nodeString(t.Subject)]; !ok {
+				fileOf[nodeString(t.Subject)] = file
+			}
+		}
+	}
+
+	v := &shaclValidator{data: data, shapes: shapes}
+	results := v.run()
+
+	report := &ValidationReport{
+		Conforms:   len(results) == 0,
+		Files:      files,
+		Shapes:     shapeSources,
+		FocusNodes: []FocusNodeReport{},
+	}
+
+	byFocus := map[string]int{}
+	for _, result := range results {
+		switch result.Severity {
+		case "Violation":
+			report.Violations++
+		case "Warning":
+			report.Warnings++
+		default:
+			report.Infos++
+		}
+
+		i, ok := byFocus[result.FocusNode]
+		if !ok {
+			i = len(report.FocusNodes)
+			byFocus[result.FocusNode] = i
+			report.FocusNodes = append(report.FocusNodes, FocusNodeReport{
+				FocusNode: result.FocusNode,
+				File:      fileOf[result.FocusNode],
+			})
+		}
+		report.FocusNodes[i].Results = append(report.FocusNodes[i].Results, result)
+	}
+
+	return report, nil
+}
+
+// loadShapes adds to g the shape graph source: the name of a base ontology of the offline sources, loaded from
+// its embedded copy, the name of an embedded core subset or the path of a Turtle file.
+func loadShapes(g *Graph, source string) error {
+	if src, ok := embeddedOntology(source); ok && src.Type == OntologyTypeBase {
+		content, err := src.Content()
+		if err != nil {
+			return fmt.Errorf("failed to load embedded shapes '%s', pass --shapes %s to only check the mandatory identifiers: %w", source, CoreShapes, err)
+		}
+		if err := g.LoadTurtle(bytes.NewReader(content), ""); err != nil {
+			return fmt.Errorf("failed to parse embedded shapes '%s': %w", source, err)
+		}
+		return nil
+	}
+
+	if file, ok := coreShapeFiles[source]; ok {
+		f, err := shapeFiles.Open(file)
+		if err != nil {
+			return fmt.Errorf("failed to open embedded shapes '%s': %w", source, err)
+		}
+		defer f.Close()
+
+		if err := g.LoadTurtle(f, ""); err != nil {
+			return fmt.Errorf("failed to parse embedded shapes '%s': %w", source, err)
+		}
+		return nil
+	}
+
+	if err := g.LoadTurtleFile(source); err != nil {
+		return fmt.Errorf("failed to load shapes '%s' (embedded shapes: %s): %w", source, strings.Join(EmbeddedShapeNames(), ", "), err)
+	}
+	return nil
+}
+
+// WriteText writes a human readable version of the report to w.
+func (r *ValidationReport) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Validated %d file(s) against %s\n", len(r.Files), strings.Join(r.Shapes, ", "))
+	for _, focus := range r.FocusNodes {
+		b.WriteString("\n")
+		b.WriteString(compactIRIs(focus.FocusNode))
+		if focus.File != "" {
+			fmt.Fprintf(&b, " (%s)", focus.File)
+		}
+		b.WriteString("\n")
+		for _, result := range focus.Results {
+			fmt.Fprintf(&b, "  [%s] ", result.Severity)
+			if result.Path != "" {
+				fmt.Fprintf(&b, "%s: ", compactIRIs(result.Path))
+			}
+			b.WriteString(compactIRIs(result.Message))
+			if result.Value != "" {
+				fmt.Fprintf(&b, " (value: %s)", compactIRIs(result.Value))
+			}
+			fmt.Fprintf(&b, " [%s]\n", compactIRIs(result.Constraint))
+		}
+	}
+
+	b.WriteString("\n")
+	if r.Conforms {
+		b.WriteString("Result: conforms\n")
+	} else {
+		fmt.Fprintf(&b, "Result: does not conform, %d violation(s), %d warning(s), %d info(s) on %d focus node(s)\n",
+			r.Violations, r.Warnings, r.Infos, len(r.FocusNodes))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func compactIRIs(s string) string {
+	for _, p := range knownPrefixes {
+		s = strings.ReplaceAll(s, p.namespace, p.prefix+":")
+	}
+	return s
+}
+
+func sh(name string) Term {
+	return IRI(shaclNS + name)
+}
+
+type shaclValidator struct {
+	data   *Graph
+	shapes *Graph
+	depth  int
+}
+
+func (v *shaclValidator) run() []ValidationResult {
+	var results []ValidationResult
+	for _, shape := range v.shapeNodes() {
+		for _, focus := range v.targets(shape) {
+			results = append(results, v.validateShape(shape, focus)...)
+		}
+	}
+	return results
+}
+
+// shapeNodes returns the shapes that declare targets, sorted for a stable output.
+func (v *shaclValidator) shapeNodes() []Term {
+	set := map[Term]struct{}{}
+	for _, target := range []string{"targetClass", "targetNode", "targetSubjectsOf", "targetObjectsOf"} {
+		for _, shape := range v.shapes.SubjectsWithPredicate(sh(target)) {
+			set[shape] = struct{}{}
+		}
+	}
+	for _, class := range []string{rdfsClass, owlClass} {
+		for _, shape := range v.shapes.Subjects(IRI(RDFType), IRI(class)) {
+			if v.isShape(shape) {
+				set[shape] = struct{}{}
+			}
+		}
+	}
+
+	shapes := make([]Term, 0, len(set))
+	for shape := range set {
+		shapes = append(shapes, shape)
+	}
+	sort.Slice(shapes, func(i, j int) bool {
+		return shapes[i].String() < shapes[j].String()
+	})
+	return shapes
+}
+
+func (v *shaclValidator) isShape(node Term) bool {
+	for _, t := range v.shapes.Objects(node, IRI(RDFType)) {
+		if t == sh("NodeShape") || t == sh("PropertyShape") {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *shaclValidator) targets(shape Term) []Term {
+	var focus []Term
+	seen := map[Term]struct{}{}
+	add := func(nodes ...Term) {
+		for _, n := range nodes {
+			if _, ok := seen[n]; !ok {
+				seen[n] = struct{}{}
+				focus = append(focus, n)
+			}
+		}
+	}
+
+	add(v.shapes.Objects(shape, sh("targetNode"))...)
+	for _, class := range v.shapes.Objects(shape, sh("targetClass")) {
+		add(v.instances(class)...)
+	}
+	for _, t := range v.shapes.Objects(shape, IRI(RDFType)) {
+		if t == IRI(rdfsClass) || t == IRI(owlClass) {
+			add(v.instances(shape)...)
+		}
+	}
+	for _, p := range v.shapes.Objects(shape, sh("targetSubjectsOf")) {
+		add(v.data.SubjectsWithPredicate(p)...)
+	}
+	for _, p := range v.shapes.Objects(shape, sh("targetObjectsOf")) {
+		for _, t := range v.data.Triples() {
+			if t.Predicate == p {
+				add(t.Object)
+			}
+		}
+	}
+	return focus
+}
+
+// subClasses returns class and all its transitive subclasses.
+func (v *shaclValidator) subClasses(class Term) []Term {
+	classes := []Term{class}
+	seen := map[Term]struct{}{class: {}}
+	for i := 0; i < len(classes); i++ {
+		for _, g := range []*Graph{v.data, v.shapes} {
+			for _, sub := range g.Subjects(IRI(rdfsSubClassOf), classes[i]) {
+				if _, ok := seen[sub]; !ok {
+					seen[sub] = struct{}{}
+					classes = append(classes, sub)
+				}
+			}
+		}
+	}
+	return classes
+}
+
+func (v *shaclValidator) instances(class Term) []Term {
+	var nodes []Term
+	for _, c := range v.subClasses(class) {
+		nodes = append(nodes, v.data.Subjects(IRI(RDFType), c)...)
+	}
+	return nodes
+}
+
+func (v *shaclValidator) isInstance(node, class Term) bool {
+	types := v.data.Objects(node, IRI(RDFType))
+	for _, c := range v.subClasses(class) {
+		if slices.Contains(types, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *shaclValidator) pathValues(focus, path Term) []Term {
+	var values []Term
+	seen := map[Term]struct{}{}
+	for _, value := range v.evalPath([]Term{focus}, path) {
+		if _, ok := seen[value]; !ok {
+			seen[value] = struct{}{}
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func (v *shaclValidator) evalPath(nodes []Term, path Term) []Term {
+	var out []Term
+	step := func(nodes []Term, p Term) []Term {
+		var next []Term
+		for _, n := range nodes {
+			next = append(next, v.evalPath([]Term{n}, p)...)
+		}
+		return next
+	}
+
+	if path.Kind == TermIRI && path != IRI(RDFNil) {
+		for _, n := range nodes {
+			out = append(out, v.data.Objects(n, path)...)
+		}
+		return out
+	}
+
+	if inverse, ok := v.shapes.Object(path, sh("inversePath")); ok {
+		for _, n := range nodes {
+			out = append(out, v.data.Subjects(inverse, n)...)
+		}
+		return out
+	}
+	if alternatives, ok := v.shapes.Object(path, sh("alternativePath")); ok {
+		for _, alt := range v.shapes.List(alternatives) {
+			out = append(out, step(nodes, alt)...)
+		}
+		return out
+	}
+	if p, ok := v.shapes.Object(path, sh("zeroOrOnePath")); ok {
+		return append(slices.Clone(nodes), step(nodes, p)...)
+	}
+	if p, ok := v.shapes.Object(path, sh("zeroOrMorePath")); ok {
+		return v.closure(nodes, p, true)
+	}
+	if p, ok := v.shapes.Object(path, sh("oneOrMorePath")); ok {
+		return v.closure(nodes, p, false)
+	}
+	if _, ok := v.shapes.Object(path, IRI(RDFFirst)); ok {
+		current := nodes
+		for _, p := range v.shapes.List(path) {
+			current = step(current, p)
+		}
+		return current
+	}
+	return nil
+}
+
+func (v *shaclValidator) closure(nodes []Term, path Term, includeStart bool) []Term {
+	var out []Term
+	seen := map[Term]struct{}{}
+	if includeStart {
+		for _, n := range nodes {
+			seen[n] = struct{}{}
+			out = append(out, n)
+		}
+	}
+	frontier := nodes
+	for len(frontier) > 0 {
+		var next []Term
+		for _, n := range v.evalPath(frontier, path) {
+			if _, ok := seen[n]; !ok {
+				seen[n] = struct{}{}
+				out = append(out, n)
+				next = append(next, n)
+			}
+		}
+		frontier = next
+	}
+	return out
+}
+
+func (v *shaclValidator) pathString(path Term) string {
+	if path.Kind == TermIRI {
+		return path.Value
+	}
+	if inverse, ok := v.shapes.Object(path, sh("inversePath")); ok {
+		return "^" + v.pathString(inverse)
+	}
+	if alternatives, ok := v.shapes.Object(path, sh("alternativePath")); ok {
+		var parts []string
+		for _, alt := range v.shapes.List(alternatives) {
+			parts = append(parts, v.pathString(alt))
+		}
+		return "(" + strings.Join(parts, "|") + ")"
+	}
+	for name, suffix := range map[string]string{"zeroOrOnePath": "?", "zeroOrMorePath": "*", "oneOrMorePath": "+"} {
+		if p, ok := v.shapes.Object(path, sh(name)); ok {
+			return "(" + v.pathString(p) + ")" + suffix
+		}
+	}
+	var parts []string
+	for _, p := range v.shapes.List(path) {
+		parts = append(parts, v.pathString(p))
+	}
+	return strings.Join(parts, "/")
+}
+
+func (v *shaclValidator) conforms(node, shape Term) bool {
+	if v.depth >= maxShapeDepth {
+		return true
+	}
+	v.depth++
+	defer func() { v.depth-- }()
+	return len(v.validateShape(shape, node)) == 0
+}
+
+func (v *shaclValidator) validateShape(shape, focus Term) []ValidationResult {
+	if deactivated, ok := v.shapes.Object(shape, sh("deactivated")); ok && deactivated.Value == "true" {
+		return nil
+	}
+
+	var results []ValidationResult
+	path, isProperty := v.shapes.Object(shape, sh("path"))
+	values := []Term{focus}
+	if isProperty {
+		values = v.pathValues(focus, path)
+	}
+
+	add := func(component string, value *Term, message string) {
+		result := ValidationResult{
+			FocusNode:  nodeString(focus),
+			Severity:   "Violation",
+			Constraint: shaclNS + component + "ConstraintComponent",
+			Shape:      nodeString(shape),
+			Message:    message,
+		}
+		if isProperty {
+			result.Path = v.pathString(path)
+		}
+		if value != nil {
+			result.Value = nodeString(*value)
+		}
+		if severity, ok := v.shapes.Object(shape, sh("severity")); ok {
+			result.Severity = strings.TrimPrefix(severity.Value, shaclNS)
+		}
+		if msg, ok := v.shapes.Object(shape, sh("message")); ok && msg.Kind == TermLiteral {
+			result.Message = msg.Value
+		}
+		results = append(results, result)
+	}
+
+	if isProperty {
+		for _, c := range v.shapes.Objects(shape, sh("minCount")) {
+			if n, err := strconv.Atoi(c.Value); err == nil && len(values) < n {
+				add("MinCount", nil, fmt.Sprintf("Less than %d value(s), found %d", n, len(values)))
+			}
+		}
+		for _, c := range v.shapes.Objects(shape, sh("maxCount")) {
+			if n, err := strconv.Atoi(c.Value); err == nil && len(values) > n {
+				add("MaxCount", nil, fmt.Sprintf("More than %d value(s), found %d", n, len(values)))
+			}
+		}
+	}
+
+	for _, hasValue := range v.shapes.Objects(shape, sh("hasValue")) {
+		if !slices.Contains(values, hasValue) {
+			add("HasValue", nil, fmt.Sprintf("Missing expected value %s", nodeString(hasValue)))
+		}
+	}
+
+	if unique, ok := v.shapes.Object(shape, sh("uniqueLang")); ok && unique.Value == "true" {
+		langs := map[string]int{}
+		for _, value := range values {
+			if value.Kind == TermLiteral && value.Lang != "" {
+				langs[strings.ToLower(value.Lang)]++
+			}
+		}
+		for lang, n := range langs {
+			if n > 1 {
+				add("UniqueLang", nil, fmt.Sprintf("Language '%s' is used by more than one value", lang))
+			}
+		}
+	}
+
+	for _, value := range values {
+		v.validateValue(shape, value, add)
+	}
+
+	if closed, ok := v.shapes.Object(shape, sh("closed")); ok && closed.Value == "true" {
+		allowed := map[Term]struct{}{}
+		for _, ps := range v.shapes.Objects(shape, sh("property")) {
+			if p, ok := v.shapes.Object(ps, sh("path")); ok && p.Kind == TermIRI {
+				allowed[p] = struct{}{}
+			}
+		}
+		if ignored, ok := v.shapes.Object(shape, sh("ignoredProperties")); ok {
+			for _, p := range v.shapes.List(ignored) {
+				allowed[p] = struct{}{}
+			}
+		}
+		for _, value := range values {
+			predicates := v.data.Predicates(value)
+			sort.Slice(predicates, func(i, j int) bool { return predicates[i].Value < predicates[j].Value })
+			for _, p := range predicates {
+				if _, ok := allowed[p]; ok {
+					continue
+				}
+				for _, object := range v.data.Objects(value, p) {
+					add("Closed", &object, fmt.Sprintf("Property %s is not allowed by the closed shape", p.Value))
+				}
+			}
+		}
+	}
+
+	for _, ps := range v.shapes.Objects(shape, sh("property")) {
+		for _, value := range values {
+			results = append(results, v.validateShape(ps, value)...)
+		}
+	}
+
+	return results
+}
+
+// validateValue checks the value based constraint components of shape against a single value node.
+func (v *shaclValidator) validateValue(shape, value Term, add func(component string, value *Term, message string)) {
+	for _, datatype := range v.shapes.Objects(shape, sh("datatype")) {
+		if value.Kind != TermLiteral || value.Datatype != datatype.Value {
+			add("Datatype", &value, fmt.Sprintf("Value does not have datatype %s", datatype.Value))
+		}
+	}
+
+	for _, class := range v.shapes.Objects(shape, sh("class")) {
+		if value.Kind == TermLiteral || !v.isInstance(value, class) {
+			add("Class", &value, fmt.Sprintf("Value is not an instance of %s", class.Value))
+		}
+	}
+
+	for _, kind := range v.shapes.Objects(shape, sh("nodeKind")) {
+		if !matchesNodeKind(value, strings.TrimPrefix(kind.Value, shaclNS)) {
+			add("NodeKind", &value, fmt.Sprintf("Value does not have node kind %s", kind.Value))
+		}
+	}
+
+	for _, pattern := range v.shapes.Objects(shape, sh("pattern")) {
+		expr := pattern.Value
+		if flags, ok := v.shapes.Object(shape, sh("flags")); ok {
+			if f := strings.Map(func(r rune) rune {
+				if strings.ContainsRune("ims", r) {
+					return r
+				}
+				return -1
+			}, flags.Value); f != "" {
+				expr = "(?" + f + ")" + expr
+			}
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			add("Pattern", &value, fmt.Sprintf("Shape pattern '%s' is not supported: %v", pattern.Value, err))
+			continue
+		}
+		if value.Kind == TermBlank || !re.MatchString(value.Value) {
+			add("Pattern", &value, fmt.Sprintf("Value does not match pattern '%s'", pattern.Value))
+		}
+	}
+
+	for _, c := range v.shapes.Objects(shape, sh("minLength")) {
+		if n, err := strconv.Atoi(c.Value); err == nil && (value.Kind == TermBlank || len([]rune(value.Value)) < n) {
+			add("MinLength", &value, fmt.Sprintf("Value is shorter than %d character(s)", n))
+		}
+	}
+	for _, c := range v.shapes.Objects(shape, sh("maxLength")) {
+		if n, err := strconv.Atoi(c.Value); err == nil && (value.Kind == TermBlank || len([]rune(value.Value)) > n) {
+			add("MaxLength", &value, fmt.Sprintf("Value is longer than %d character(s)", n))
+		}
+	}
+
+	for _, r := range valueRanges {
+		for _, bound := range v.shapes.Objects(shape, sh(r.name)) {
+			c, ok := compareLiterals(value, bound)
+			if !ok || !r.check(c) {
+				add(r.component, &value, fmt.Sprintf("Value does not satisfy %s %s", r.name, bound.Value))
+			}
+		}
+	}
+
+	for _, in := range v.shapes.Objects(shape, sh("in")) {
+		if !slices.Contains(v.shapes.List(in), value) {
+			add("In", &value, "Value is not one of the allowed values")
+		}
+	}
+
+	for _, languages := range v.shapes.Objects(shape, sh("languageIn")) {
+		if !matchesLanguage(value, v.shapes.List(languages)) {
+			add("LanguageIn", &value, "Value does not have an allowed language tag")
+		}
+	}
+
+	for _, node := range v.shapes.Objects(shape, sh("node")) {
+		if !v.conforms(value, node) {
+			add("Node", &value, fmt.Sprintf("Value does not conform to shape %s", nodeString(node)))
+		}
+	}
+	for _, not := range v.shapes.Objects(shape, sh("not")) {
+		if v.conforms(value, not) {
+			add("Not", &value, fmt.Sprintf("Value conforms to shape %s", nodeString(not)))
+		}
+	}
+	for _, list := range v.shapes.Objects(shape, sh("and")) {
+		for _, member := range v.shapes.List(list) {
+			if !v.conforms(value, member) {
+				add("And", &value, "Value does not conform to all shapes of sh:and")
+				break
+			}
+		}
+	}
+	for _, list := range v.shapes.Objects(shape, sh("or")) {
+		matched := false
+		for _, member := range v.shapes.List(list) {
+			if v.conforms(value, member) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			add("Or", &value, "Value does not conform to any shape of sh:or")
+		}
+	}
+	for _, list := range v.shapes.Objects(shape, sh("xone")) {
+		matched := 0
+		for _, member := range v.shapes.List(list) {
+			if v.conforms(value, member) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			add("Xone", &value, fmt.Sprintf("Value conforms to %d shapes of sh:xone instead of exactly one", matched))
+		}
+	}
+}
+
+// valueRanges are the value range constraint components, checked against the comparison of a value with the bound.
+var valueRanges = []struct {
+	name      string
+	component string
+	check     func(int) bool
+}{
+	{"minInclusive", "MinInclusive", func(c int) bool { return c >= 0 }},
+	{"maxInclusive", "MaxInclusive", func(c int) bool { return c <= 0 }},
+	{"minExclusive", "MinExclusive", func(c int) bool { return c > 0 }},
+	{"maxExclusive", "MaxExclusive", func(c int) bool { return c < 0 }},
+}
+
+func matchesNodeKind(value Term, kind string) bool {
+	switch kind {
+	case "IRI":
+		return value.Kind == TermIRI
+	case "BlankNode":
+		return value.Kind == TermBlank
+	case "Literal":
+		return value.Kind == TermLiteral
+	case "BlankNodeOrIRI":
+		return value.Kind != TermLiteral
+	case "BlankNodeOrLiteral":
+		return value.Kind != TermIRI
+	case "IRIOrLiteral":
+		return value.Kind != TermBlank
+	}
+	return false
+}
+
+func matchesLanguage(value Term, languages []Term) bool {
+	if value.Kind != TermLiteral || value.Lang == "" {
+		return false
+	}
+	lang := strings.ToLower(value.Lang)
+	for _, l := range languages {
+		allowed := strings.ToLower(l.Value)
+		if lang == allowed || strings.HasPrefix(lang, allowed+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// compareLiterals compares two literals numerically when both are numbers and lexically otherwise.
+func compareLiterals(a, b Term) (int, bool) {
+	if a.Kind != TermLiteral || b.Kind != TermLiteral {
+		return 0, false
+	}
+	x, errA := strconv.ParseFloat(a.Value, 64)
+	y, errB := strconv.ParseFloat(b.Value, 64)
+	if errA == nil && errB == nil {
+		if math.IsNaN(x) || math.IsNaN(y) {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	if a.Datatype != b.Datatype {
+		return 0, false
+	}
+	return strings.Compare(a.Value, b.Value), true
+}
+
+// nodeString returns the representation of a node used in reports: the IRI, _:label or the literal in N-Triples form.
+func nodeString(t Term) string {
+	if t.Kind == TermIRI {
+		return t.Value
+	}
+	return t.String()
+}
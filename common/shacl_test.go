@@ -0,0 +1,143 @@
+package common
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	t.Parallel()
+
+	shapes := `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+@prefix ex: <http://example.org/> .
+
+ex:ThingShape a sh:NodeShape ;
+    sh:targetClass ex:Thing ;
+    sh:property [ sh:path ex:id ; sh:minCount 1 ; sh:maxCount 1 ; sh:datatype xsd:string ] ;
+    sh:property [ sh:path ex:code ; sh:pattern "^[A-Z]{3}$" ] ;
+    sh:property [ sh:path ex:level ; sh:in ( "low" "high" ) ] ;
+    sh:property [ sh:path ex:owner ; sh:class ex:Agent ; sh:severity sh:Warning ] ;
+    sh:property [ sh:path ex:size ; sh:minInclusive 0 ] ;
+    sh:property [ sh:path ( ex:owner ex:name ) ; sh:minCount 1 ; sh:message "Owner needs a name" ] .
+`
+
+	tests := []struct {
+		name           string
+		data           string
+		wantConforms   bool
+		wantViolations int
+		wantWarnings   int
+		wantMessages   []string
+	}{
+		{
+			name: "conforming data",
+			data: `@prefix ex: <http://example.org/> .
+ex:a a ex:Thing ; ex:id "a" ; ex:code "ABC" ; ex:level "low" ; ex:owner ex:o ; ex:size 3 .
+ex:o a ex:SpecialAgent ; ex:name "Owner" .
+ex:SpecialAgent <http://www.w3.org/2000/01/rdf-schema#subClassOf> ex:Agent .
+`,
+			wantConforms: true,
+		},
+		{
+			name: "missing and invalid values",
+			data: `@prefix ex: <http://example.org/> .
+ex:a a ex:Thing ; ex:code "abcd" ; ex:level "medium" ; ex:owner ex:o ; ex:size -1 .
+ex:b a ex:Thing ; ex:id "b1", "b2" ; ex:owner [ ex:name "N" ] .
+`,
+			wantConforms:   false,
+			wantViolations: 6,
+			wantWarnings:   2,
+			wantMessages: []string{
+				"Less than 1 value(s), found 0",
+				"More than 1 value(s), found 2",
+				"Value does not match pattern '^[A-Z]{3}$'",
+				"Value is not one of the allowed values",
+				"Value does not satisfy minInclusive 0",
+				"Value is not an instance of http://example.org/Agent",
+				"Owner needs a name",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			shapesPath := filepath.Join(tmpDir, "shapes.ttl")
+			dataPath := filepath.Join(tmpDir, "data", "data.ttl")
+			if err := os.MkdirAll(filepath.Dir(dataPath), 0o750); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(shapesPath, []byte(shapes), 0o600); err != nil {
+				t.Fatalf("Failed to write shapes: %v", err)
+			}
+			if err := os.WriteFile(dataPath, []byte(tc.data), 0o600); err != nil {
+				t.Fatalf("Failed to write data: %v", err)
+			}
+
+			report, err := ValidateMetadata(ValidateMetadataOpts{
+				Paths:  []string{filepath.Dir(dataPath)},
+				Shapes: []string{shapesPath},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if report.Conforms != tc.wantConforms {
+				t.Errorf("Expected conforms %v, got %v", tc.wantConforms, report.Conforms)
+			}
+			if report.Violations != tc.wantViolations || report.Warnings != tc.wantWarnings {
+				t.Errorf("Expected %d violations and %d warnings, got %d and %d", tc.wantViolations, tc.wantWarnings, report.Violations, report.Warnings)
+			}
+
+			var text bytes.Buffer
+			if err := report.WriteText(&text); err != nil {
+				t.Fatalf("unexpected error writing report: %v", err)
+			}
+			for _, msg := range tc.wantMessages {
+				if !strings.Contains(text.String(), msg) {
+					t.Errorf("Expected report to contain %q, got:\n%s", msg, text.String())
+				}
+			}
+			for _, focus := range report.FocusNodes {
+				if focus.File != dataPath {
+					t.Errorf("Expected focus node %s to be attributed to %s, got %s", focus.FocusNode, dataPath, focus.File)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateMetadataCoreShapes(t *testing.T) {
+	t.Parallel()
+
+	dataPath := filepath.Join(t.TempDir(), "dataset.ttl")
+	data := `@prefix dcat: <http://www.w3.org/ns/dcat#> .
+@prefix dct: <http://purl.org/dc/terms/> .
+<https://example.org/dataset/1> a dcat:Dataset ; dct:title "Dataset" ; dct:description "Description" .
+`
+	if err := os.WriteFile(dataPath, []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write data: %v", err)
+	}
+
+	report, err := ValidateMetadata(ValidateMetadataOpts{Paths: []string{dataPath}, Shapes: []string{CoreShapes}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Conforms || report.Violations != 1 {
+		t.Fatalf("Expected a single violation for the missing identifier, got %+v", report)
+	}
+	if got := report.FocusNodes[0].Results[0].Path; got != "http://purl.org/dc/terms/identifier" {
+		t.Errorf("Expected violation on dct:identifier, got %s", got)
+	}
+
+	if _, err := ValidateMetadata(ValidateMetadataOpts{Paths: []string{dataPath}, Shapes: []string{"does-not-exist"}}); err == nil {
+		t.Error("Expected an error for unknown shapes, but got nil")
+	}
+}
@@ -0,0 +1,826 @@
+package common
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SyntaxError describes a syntax error found while parsing an RDF document.
+type SyntaxError struct {
+	// File the error was found in, empty when parsing from a reader
+	File string
+	// 1-based line of the error
+	Line int
+	// 1-based column (in characters) of the error
+	Column int
+	// Description of the error
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ParseTurtle parses a Turtle document and returns its triples. Relative IRIs are resolved against base
+// when it is set. Syntax errors are returned as *SyntaxError.
+func ParseTurtle(r io.Reader, base string) ([]Triple, error) {
+	var triples []Triple
+	_, err := parseTurtle(r, base, func(t Triple) {
+		triples = append(triples, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return triples, nil
+}
+
+// LoadTurtle parses a Turtle document and adds its triples to the graph. Blank nodes are kept distinct from
+// the ones of previously loaded documents. Syntax errors are returned as *SyntaxError.
+func (g *Graph) LoadTurtle(r io.Reader, base string) error {
+	_, err := parseTurtle(r, base, g.documentAdder())
+	return err
+}
+
+// documentAdder returns a function adding the triples of a new document to the graph, renaming its blank
+// nodes so that they do not clash with the ones of previously loaded documents.
+func (g *Graph) documentAdder() func(Triple) {
+	g.docs++
+	doc := g.docs
+	rename := func(t Term) Term {
+		if t.Kind == TermBlank {
+			return Blank(fmt.Sprintf("d%d%s", doc, t.Value))
+		}
+		return t
+	}
+	return func(t Triple) {
+		g.Add(Triple{Subject: rename(t.Subject), Predicate: t.Predicate, Object: rename(t.Object)})
+	}
+}
+
+// LoadTurtleFile parses the Turtle file at path and adds its triples to the graph.
+// Syntax errors are returned as *SyntaxError with File set to path.
+func (g *Graph) LoadTurtleFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	if err := g.LoadTurtle(f, ""); err != nil {
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) {
+			syntaxErr.File = path
+			return syntaxErr
+		}
+		return fmt.Errorf("failed to read file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// ValidateTurtle checks that the document read from r is syntactically valid Turtle.
+// Syntax errors are returned as *SyntaxError.
+func ValidateTurtle(r io.Reader) error {
+	_, err := parseTurtle(r, "", func(Triple) {})
+	return err
+}
+
+// ValidateTurtleFile checks that the file at path is syntactically valid Turtle.
+// Syntax errors are returned as *SyntaxError with File set to path.
+func ValidateTurtleFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	if err := ValidateTurtle(f); err != nil {
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) {
+			syntaxErr.File = path
+			return syntaxErr
+		}
+		return fmt.Errorf("failed to read file '%s': %w", path, err)
+	}
+	return nil
+}
+
+const eof = -1
+
+type turtleParser struct {
+	r         *bufio.Reader
+	lookahead []rune
+	readErr   error
+
+	// position of the next rune to be consumed
+	line int
+	col  int
+
+	base       *url.URL
+	prefixes   map[string]string
+	blanks     map[string]string
+	blankCount int
+	emit       func(Triple)
+}
+
+// turtleFailure carries a syntax error through panics inside the parser.
+type turtleFailure struct {
+	err *SyntaxError
+}
+
+// parseTurtle parses a Turtle document, passing every triple to emit. It returns the prefixes declared in
+// the document.
+func parseTurtle(r io.Reader, base string, emit func(Triple)) (prefixes map[string]string, err error) {
+	p := &turtleParser{
+		r:        bufio.NewReader(r),
+		line:     1,
+		col:      1,
+		prefixes: map[string]string{},
+		blanks:   map[string]string{},
+		emit:     emit,
+	}
+	if base != "" {
+		u, err := url.Parse(base)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base IRI '%s': %w", base, err)
+		}
+		p.base = u
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			failure, ok := rec.(turtleFailure)
+			if !ok {
+				panic(rec)
+			}
+			err = failure.err
+		}
+		if err == nil && p.readErr != nil && !errors.Is(p.readErr, io.EOF) {
+			err = p.readErr
+		}
+		if err != nil {
+			prefixes = nil
+		}
+	}()
+
+	for {
+		p.skipWS()
+		if p.peek() == eof {
+			return p.prefixes, nil
+		}
+		p.parseStatement()
+	}
+}
+
+func (p *turtleParser) peekAt(i int) rune {
+	for len(p.lookahead) <= i {
+		if p.readErr != nil {
+			return eof
+		}
+		r, _, err := p.r.ReadRune()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				p.readErr = err
+			} else {
+				p.readErr = io.EOF
+			}
+			return eof
+		}
+		p.lookahead = append(p.lookahead, r)
+	}
+	return p.lookahead[i]
+}
+
+func (p *turtleParser) peek() rune {
+	return p.peekAt(0)
+}
+
+func (p *turtleParser) next() rune {
+	r := p.peek()
+	if r == eof {
+		return eof
+	}
+	p.lookahead = p.lookahead[1:]
+	if r == '\n' {
+		p.line++
+		p.col = 1
+	} else {
+		p.col++
+	}
+	return r
+}
+
+func (p *turtleParser) fail(format string, a ...any) {
+	p.failAt(p.line, p.col, format, a...)
+}
+
+func (p *turtleParser) failAt(line, col int, format string, a ...any) {
+	if p.readErr != nil && !errors.Is(p.readErr, io.EOF) {
+		panic(turtleFailure{err: &SyntaxError{Line: line, Column: col, Msg: p.readErr.Error()}})
+	}
+	panic(turtleFailure{err: &SyntaxError{Line: line, Column: col, Msg: fmt.Sprintf(format, a...)}})
+}
+
+func (p *turtleParser) expect(want rune, context string) {
+	if r := p.peek(); r != want {
+		p.fail("expected '%c' %s, found %s", want, context, describeRune(r))
+	}
+	p.next()
+}
+
+func describeRune(r rune) string {
+	if r == eof {
+		return "end of file"
+	}
+	return strconv.QuoteRune(r)
+}
+
+func (p *turtleParser) skipWS() {
+	for {
+		switch r := p.peek(); {
+		case r == '#':
+			for r != '\n' && r != eof {
+				p.next()
+				r = p.peek()
+			}
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			p.next()
+		default:
+			return
+		}
+	}
+}
+
+// peekKeyword reports whether the input continues with the case-insensitive keyword followed by whitespace.
+func (p *turtleParser) peekKeyword(keyword string) bool {
+	for i, want := range keyword {
+		if unicode.ToLower(p.peekAt(i)) != want {
+			return false
+		}
+	}
+	switch p.peekAt(len(keyword)) {
+	case ' ', '\t', '\n', '\r', '<':
+		return true
+	}
+	return false
+}
+
+func (p *turtleParser) parseStatement() {
+	switch {
+	case p.peek() == '@':
+		line, col := p.line, p.col
+		p.next()
+		var keyword strings.Builder
+		for isLetter(p.peek()) {
+			keyword.WriteRune(p.next())
+		}
+		switch keyword.String() {
+		case "prefix":
+			p.parsePrefix()
+		case "base":
+			p.parseBase()
+		default:
+			p.failAt(line, col, "unknown directive '@%s'", keyword.String())
+		}
+		p.skipWS()
+		p.expect('.', "at end of directive")
+	case p.peekKeyword("prefix"):
+		for range len("prefix") {
+			p.next()
+		}
+		p.parsePrefix()
+	case p.peekKeyword("base"):
+		for range len("base") {
+			p.next()
+		}
+		p.parseBase()
+	default:
+		p.parseTriples()
+		p.skipWS()
+		p.expect('.', "at end of statement")
+	}
+}
+
+func (p *turtleParser) parsePrefix() {
+	p.skipWS()
+	line, col := p.line, p.col
+	var prefix strings.Builder
+	for p.peek() != ':' {
+		r := p.peek()
+		if !isNameChar(r) && r != '.' {
+			p.fail("expected prefix name followed by ':', found %s", describeRune(r))
+		}
+		prefix.WriteRune(p.next())
+	}
+	p.next()
+	name := prefix.String()
+	if name != "" && (!isLetter([]rune(name)[0]) || strings.HasSuffix(name, ".")) {
+		p.failAt(line, col, "invalid prefix name '%s'", name)
+	}
+	p.skipWS()
+	p.prefixes[name] = p.parseIRIRef()
+}
+
+func (p *turtleParser) parseBase() {
+	p.skipWS()
+	iri := p.parseIRIRef()
+	u, err := url.Parse(iri)
+	if err != nil {
+		p.fail("invalid base IRI '%s': %v", iri, err)
+	}
+	p.base = u
+}
+
+func (p *turtleParser) parseTriples() {
+	var subject Term
+	switch r := p.peek(); {
+	case r == '[':
+		node, hasProperties := p.parseBlankNodePropertyList()
+		p.skipWS()
+		if hasProperties && p.peek() == '.' {
+			return
+		}
+		subject = node
+	case r == '(':
+		subject = p.parseCollection()
+	case r == '<':
+		subject = IRI(p.parseIRIRef())
+	case r == '_' && p.peekAt(1) == ':':
+		subject = p.parseBlankNodeLabel()
+	case r == '"' || r == '\'' || r == '+' || r == '-' || isDigit(r):
+		p.fail("literals cannot be used as subject")
+	default:
+		line, col := p.line, p.col
+		name := p.readName()
+		if !strings.Contains(name, ":") {
+			p.failAt(line, col, "expected subject, found %s", describeName(name, r))
+		}
+		subject = IRI(p.expandName(name, line, col))
+	}
+	p.parsePredicateObjectList(subject)
+}
+
+func describeName(name string, r rune) string {
+	if name == "" {
+		return describeRune(r)
+	}
+	return "'" + name + "'"
+}
+
+func (p *turtleParser) parsePredicateObjectList(subject Term) {
+	for {
+		p.skipWS()
+		predicate := p.parseVerb()
+		p.parseObjectList(subject, predicate)
+		p.skipWS()
+		if p.peek() != ';' {
+			return
+		}
+		for p.peek() == ';' {
+			p.next()
+			p.skipWS()
+		}
+		switch p.peek() {
+		case '.', ']', eof:
+			return
+		}
+	}
+}
+
+func (p *turtleParser) parseVerb() Term {
+	r := p.peek()
+	if r == 'a' {
+		after := p.peekAt(1)
+		if !isNameChar(after) && after != ':' && after != '.' {
+			p.next()
+			return IRI(RDFType)
+		}
+	}
+	if r == '<' {
+		return IRI(p.parseIRIRef())
+	}
+
+	line, col := p.line, p.col
+	name := p.readName()
+	if !strings.Contains(name, ":") {
+		p.failAt(line, col, "expected predicate, found %s", describeName(name, r))
+	}
+	return IRI(p.expandName(name, line, col))
+}
+
+func (p *turtleParser) parseObjectList(subject, predicate Term) {
+	for {
+		p.skipWS()
+		object := p.parseObject()
+		p.emit(Triple{Subject: subject, Predicate: predicate, Object: object})
+		p.skipWS()
+		if p.peek() != ',' {
+			return
+		}
+		p.next()
+	}
+}
+
+func (p *turtleParser) parseObject() Term {
+	switch r := p.peek(); {
+	case r == '<':
+		return IRI(p.parseIRIRef())
+	case r == '_' && p.peekAt(1) == ':':
+		return p.parseBlankNodeLabel()
+	case r == '[':
+		node, _ := p.parseBlankNodePropertyList()
+		return node
+	case r == '(':
+		return p.parseCollection()
+	case r == '"' || r == '\'':
+		return p.parseRDFLiteral()
+	case r == '+' || r == '-' || isDigit(r) || (r == '.' && isDigit(p.peekAt(1))):
+		return p.parseNumber()
+	default:
+		line, col := p.line, p.col
+		name := p.readName()
+		switch name {
+		case "true", "false":
+			return Literal(name, XSDBoolean, "")
+		}
+		if !strings.Contains(name, ":") {
+			p.failAt(line, col, "expected object, found %s", describeName(name, r))
+		}
+		return IRI(p.expandName(name, line, col))
+	}
+}
+
+func (p *turtleParser) newBlank() Term {
+	p.blankCount++
+	return Blank(fmt.Sprintf("b%d", p.blankCount))
+}
+
+func (p *turtleParser) parseBlankNodeLabel() Term {
+	line, col := p.line, p.col
+	p.next()
+	p.next()
+
+	var label strings.Builder
+	r := p.peek()
+	if !isNameChar(r) || r == '-' {
+		p.failAt(line, col, "invalid blank node label")
+	}
+	for {
+		r := p.peek()
+		if isNameChar(r) {
+			label.WriteRune(p.next())
+			continue
+		}
+		if r == '.' && isNameChar(p.peekAt(1)) {
+			label.WriteRune(p.next())
+			continue
+		}
+		break
+	}
+
+	id, ok := p.blanks[label.String()]
+	if !ok {
+		id = p.newBlank().Value
+		p.blanks[label.String()] = id
+	}
+	return Blank(id)
+}
+
+// parseBlankNodePropertyList parses either an anonymous blank node '[]' or a blank node property list.
+// It reports whether the node had properties.
+func (p *turtleParser) parseBlankNodePropertyList() (Term, bool) {
+	p.next()
+	p.skipWS()
+	node := p.newBlank()
+	if p.peek() == ']' {
+		p.next()
+		return node, false
+	}
+	p.parsePredicateObjectList(node)
+	p.skipWS()
+	p.expect(']', "at end of blank node property list")
+	return node, true
+}
+
+func (p *turtleParser) parseCollection() Term {
+	line, col := p.line, p.col
+	p.next()
+
+	var items []Term
+	for {
+		p.skipWS()
+		r := p.peek()
+		if r == ')' {
+			p.next()
+			break
+		}
+		if r == eof {
+			p.failAt(line, col, "unterminated collection")
+		}
+		items = append(items, p.parseObject())
+	}
+
+	if len(items) == 0 {
+		return IRI(RDFNil)
+	}
+
+	head := p.newBlank()
+	current := head
+	for i, item := range items {
+		p.emit(Triple{Subject: current, Predicate: IRI(RDFFirst), Object: item})
+		rest := IRI(RDFNil)
+		if i < len(items)-1 {
+			rest = p.newBlank()
+		}
+		p.emit(Triple{Subject: current, Predicate: IRI(RDFRest), Object: rest})
+		current = rest
+	}
+	return head
+}
+
+func (p *turtleParser) parseIRIRef() string {
+	line, col := p.line, p.col
+	if p.peek() != '<' {
+		p.fail("expected IRI, found %s", describeRune(p.peek()))
+	}
+	p.next()
+
+	var iri strings.Builder
+	for {
+		r := p.peek()
+		switch {
+		case r == eof:
+			p.failAt(line, col, "unterminated IRI")
+		case r <= 0x20 || strings.ContainsRune(`<"{}|^`+"`", r):
+			p.fail("invalid character %s in IRI", describeRune(r))
+		}
+		p.next()
+		switch r {
+		case '>':
+			return p.resolve(iri.String())
+		case '\\':
+			esc := p.next()
+			if esc != 'u' && esc != 'U' {
+				p.fail("invalid escape sequence '\\%c' in IRI", esc)
+			}
+			iri.WriteRune(p.readUnicodeEscape(esc))
+		default:
+			iri.WriteRune(r)
+		}
+	}
+}
+
+func (p *turtleParser) resolve(iri string) string {
+	if p.base == nil {
+		return iri
+	}
+	u, err := url.Parse(iri)
+	if err != nil || u.IsAbs() {
+		return iri
+	}
+	return p.base.ResolveReference(u).String()
+}
+
+// readName reads a prefixed name or a bare keyword. Escapes in the local part are decoded.
+func (p *turtleParser) readName() string {
+	var name strings.Builder
+	for {
+		r := p.peek()
+		switch {
+		case isNameChar(r) || r == ':':
+			name.WriteRune(p.next())
+		case r == '.':
+			after := p.peekAt(1)
+			if !isNameChar(after) && after != ':' && after != '%' && after != '\\' {
+				return name.String()
+			}
+			name.WriteRune(p.next())
+		case r == '%':
+			p.next()
+			name.WriteRune('%')
+			for range 2 {
+				if !isHex(p.peek()) {
+					p.fail("invalid percent encoding in prefixed name")
+				}
+				name.WriteRune(p.next())
+			}
+		case r == '\\':
+			p.next()
+			esc := p.next()
+			if !strings.ContainsRune("_~.-!$&'()*+,;=/?#@%", esc) {
+				p.fail("invalid escape sequence '\\%c' in prefixed name", esc)
+			}
+			name.WriteRune(esc)
+		default:
+			return name.String()
+		}
+	}
+}
+
+func (p *turtleParser) expandName(name string, line, col int) string {
+	prefix, local, _ := strings.Cut(name, ":")
+	if prefix != "" && !isLetter([]rune(prefix)[0]) {
+		p.failAt(line, col, "invalid prefixed name '%s'", name)
+	}
+	if strings.HasPrefix(local, "-") {
+		p.failAt(line, col, "invalid local name in '%s'", name)
+	}
+	namespace, ok := p.prefixes[prefix]
+	if !ok {
+		p.failAt(line, col, "undefined prefix '%s:'", prefix)
+	}
+	return namespace + local
+}
+
+func (p *turtleParser) parseRDFLiteral() Term {
+	value := p.parseString()
+	switch {
+	case p.peek() == '@':
+		line, col := p.line, p.col
+		p.next()
+		var lang strings.Builder
+		for isLetter(p.peek()) && p.peek() < unicode.MaxASCII {
+			lang.WriteRune(p.next())
+		}
+		if lang.Len() == 0 {
+			p.failAt(line, col, "invalid language tag")
+		}
+		for p.peek() == '-' {
+			lang.WriteRune(p.next())
+			n := 0
+			for r := p.peek(); r < unicode.MaxASCII && (isLetter(r) || isDigit(r)); r = p.peek() {
+				lang.WriteRune(p.next())
+				n++
+			}
+			if n == 0 {
+				p.failAt(line, col, "invalid language tag")
+			}
+		}
+		return Literal(value, "", lang.String())
+	case p.peek() == '^' && p.peekAt(1) == '^':
+		p.next()
+		p.next()
+		if p.peek() == '<' {
+			return Literal(value, p.parseIRIRef(), "")
+		}
+		line, col := p.line, p.col
+		name := p.readName()
+		if !strings.Contains(name, ":") {
+			p.failAt(line, col, "expected datatype IRI, found %s", describeName(name, p.peek()))
+		}
+		return Literal(value, p.expandName(name, line, col), "")
+	}
+	return Literal(value, XSDString, "")
+}
+
+func (p *turtleParser) parseString() string {
+	line, col := p.line, p.col
+	quote := p.next()
+	long := false
+	if p.peek() == quote {
+		if p.peekAt(1) != quote {
+			p.next()
+			return ""
+		}
+		p.next()
+		p.next()
+		long = true
+	}
+
+	var value strings.Builder
+	for {
+		r := p.next()
+		switch {
+		case r == eof:
+			p.failAt(line, col, "unterminated string literal")
+		case r == '\\':
+			value.WriteRune(p.readStringEscape())
+		case r == quote && !long:
+			return value.String()
+		case r == quote && p.peek() == quote && p.peekAt(1) == quote:
+			p.next()
+			p.next()
+			return value.String()
+		case !long && (r == '\n' || r == '\r'):
+			p.failAt(line, col, "unterminated string literal (line break in short string)")
+		default:
+			value.WriteRune(r)
+		}
+	}
+}
+
+func (p *turtleParser) readStringEscape() rune {
+	switch esc := p.next(); esc {
+	case 't':
+		return '\t'
+	case 'b':
+		return '\b'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 'f':
+		return '\f'
+	case '"', '\'', '\\':
+		return esc
+	case 'u', 'U':
+		return p.readUnicodeEscape(esc)
+	default:
+		p.fail("invalid escape sequence '\\%s' in string literal", string(esc))
+		return 0
+	}
+}
+
+func (p *turtleParser) readUnicodeEscape(kind rune) rune {
+	n := 4
+	if kind == 'U' {
+		n = 8
+	}
+	var hex strings.Builder
+	for range n {
+		r := p.next()
+		if !isHex(r) {
+			p.fail("invalid unicode escape sequence")
+		}
+		hex.WriteRune(r)
+	}
+	v, err := strconv.ParseUint(hex.String(), 16, 32)
+	if err != nil {
+		p.fail("invalid unicode escape sequence")
+	}
+	return rune(v)
+}
+
+func (p *turtleParser) parseNumber() Term {
+	line, col := p.line, p.col
+	var num strings.Builder
+	if r := p.peek(); r == '+' || r == '-' {
+		num.WriteRune(p.next())
+	}
+
+	datatype := XSDInteger
+	digits := 0
+	for isDigit(p.peek()) {
+		num.WriteRune(p.next())
+		digits++
+	}
+	if p.peek() == '.' && isDigit(p.peekAt(1)) {
+		datatype = XSDDecimal
+		num.WriteRune(p.next())
+		for isDigit(p.peek()) {
+			num.WriteRune(p.next())
+			digits++
+		}
+	}
+	if digits == 0 {
+		p.failAt(line, col, "invalid numeric literal")
+	}
+	if r := p.peek(); r == 'e' || r == 'E' {
+		datatype = XSDDouble
+		num.WriteRune(p.next())
+		if r := p.peek(); r == '+' || r == '-' {
+			num.WriteRune(p.next())
+		}
+		if !isDigit(p.peek()) {
+			p.failAt(line, col, "invalid exponent in numeric literal")
+		}
+		for isDigit(p.peek()) {
+			num.WriteRune(p.next())
+		}
+	}
+	return Literal(num.String(), datatype, "")
+}
+
+func isLetter(r rune) bool {
+	return r != eof && unicode.IsLetter(r)
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isHex(r rune) bool {
+	return isDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+// isNameChar reports whether r may appear in a prefix, local name or blank node label (PN_CHARS).
+func isNameChar(r rune) bool {
+	switch {
+	case r == eof:
+		return false
+	case isLetter(r), isDigit(r), r == '_', r == '-', r == 0xB7:
+		return true
+	case r >= 0x0300 && r <= 0x036F, r >= 0x203F && r <= 0x2040:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package common
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTurtle(t *testing.T) {
+	t.Parallel()
+
+	doc := `@prefix ex: <http://example.org/> .
+@prefix dct: <http://purl.org/dc/terms/> .
+PREFIX xsd: <http://www.w3.org/2001/XMLSchema#>
+@base <http://example.org/base/> .
+
+# a comment
+ex:dataset a ex:Dataset ;
+    dct:title "Title"@en , 'Other' ;
+    dct:identifier """multi
+line""" ;
+    ex:count 42 ;
+    ex:ratio -1.5 ;
+    ex:big 1e10 ;
+    ex:flag true ;
+    ex:typed "2024-01-01"^^xsd:date ;
+    ex:rel <relative> ;
+    ex:contact [ a ex:Contact ; ex:name "N" ] ;
+    ex:list ( ex:a ex:b ) ;
+    ex:empty () ;
+    ex:local ex:name.with.dots ;
+    ex:escaped ex:a\,b .
+
+_:node ex:p _:node .
+[ ex:p ex:o ] .
+`
+
+	triples, err := ParseTurtle(strings.NewReader(doc), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		`<http://example.org/dataset> <` + RDFType + `> <http://example.org/Dataset> .`,
+		`<http://example.org/dataset> <http://purl.org/dc/terms/title> "Title"@en .`,
+		`<http://example.org/dataset> <http://purl.org/dc/terms/title> "Other" .`,
+		`<http://example.org/dataset> <http://purl.org/dc/terms/identifier> "multi\nline" .`,
+		`<http://example.org/dataset> <http://example.org/count> "42"^^<` + XSDInteger + `> .`,
+		`<http://example.org/dataset> <http://example.org/ratio> "-1.5"^^<` + XSDDecimal + `> .`,
+		`<http://example.org/dataset> <http://example.org/big> "1e10"^^<` + XSDDouble + `> .`,
+		`<http://example.org/dataset> <http://example.org/flag> "true"^^<` + XSDBoolean + `> .`,
+		`<http://example.org/dataset> <http://example.org/typed> "2024-01-01"^^<http://www.w3.org/2001/XMLSchema#date> .`,
+		`<http://example.org/dataset> <http://example.org/rel> <http://example.org/base/relative> .`,
+		`<http://example.org/dataset> <http://example.org/empty> <` + RDFNil + `> .`,
+		`<http://example.org/dataset> <http://example.org/local> <http://example.org/name.with.dots> .`,
+		`<http://example.org/dataset> <http://example.org/escaped> <http://example.org/a,b> .`,
+	}
+
+	got := map[string]bool{}
+	for _, triple := range triples {
+		got[triple.String()] = true
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("missing triple %s", w)
+		}
+	}
+
+	// 15 statements on ex:dataset, 2 for the contact, 4 for the list, 1 self reference, 1 anonymous node
+	if len(triples) != 23 {
+		t.Errorf("expected 23 triples, got %d", len(triples))
+	}
+}
+
+func TestValidateTurtle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr bool
+		line    int
+		column  int
+	}{
+		{
+			name: "valid document",
+			doc:  "@prefix ex: <http://example.org/> .\nex:a ex:b ex:c .\n",
+		},
+		{
+			name: "empty document",
+			doc:  "",
+		},
+		{
+			name:    "missing final dot",
+			doc:     "@prefix ex: <http://example.org/> .\nex:a ex:b ex:c",
+			wantErr: true,
+			line:    2,
+			column:  15,
+		},
+		{
+			name:    "undefined prefix",
+			doc:     "@prefix ex: <http://example.org/> .\nex:a foo:b ex:c .\n",
+			wantErr: true,
+			line:    2,
+			column:  6,
+		},
+		{
+			name:    "unterminated string",
+			doc:     "@prefix ex: <http://example.org/> .\nex:a ex:b \"oops .\n",
+			wantErr: true,
+			line:    2,
+			column:  11,
+		},
+		{
+			name:    "invalid IRI character",
+			doc:     "<http://example.org/a b> <http://example.org/p> <http://example.org/o> .\n",
+			wantErr: true,
+			line:    1,
+			column:  22,
+		},
+		{
+			name:    "literal as subject",
+			doc:     "\"s\" <http://example.org/p> <http://example.org/o> .\n",
+			wantErr: true,
+			line:    1,
+			column:  1,
+		},
+		{
+			name:    "unknown directive",
+			doc:     "@prefx ex: <http://example.org/> .\n",
+			wantErr: true,
+			line:    1,
+			column:  1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateTurtle(strings.NewReader(tc.doc))
+			if !tc.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected *SyntaxError, got %v", err)
+			}
+			if syntaxErr.Line != tc.line || syntaxErr.Column != tc.column {
+				t.Errorf("expected error at %d:%d, got %d:%d (%s)", tc.line, tc.column, syntaxErr.Line, syntaxErr.Column, syntaxErr.Msg)
+			}
+		})
+	}
+}
+
+func TestValidateTurtleFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "bad.ttl")
+	if err := os.WriteFile(path, []byte("<http://example.org/a> <http://example.org/b> .\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	err := ValidateTurtleFile(path)
+	if err == nil {
+		t.Fatal("Expected an error, but got nil")
+	}
+	if !strings.HasPrefix(err.Error(), path+":1:47: ") {
+		t.Errorf("Expected error prefixed with file:line:column, got %q", err.Error())
+	}
+}
@@ -0,0 +1,196 @@
+package common
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// wellKnownPrefixes are used to abbreviate IRIs in written Turtle when the source document does not declare
+// a prefix for their namespace.
+var wellKnownPrefixes = map[string]string{
+	"rdf":    rdfNS,
+	"rdfs":   "http://www.w3.org/2000/01/rdf-schema#",
+	"xsd":    "http://www.w3.org/2001/XMLSchema#",
+	"owl":    "http://www.w3.org/2002/07/owl#",
+	"dcat":   "http://www.w3.org/ns/dcat#",
+	"dct":    "http://purl.org/dc/terms/",
+	"foaf":   "http://xmlns.com/foaf/0.1/",
+	"vcard":  "http://www.w3.org/2006/vcard/ns#",
+	"schema": "http://schema.org/",
+	"skos":   "http://www.w3.org/2004/02/skos/core#",
+	"adms":   "http://www.w3.org/ns/adms#",
+	"locn":   "http://www.w3.org/ns/locn#",
+	"hydra":  "http://www.w3.org/ns/hydra/core#",
+	"epos":   "https://www.epos-eu.org/epos-dcat-ap#",
+}
+
+// WriteTurtle writes triples as a Turtle document. Triples are grouped by subject in order of first
+// appearance. IRIs are abbreviated with the given prefixes, falling back to well known prefixes, and only
+// the prefixes that are used are declared.
+func WriteTurtle(w io.Writer, triples []Triple, prefixes map[string]string) error {
+	tw := &turtleWriter{
+		prefixes:   map[string]string{},
+		namespaces: map[string]string{},
+		used:       map[string]bool{},
+		blanks:     map[string]string{},
+	}
+	// declared prefixes win over the well known ones
+	for _, prefix := range sortedPrefixes(prefixes) {
+		tw.addPrefix(prefix, prefixes[prefix])
+	}
+	for _, prefix := range sortedPrefixes(wellKnownPrefixes) {
+		tw.addPrefix(prefix, wellKnownPrefixes[prefix])
+	}
+
+	var subjects []Term
+	bySubject := map[Term][]Triple{}
+	for _, t := range triples {
+		if _, ok := bySubject[t.Subject]; !ok {
+			subjects = append(subjects, t.Subject)
+		}
+		bySubject[t.Subject] = append(bySubject[t.Subject], t)
+	}
+
+	var body strings.Builder
+	for _, subject := range subjects {
+		body.WriteString("\n")
+		body.WriteString(tw.term(subject))
+		var predicate Term
+		for i, t := range bySubject[subject] {
+			switch {
+			case i == 0:
+				body.WriteString(" ")
+			case t.Predicate == predicate:
+				body.WriteString(" ,\n        ")
+				body.WriteString(tw.term(t.Object))
+				continue
+			default:
+				body.WriteString(" ;\n    ")
+			}
+			predicate = t.Predicate
+			if t.Predicate == IRI(RDFType) {
+				body.WriteString("a")
+			} else {
+				body.WriteString(tw.term(t.Predicate))
+			}
+			body.WriteString(" ")
+			body.WriteString(tw.term(t.Object))
+		}
+		body.WriteString(" .\n")
+	}
+
+	bw := bufio.NewWriter(w)
+	var declared []string
+	for prefix := range tw.used {
+		declared = append(declared, prefix)
+	}
+	sort.Strings(declared)
+	for _, prefix := range declared {
+		fmt.Fprintf(bw, "@prefix %s: <%s> .\n", prefix, escapeIRI(tw.prefixes[prefix]))
+	}
+	bw.WriteString(body.String())
+	return bw.Flush()
+}
+
+type turtleWriter struct {
+	// prefix -> namespace
+	prefixes map[string]string
+	// namespace -> prefix
+	namespaces map[string]string
+	used       map[string]bool
+	blanks     map[string]string
+}
+
+// addPrefix declares prefix for namespace unless either of them is already taken.
+func (tw *turtleWriter) addPrefix(prefix, namespace string) {
+	if !validPrefixName(prefix) || namespace == "" {
+		return
+	}
+	if _, ok := tw.prefixes[prefix]; ok {
+		return
+	}
+	if _, ok := tw.namespaces[namespace]; ok {
+		return
+	}
+	tw.prefixes[prefix] = namespace
+	tw.namespaces[namespace] = prefix
+}
+
+func sortedPrefixes(prefixes map[string]string) []string {
+	names := make([]string, 0, len(prefixes))
+	for prefix := range prefixes {
+		names = append(names, prefix)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (tw *turtleWriter) term(t Term) string {
+	switch t.Kind {
+	case TermIRI:
+		return tw.iri(t.Value)
+	case TermBlank:
+		label, ok := tw.blanks[t.Value]
+		if !ok {
+			label = fmt.Sprintf("b%d", len(tw.blanks))
+			tw.blanks[t.Value] = label
+		}
+		return "_:" + label
+	default:
+		s := `"` + escapeString(t.Value) + `"`
+		switch {
+		case t.Lang != "":
+			return s + "@" + t.Lang
+		case t.Datatype != "" && t.Datatype != XSDString:
+			return s + "^^" + tw.iri(t.Datatype)
+		}
+		return s
+	}
+}
+
+// iri returns the prefixed name of iri when a declared namespace matches it, or the full IRI reference.
+func (tw *turtleWriter) iri(iri string) string {
+	best := ""
+	for namespace := range tw.namespaces {
+		if len(namespace) > len(best) && strings.HasPrefix(iri, namespace) && validLocalName(iri[len(namespace):]) {
+			best = namespace
+		}
+	}
+	if best == "" {
+		return "<" + escapeIRI(iri) + ">"
+	}
+	prefix := tw.namespaces[best]
+	tw.used[prefix] = true
+	return prefix + ":" + iri[len(best):]
+}
+
+// validPrefixName reports whether prefix can be declared in Turtle (PN_PREFIX, or the empty prefix).
+func validPrefixName(prefix string) bool {
+	for i, r := range prefix {
+		switch {
+		case i == 0 && !unicode.IsLetter(r):
+			return false
+		case !isNameChar(r) && r != '.':
+			return false
+		}
+	}
+	return !strings.HasSuffix(prefix, ".")
+}
+
+// validLocalName reports whether local can be written as the local part of a prefixed name without
+// escaping. Local names needing escapes are written as full IRIs instead.
+func validLocalName(local string) bool {
+	for i, r := range local {
+		switch {
+		case i == 0 && (r == '-' || r == '.'):
+			return false
+		case !isNameChar(r) && r != '.':
+			return false
+		}
+	}
+	return !strings.HasSuffix(local, ".")
+}
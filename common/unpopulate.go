@@ -0,0 +1,208 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/EPOS-ERIC/epos-opensource/common/offline"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// metadataEntityKinds maps the EPOS-DCAT-AP classes to the backoffice entity managing their instances.
+var metadataEntityKinds = map[string]string{
+	"http://www.w3.org/ns/dcat#Dataset":                 "dataproduct",
+	"http://www.w3.org/ns/dcat#Distribution":            "distribution",
+	"https://www.epos-eu.org/epos-dcat-ap#WebService":   "webservice",
+	"http://www.w3.org/ns/hydra/core#Operation":         "operation",
+	"http://schema.org/Organization":                    "organization",
+	"http://schema.org/Person":                          "person",
+	"http://schema.org/ContactPoint":                    "contactpoint",
+	"http://schema.org/SoftwareApplication":             "softwareapplication",
+	"http://schema.org/SoftwareSourceCode":              "softwaresourcecode",
+	"https://www.epos-eu.org/epos-dcat-ap#Equipment":    "equipment",
+	"https://www.epos-eu.org/epos-dcat-ap#Facility":     "facility",
+	"http://www.w3.org/2004/02/skos/core#Concept":       "category",
+	"http://www.w3.org/2004/02/skos/core#ConceptScheme": "categoryscheme",
+	"http://www.w3.org/ns/dcat#DataService":             "dataservice",
+}
+
+// Entity is a metadata entity defined by an ingested file.
+type Entity struct {
+	// Backoffice entity managing the instances of the entity (e.g., "dataproduct")
+	Kind string
+	// UID of the entity, the IRI of the subject defining it
+	UID string
+}
+
+func (e Entity) String() string {
+	return e.Kind + " " + e.UID
+}
+
+// MetadataEntities returns the entities defined by the RDF file at path: every IRI subject typed with one of
+// the EPOS-DCAT-AP classes managed by the backoffice. Entities are sorted by kind and UID. The path of an
+// archive entry (see ArchiveEntryPath) is read from the archive.
+func MetadataEntities(path string) ([]Entity, error) {
+	g := NewGraph()
+	if archivePath, entry, ok := SplitArchiveEntryPath(path); ok {
+		if err := loadArchiveEntry(g, archivePath, entry); err != nil {
+			return nil, err
+		}
+	} else if err := g.LoadFile(path); err != nil {
+		return nil, err
+	}
+	return graphEntities(g), nil
+}
+
+// ExampleEntities returns the entities defined by the example ingested from url, as recorded by
+// PopulateExample, read from its embedded copy. It fails when url is not the URL of an example of this build
+// or the example has no embedded copy.
+func ExampleEntities(url string) ([]Entity, error) {
+	for _, example := range offline.Sources().Examples {
+		if example.RemoteURL() != url {
+			continue
+		}
+		content, err := example.Content()
+		if err != nil {
+			return nil, err
+		}
+		g := NewGraph()
+		if err := g.loadReader(url, FormatTurtle, bytes.NewReader(content)); err != nil {
+			return nil, err
+		}
+		return graphEntities(g), nil
+	}
+	return nil, fmt.Errorf("%s is not an example embedded in this build", url)
+}
+
+// graphEntities returns the entities defined in g, sorted by kind and UID.
+func graphEntities(g *Graph) []Entity {
+	seen := map[Entity]struct{}{}
+	var entities []Entity
+	for _, t := range g.Triples() {
+		if t.Predicate != IRI(RDFType) || t.Subject.Kind != TermIRI {
+			continue
+		}
+		kind, ok := metadataEntityKinds[t.Object.Value]
+		if !ok {
+			continue
+		}
+		entity := Entity{Kind: kind, UID: t.Subject.Value}
+		if _, ok := seen[entity]; ok {
+			continue
+		}
+		seen[entity] = struct{}{}
+		entities = append(entities, entity)
+	}
+
+	sort.Slice(entities, func(i, j int) bool {
+		if entities[i].Kind != entities[j].Kind {
+			return entities[i].Kind < entities[j].Kind
+		}
+		return entities[i].UID < entities[j].UID
+	})
+	return entities
+}
+
+// backofficeInstance is an instance of an entity as returned by the backoffice API.
+type backofficeInstance struct {
+	InstanceID string `json:"instanceId"`
+	UID        string `json:"uid"`
+}
+
+// EntityRemover deletes entities from an environment through the backoffice API exposed by the gateway.
+// The instances of every kind are listed once with GET {endpoint}/{kind}/all and cached.
+type EntityRemover struct {
+	endpoint  *url.URL
+	token     string
+	instances map[string][]backofficeInstance
+}
+
+// NewEntityRemover returns an EntityRemover for the gateway at endpointURL (e.g., "http://gateway/api/v1").
+// When token is set it is sent as a bearer token, which the backoffice API requires when it is behind auth.
+func NewEntityRemover(endpointURL, token string) (*EntityRemover, error) {
+	endpointURL = strings.TrimSuffix(endpointURL, "/ui")
+	endpoint, err := url.Parse(endpointURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint URL '%s': %w", endpointURL, err)
+	}
+	return &EntityRemover{endpoint: endpoint, token: token, instances: map[string][]backofficeInstance{}}, nil
+}
+
+// Remove deletes every instance of entity, including older versions with the same UID, with
+// DELETE {endpoint}/{kind}/{instanceId}. It returns false when the environment has no instance of entity.
+func (r *EntityRemover) Remove(ctx context.Context, entity Entity) (bool, error) {
+	instances, err := r.list(ctx, entity.Kind)
+	if err != nil {
+		return false, err
+	}
+
+	removed := false
+	for _, instance := range instances {
+		if instance.UID != entity.UID {
+			continue
+		}
+		display.Debug("deleting %s instance %s", entity, instance.InstanceID)
+		reqURL := r.endpoint.JoinPath(entity.Kind, instance.InstanceID)
+		if _, err := r.do(ctx, http.MethodDelete, reqURL); err != nil {
+			return removed, fmt.Errorf("failed to delete %s: %w", entity, err)
+		}
+		removed = true
+	}
+	return removed, nil
+}
+
+func (r *EntityRemover) list(ctx context.Context, kind string) ([]backofficeInstance, error) {
+	if instances, ok := r.instances[kind]; ok {
+		return instances, nil
+	}
+
+	body, err := r.do(ctx, http.MethodGet, r.endpoint.JoinPath(kind, "all"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s entities: %w", kind, err)
+	}
+
+	var instances []backofficeInstance
+	if err := json.Unmarshal(body, &instances); err != nil {
+		return nil, fmt.Errorf("invalid response listing %s entities: %w", kind, err)
+	}
+	r.instances[kind] = instances
+	return instances, nil
+}
+
+func (r *EntityRemover) do(ctx context.Context, method string, reqURL *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	display.Debug("sending request: %s %s", method, req.URL.String())
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("the backoffice refused the request with status %d, the access token must be valid and belong to a backoffice administrator", res.StatusCode)
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, &httpStatusError{Name: reqURL.Path, StatusCode: res.StatusCode, Body: string(body)}
+	}
+	return body, nil
+}
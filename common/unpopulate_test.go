@@ -0,0 +1,115 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestMetadataEntities(t *testing.T) {
+	t.Parallel()
+
+	content := `@prefix dcat: <http://www.w3.org/ns/dcat#> .
+@prefix dct: <http://purl.org/dc/terms/> .
+@prefix schema: <http://schema.org/> .
+@prefix epos: <https://www.epos-eu.org/epos-dcat-ap#> .
+
+<https://example.org/dataset/1> a dcat:Dataset ;
+    dct:title "Dataset" ;
+    dcat:distribution <https://example.org/distribution/1> .
+<https://example.org/distribution/1> a dcat:Distribution ;
+    dcat:accessService [ a epos:WebService ] .
+<https://example.org/org/1> a schema:Organization , schema:Organization .
+<https://example.org/dataset/1> a dcat:Dataset .
+<https://example.org/other> a schema:Thing .
+`
+	path := filepath.Join(t.TempDir(), "metadata.ttl")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	entities, err := MetadataEntities(path)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	// the blank node web service has no UID and is not an entity
+	expected := []Entity{
+		{Kind: "dataproduct", UID: "https://example.org/dataset/1"},
+		{Kind: "distribution", UID: "https://example.org/distribution/1"},
+		{Kind: "organization", UID: "https://example.org/org/1"},
+	}
+	if !reflect.DeepEqual(entities, expected) {
+		t.Errorf("Expected %v, got %v", expected, entities)
+	}
+}
+
+func TestEntityRemover(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Expected the bearer token to be sent, got %q", auth)
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/dataproduct/all":
+			_, _ = w.Write([]byte(`[
+				{"instanceId": "i1", "uid": "https://example.org/dataset/1"},
+				{"instanceId": "i2", "uid": "https://example.org/dataset/2"},
+				{"instanceId": "i3", "uid": "https://example.org/dataset/1"}
+			]`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/distribution/all":
+			_, _ = w.Write([]byte(`[]`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/dataproduct/i3":
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	remover, err := NewEntityRemover(server.URL+"/api/v1", "secret")
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	found, err := remover.Remove(t.Context(), Entity{Kind: "dataproduct", UID: "https://example.org/dataset/2"})
+	if err != nil || !found {
+		t.Errorf("Expected dataset 2 to be removed, got %v and error %v", found, err)
+	}
+
+	found, err = remover.Remove(t.Context(), Entity{Kind: "distribution", UID: "https://example.org/distribution/1"})
+	if err != nil || found {
+		t.Errorf("Expected distribution 1 not to be found, got %v and error %v", found, err)
+	}
+
+	// both instances of dataset 1 are deleted, the second deletion fails
+	_, err = remover.Remove(t.Context(), Entity{Kind: "dataproduct", UID: "https://example.org/dataset/1"})
+	if err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+
+	expected := []string{
+		"GET /api/v1/dataproduct/all",
+		"DELETE /api/v1/dataproduct/i2",
+		"GET /api/v1/distribution/all",
+		"DELETE /api/v1/dataproduct/i1",
+		"DELETE /api/v1/dataproduct/i3",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+}
@@ -0,0 +1,112 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// requestBody is the body of an upload request. It is opened again for every attempt, so that a body streamed
+// from a file is sent from its start on every retry.
+type requestBody struct {
+	// open returns a reader of the whole body
+	open func() (io.ReadCloser, error)
+	// size of the body in bytes, sent as the Content-Length of uncompressed requests
+	size int64
+}
+
+// bytesBody returns a body sending content, kept in memory.
+func bytesBody(content []byte) requestBody {
+	return requestBody{
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(content)), nil
+		},
+		size: int64(len(content)),
+	}
+}
+
+// fileBody returns a body streaming the file at path, whose size is size, without loading it in memory.
+func fileBody(path string, size int64) requestBody {
+	return requestBody{
+		open: func() (io.ReadCloser, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open file '%s': %w", filepath.Base(path), err)
+			}
+			return f, nil
+		},
+		size: size,
+	}
+}
+
+// gzipReader returns a reader of src compressed with gzip. src is compressed while the reader is read, and
+// is closed once fully read or when the reader is closed.
+func gzipReader(src io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer src.Close()
+
+		zw := gzip.NewWriter(pw)
+		_, err := io.Copy(zw, src)
+		if err == nil {
+			err = zw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// hashBody returns the content hash and size of body, the content of the file at path, reading it as a stream.
+func hashBody(path string, body requestBody) (IngestedFile, error) {
+	r, err := body.open()
+	if err != nil {
+		return IngestedFile{}, err
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, r)
+	if err != nil {
+		return IngestedFile{}, fmt.Errorf("failed to read file '%s': %w", filepath.Base(path), err)
+	}
+
+	return IngestedFile{
+		Path:        path,
+		ContentHash: hex.EncodeToString(h.Sum(nil)),
+		SizeBytes:   size,
+	}, nil
+}
+
+// ContentHash returns the content hash of the file at path, as recorded for the files ingested by PopulateEnv.
+// The path of an archive entry (see ArchiveEntryPath) is read from the archive.
+func ContentHash(path string) (string, error) {
+	body := fileBody(path, 0)
+	if archivePath, entry, ok := SplitArchiveEntryPath(path); ok {
+		body = archiveEntryBody(archivePath, entry, 0)
+	}
+	file, err := hashBody(path, body)
+	if err != nil {
+		return "", err
+	}
+	return file.ContentHash, nil
+}
+
+// readBody returns the whole content of body, the content of the file at path.
+func readBody(path string, body requestBody) ([]byte, error) {
+	r, err := body.open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s': %w", filepath.Base(path), err)
+	}
+	return content, nil
+}
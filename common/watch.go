@@ -0,0 +1,290 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is the quiet period Watch waits for after the last change before re-ingesting files.
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// WatchOpts defines inputs for Watch.
+type WatchOpts struct {
+	// Required. paths to RDF files or directories containing RDF files to watch
+	Paths []string
+	// Required. base URL of the EPOS gateway (e.g., "http://gateway/api/v1")
+	EndpointURL string
+	// Optional. content hashes of the ingested files keyed by absolute path. A changed file whose content
+	// matches its recorded hash is not posted again. The map is updated with every re-ingested file
+	Ingested map[string]string
+	// Optional. how failed uploads are retried. The zero value disables retries
+	Retry RetryPolicy
+	// Optional. ingestion type, model and mapping of the files. Empty fields use the defaults, and the
+	// ingestion manifests found in the watched directories override them
+	Ingestion IngestionSettings
+	// Optional. glob patterns selecting the files watched in a directory, applied with the ignore file of the
+	// directory as PopulateEnv does
+	Filter PathFilter
+	// Optional. compress the re-ingested files with gzip, as PopulateEnvOpts.Compress
+	Compress bool
+	// Optional. quiet period after the last change before the changed files are re-ingested. If not set
+	// DefaultWatchDebounce is used
+	Debounce time.Duration
+	// Optional. called with every re-ingested file. An error stops Watch
+	OnIngested func(IngestedFile) error
+}
+
+// watchRoot is a path given to Watch: a directory whose RDF files selected by filter are watched, or a
+// single file.
+type watchRoot struct {
+	path   string
+	isDir  bool
+	filter PathFilter
+}
+
+// Watch watches the given paths with filesystem notifications and re-ingests the RDF files that change, until
+// ctx is done. Directories are watched recursively, including the subdirectories created while watching, and
+// a single file is watched through its parent directory so that editors replacing the file on save are
+// handled too.
+//
+// Bursts of changes are debounced: the changed files are re-ingested once no further change happened for
+// opts.Debounce. Every file is checked for syntax errors and posted through the same path as PopulateEnv,
+// with the ingestion settings resolved from the manifests at that time, and a single result line is printed
+// for it. A file that fails is reported and retried on its next change, it does not stop Watch.
+//
+// Watch returns nil once ctx is done, and an error if the paths cannot be watched or opts.OnIngested fails.
+func Watch(ctx context.Context, opts WatchOpts) error {
+	endpointURL := strings.TrimSuffix(opts.EndpointURL, "/ui")
+	postURL, err := url.Parse(endpointURL)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint URL '%s': %w", endpointURL, err)
+	}
+	postURL = postURL.JoinPath("/populate")
+
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultWatchDebounce
+	}
+	if opts.Ingested == nil {
+		opts.Ingested = map[string]string{}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	var roots []watchRoot
+	for _, p := range opts.Paths {
+		absPath, err := filepath.Abs(p)
+		if err != nil {
+			return fmt.Errorf("failed to resolve absolute path: %w", err)
+		}
+		fi, err := os.Stat(absPath)
+		if err != nil {
+			return fmt.Errorf("cannot access path '%s': %w", p, err)
+		}
+
+		root := watchRoot{path: absPath, isDir: fi.IsDir()}
+		if root.isDir {
+			if root.filter, err = opts.Filter.withIgnoreFile(absPath); err != nil {
+				return err
+			}
+			err = addWatchDirs(watcher, absPath)
+		} else {
+			err = watcher.Add(filepath.Dir(absPath))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to watch '%s': %w", p, err)
+		}
+		roots = append(roots, root)
+	}
+
+	display.Info("Watching %d path(s) for changes, press Ctrl-C to stop", len(roots))
+
+	pending := map[string]struct{}{}
+	timer := time.NewTimer(opts.Debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			display.Info("Stopped watching for changes")
+			return nil
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			display.Warn("File watcher error: %v", err)
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			display.Debug("file event: %s", event)
+
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+				continue
+			}
+
+			changed := []string{event.Name}
+			if fi, err := os.Stat(event.Name); err == nil && fi.IsDir() {
+				if root := watchRootOf(roots, event.Name); root == nil || root.excludesDir(event.Name) {
+					continue
+				}
+				// files copied or moved together with a new directory may not produce events of their own
+				if err := addWatchDirs(watcher, event.Name); err != nil {
+					display.Warn("Cannot watch new directory '%s': %v", event.Name, err)
+				}
+				changed, _, err = collectRDFFiles(event.Name, PathFilter{})
+				if err != nil {
+					display.Warn("Cannot list new directory '%s': %v", event.Name, err)
+					continue
+				}
+			}
+
+			for _, path := range changed {
+				if watchedFile(roots, path) {
+					pending[path] = struct{}{}
+				}
+			}
+			if len(pending) > 0 {
+				timer.Reset(opts.Debounce)
+			}
+
+		case <-timer.C:
+			files := slices.Sorted(maps.Keys(pending))
+			clear(pending)
+
+			if err := reingestFiles(ctx, files, roots, *postURL, opts); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// reingestFiles checks and posts every changed file that still exists, printing one result line per file.
+func reingestFiles(ctx context.Context, files []string, roots []watchRoot, postURL url.URL, opts WatchOpts) error {
+	base := opts.Ingestion.Inherit(DefaultIngestionSettings())
+	populateOpts := PopulateEnvOpts{Ingested: opts.Ingested, Retry: opts.Retry, Compress: opts.Compress}
+
+	for _, path := range files {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if _, err := os.Stat(path); err != nil {
+			display.Debug("ignoring change of removed file: %s", path)
+			continue
+		}
+
+		name := filepath.Base(path)
+		if err := ValidateRDFFile(path); err != nil {
+			display.Error("Not re-ingesting '%s': %v", name, err)
+			continue
+		}
+
+		root := watchRootOf(roots, path)
+		settings, err := resolveIngestionSettings(root.dir(), []string{path}, base)
+		if err != nil {
+			display.Error("Not re-ingesting '%s': %v", name, err)
+			continue
+		}
+
+		start := time.Now()
+		file, changed, err := postFile(ctx, path, postURL, settings[path], populateOpts)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			display.Warn("Re-ingestion of '%s' aborted", name)
+			return nil
+		case err != nil:
+			display.Error("Failed to re-ingest '%s': %v", name, err)
+			continue
+		case !changed:
+			continue
+		}
+
+		opts.Ingested[path] = file.ContentHash
+		display.Done("Re-ingested '%s' in %s", name, time.Since(start).Round(time.Millisecond))
+
+		if opts.OnIngested != nil {
+			if err := opts.OnIngested(file); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addWatchDirs adds dir and all of its subdirectories to watcher.
+func addWatchDirs(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		return watcher.Add(path)
+	})
+}
+
+// watchRootOf returns the innermost root containing path, or nil when path is not below any root.
+func watchRootOf(roots []watchRoot, path string) *watchRoot {
+	var match *watchRoot
+	for i, root := range roots {
+		inside := path == root.path
+		if root.isDir {
+			inside = inside || strings.HasPrefix(path, root.path+string(filepath.Separator))
+		}
+		if inside && (match == nil || len(root.path) > len(match.path)) {
+			match = &roots[i]
+		}
+	}
+	return match
+}
+
+// watchedFile reports whether path is a file that Watch re-ingests: a file given directly, or a file with an
+// RDF extension below a watched directory and selected by its filter.
+func watchedFile(roots []watchRoot, path string) bool {
+	root := watchRootOf(roots, path)
+	if root == nil {
+		return false
+	}
+	if !root.isDir {
+		return true
+	}
+	if _, ok := RDFFormatFromExtension(filepath.Base(path)); !ok {
+		return false
+	}
+	rel, err := filepath.Rel(root.path, path)
+	return err == nil && root.filter.selects(filepath.ToSlash(rel))
+}
+
+// excludesDir reports whether the directory at path, below the root, is excluded by the filter of the root.
+func (r *watchRoot) excludesDir(path string) bool {
+	rel, err := filepath.Rel(r.path, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	return r.filter.excludes(filepath.ToSlash(rel), true)
+}
+
+// dir returns the directory the ingestion manifests of the files of the root are resolved from.
+func (r *watchRoot) dir() string {
+	if r.isDir {
+		return r.path
+	}
+	return filepath.Dir(r.path)
+}
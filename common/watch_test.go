@@ -0,0 +1,163 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	writeFile := func(path, content string) {
+		t.Helper()
+		fullPath := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	writeFile("a.ttl", ttl("content a"))
+	writeFile("notes.txt", "not metadata")
+
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var recordedMu sync.Mutex
+	recorded := map[string]string{}
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, WatchOpts{
+			Paths:       []string{tmpDir},
+			EndpointURL: server.URL,
+			Debounce:    50 * time.Millisecond,
+			OnIngested: func(file IngestedFile) error {
+				recordedMu.Lock()
+				recorded[file.Path] = file.ContentHash
+				recordedMu.Unlock()
+				return nil
+			},
+		})
+	}()
+
+	waitForRequests := func(n int) []string {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			got := append([]string(nil), received...)
+			mu.Unlock()
+			if len(got) >= n {
+				return got
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("Timed out waiting for %d request(s)", n)
+		return nil
+	}
+
+	// the watcher is set up asynchronously, keep writing until the first change is picked up
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		writeFile("a.ttl", ttl("burst 1"))
+		writeFile("a.ttl", ttl("burst 2"))
+		writeFile("a.ttl", ttl("burst 3"))
+		time.Sleep(200 * time.Millisecond)
+		mu.Lock()
+		n := len(received)
+		mu.Unlock()
+		if n > 0 || time.Now().After(deadline) {
+			break
+		}
+	}
+	got := waitForRequests(1)
+	if got[len(got)-1] != ttl("burst 3") {
+		t.Errorf("Expected the last content of the burst to be posted, got %q", got[len(got)-1])
+	}
+
+	mu.Lock()
+	before := len(received)
+	mu.Unlock()
+
+	writeFile("notes.txt", "still not metadata")
+	writeFile("a.ttl", ttl("burst 3"))
+	writeFile("sub/b.ttl", ttl("content b"))
+	waitForRequests(before + 1)
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	got = append([]string(nil), received...)
+	mu.Unlock()
+	if len(got) != before+1 || got[before] != ttl("content b") {
+		t.Errorf("Expected only the new file in a new directory to be posted, got %q", got[before:])
+	}
+
+	writeFile("a.ttl", "<urn:ex:s> <urn:ex:p> .")
+	time.Sleep(300 * time.Millisecond)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != before+1 {
+		t.Errorf("Expected the invalid file not to be posted, got %d request(s)", len(received))
+	}
+
+	recordedMu.Lock()
+	defer recordedMu.Unlock()
+	if _, ok := recorded[filepath.Join(tmpDir, "sub", "b.ttl")]; !ok || len(recorded) != 2 {
+		t.Errorf("Expected both re-ingested files to be recorded, got %v", recorded)
+	}
+}
+
+func TestWatchedFile(t *testing.T) {
+	t.Parallel()
+
+	roots := []watchRoot{
+		{path: filepath.FromSlash("/data"), isDir: true},
+		{path: filepath.FromSlash("/single/metadata.txt")},
+	}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{path: "/data/a.ttl", expected: true},
+		{path: "/data/sub/b.jsonld", expected: true},
+		{path: "/data/notes.txt", expected: false},
+		{path: "/data-old/a.ttl", expected: false},
+		{path: "/single/metadata.txt", expected: true},
+		{path: "/single/other.ttl", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := watchedFile(roots, filepath.FromSlash(tc.path)); got != tc.expected {
+				t.Errorf("Expected %v for %s, got %v", tc.expected, tc.path, got)
+			}
+		})
+	}
+}
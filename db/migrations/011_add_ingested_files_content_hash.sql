@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE
+    ingested_files
+ADD
+    COLUMN content_hash TEXT;
+
+ALTER TABLE
+    ingested_files
+ADD
+    COLUMN size_bytes INTEGER;
+
+-- +goose Down
+ALTER TABLE
+    ingested_files DROP COLUMN size_bytes;
+
+ALTER TABLE
+    ingested_files DROP COLUMN content_hash;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE k8s_ingested_files (
+    context TEXT NOT NULL,
+    environment_name TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    content_hash TEXT,
+    size_bytes INTEGER,
+    ingested_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (context, environment_name, file_path)
+);
+
+-- +goose Down
+DROP TABLE k8s_ingested_files;
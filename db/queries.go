@@ -133,15 +133,21 @@ func UpsertImageUpdateCache(ctx context.Context, imageRef, remoteDigest string,
 }
 
 // InsertIngestedFile inserts or updates an ingested file record for a docker environment.
-func InsertIngestedFile(envName, filePath string) error {
+// An empty contentHash (e.g. for remote examples) stores no hash or size for the record.
+func InsertIngestedFile(envName, filePath, contentHash string, sizeBytes int64) error {
 	q, err := Get()
 	if err != nil {
 		return fmt.Errorf("error getting db connection: %w", err)
 	}
-	err = q.InsertIngestedFile(context.Background(), sqlc.InsertIngestedFileParams{
+	params := sqlc.InsertIngestedFileParams{
 		EnvironmentName: envName,
 		FilePath:        filePath,
-	})
+	}
+	if contentHash != "" {
+		params.ContentHash = &contentHash
+		params.SizeBytes = &sizeBytes
+	}
+	err = q.InsertIngestedFile(context.Background(), params)
 	if err != nil {
 		return fmt.Errorf("error inserting ingested file: %w", err)
 	}
@@ -161,6 +167,22 @@ func DeleteIngestedFilesByEnvironment(envName string) error {
 	return nil
 }
 
+// DeleteIngestedFile deletes the ingested file record of a single file of an environment.
+func DeleteIngestedFile(envName, filePath string) error {
+	q, err := Get()
+	if err != nil {
+		return fmt.Errorf("error getting db connection: %w", err)
+	}
+	err = q.DeleteIngestedFile(context.Background(), sqlc.DeleteIngestedFileParams{
+		EnvironmentName: envName,
+		FilePath:        filePath,
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting ingested file: %w", err)
+	}
+	return nil
+}
+
 // GetIngestedFilesByEnvironment retrieves all ingested file records for an environment.
 func GetIngestedFilesByEnvironment(envName string) ([]sqlc.GetIngestedFilesByEnvironmentRow, error) {
 	q, err := Get()
@@ -173,3 +195,59 @@ func GetIngestedFilesByEnvironment(envName string) ([]sqlc.GetIngestedFilesByEnv
 	}
 	return files, nil
 }
+
+// InsertK8sIngestedFile inserts or updates the cached record of a file ingested into a K8s environment.
+// An empty contentHash (e.g. for remote examples) stores no hash or size for the record.
+func InsertK8sIngestedFile(kubeContext, envName, filePath, contentHash string, sizeBytes int64, ingestedAt time.Time) error {
+	q, err := Get()
+	if err != nil {
+		return fmt.Errorf("error getting db connection: %w", err)
+	}
+	params := sqlc.InsertK8sIngestedFileParams{
+		Context:         kubeContext,
+		EnvironmentName: envName,
+		FilePath:        filePath,
+		IngestedAt:      &ingestedAt,
+	}
+	if contentHash != "" {
+		params.ContentHash = &contentHash
+		params.SizeBytes = &sizeBytes
+	}
+	err = q.InsertK8sIngestedFile(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("error inserting k8s ingested file: %w", err)
+	}
+	return nil
+}
+
+// DeleteK8sIngestedFilesByEnvironment deletes all cached ingested file records of a K8s environment.
+func DeleteK8sIngestedFilesByEnvironment(kubeContext, envName string) error {
+	q, err := Get()
+	if err != nil {
+		return fmt.Errorf("error getting db connection: %w", err)
+	}
+	err = q.DeleteK8sIngestedFilesByEnvironment(context.Background(), sqlc.DeleteK8sIngestedFilesByEnvironmentParams{
+		Context:         kubeContext,
+		EnvironmentName: envName,
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting k8s ingested files: %w", err)
+	}
+	return nil
+}
+
+// GetK8sIngestedFilesByEnvironment retrieves all cached ingested file records of a K8s environment.
+func GetK8sIngestedFilesByEnvironment(kubeContext, envName string) ([]sqlc.GetK8sIngestedFilesByEnvironmentRow, error) {
+	q, err := Get()
+	if err != nil {
+		return nil, fmt.Errorf("error getting db connection: %w", err)
+	}
+	files, err := q.GetK8sIngestedFilesByEnvironment(context.Background(), sqlc.GetK8sIngestedFilesByEnvironmentParams{
+		Context:         kubeContext,
+		EnvironmentName: envName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting k8s ingested files: %w", err)
+	}
+	return files, nil
+}
@@ -83,12 +83,16 @@ INSERT INTO
     ingested_files (
         environment_name,
         file_path,
+        content_hash,
+        size_bytes,
         ingested_at
     )
 VALUES
-    (?, ?, CURRENT_TIMESTAMP) ON CONFLICT (environment_name, file_path) DO
+    (?, ?, ?, ?, CURRENT_TIMESTAMP) ON CONFLICT (environment_name, file_path) DO
 UPDATE
 SET
+    content_hash = excluded.content_hash,
+    size_bytes = excluded.size_bytes,
     ingested_at = CURRENT_TIMESTAMP;
 
 -- name: DeleteIngestedFilesByEnvironment :exec
@@ -97,9 +101,18 @@ DELETE FROM
 WHERE
     environment_name = ?;
 
+-- name: DeleteIngestedFile :exec
+DELETE FROM
+    ingested_files
+WHERE
+    environment_name = ?
+    AND file_path = ?;
+
 -- name: GetIngestedFilesByEnvironment :many
 SELECT
     file_path,
+    content_hash,
+    size_bytes,
     ingested_at
 FROM
     ingested_files
@@ -107,3 +120,42 @@ WHERE
     environment_name = ?
 ORDER BY
     ingested_at DESC;
+
+-- name: InsertK8sIngestedFile :exec
+INSERT INTO
+    k8s_ingested_files (
+        context,
+        environment_name,
+        file_path,
+        content_hash,
+        size_bytes,
+        ingested_at
+    )
+VALUES
+    (?, ?, ?, ?, ?, ?) ON CONFLICT (context, environment_name, file_path) DO
+UPDATE
+SET
+    content_hash = excluded.content_hash,
+    size_bytes = excluded.size_bytes,
+    ingested_at = excluded.ingested_at;
+
+-- name: DeleteK8sIngestedFilesByEnvironment :exec
+DELETE FROM
+    k8s_ingested_files
+WHERE
+    context = ?
+    AND environment_name = ?;
+
+-- name: GetK8sIngestedFilesByEnvironment :many
+SELECT
+    file_path,
+    content_hash,
+    size_bytes,
+    ingested_at
+FROM
+    k8s_ingested_files
+WHERE
+    context = ?
+    AND environment_name = ?
+ORDER BY
+    ingested_at DESC;
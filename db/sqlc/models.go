@@ -24,6 +24,17 @@ type IngestedFile struct {
 	EnvironmentName string
 	FilePath        string
 	IngestedAt      *time.Time
+	ContentHash     *string
+	SizeBytes       *int64
+}
+
+type K8sIngestedFile struct {
+	Context         string
+	EnvironmentName string
+	FilePath        string
+	ContentHash     *string
+	SizeBytes       *int64
+	IngestedAt      *time.Time
 }
 
 type LatestReleaseCache struct {
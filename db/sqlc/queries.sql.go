@@ -22,6 +22,24 @@ func (q *Queries) DeleteDocker(ctx context.Context, name string) error {
 	return err
 }
 
+const deleteIngestedFile = `-- name: DeleteIngestedFile :exec
+DELETE FROM
+    ingested_files
+WHERE
+    environment_name = ?
+    AND file_path = ?
+`
+
+type DeleteIngestedFileParams struct {
+	EnvironmentName string
+	FilePath        string
+}
+
+func (q *Queries) DeleteIngestedFile(ctx context.Context, arg DeleteIngestedFileParams) error {
+	_, err := q.db.ExecContext(ctx, deleteIngestedFile, arg.EnvironmentName, arg.FilePath)
+	return err
+}
+
 const deleteIngestedFilesByEnvironment = `-- name: DeleteIngestedFilesByEnvironment :exec
 DELETE FROM
     ingested_files
@@ -34,6 +52,24 @@ func (q *Queries) DeleteIngestedFilesByEnvironment(ctx context.Context, environm
 	return err
 }
 
+const deleteK8sIngestedFilesByEnvironment = `-- name: DeleteK8sIngestedFilesByEnvironment :exec
+DELETE FROM
+    k8s_ingested_files
+WHERE
+    context = ?
+    AND environment_name = ?
+`
+
+type DeleteK8sIngestedFilesByEnvironmentParams struct {
+	Context         string
+	EnvironmentName string
+}
+
+func (q *Queries) DeleteK8sIngestedFilesByEnvironment(ctx context.Context, arg DeleteK8sIngestedFilesByEnvironmentParams) error {
+	_, err := q.db.ExecContext(ctx, deleteK8sIngestedFilesByEnvironment, arg.Context, arg.EnvironmentName)
+	return err
+}
+
 const getAllDocker = `-- name: GetAllDocker :many
 SELECT
     name,
@@ -112,6 +148,8 @@ func (q *Queries) GetImageUpdateCache(ctx context.Context, imageRef string) (Ima
 const getIngestedFilesByEnvironment = `-- name: GetIngestedFilesByEnvironment :many
 SELECT
     file_path,
+    content_hash,
+    size_bytes,
     ingested_at
 FROM
     ingested_files
@@ -122,8 +160,10 @@ ORDER BY
 `
 
 type GetIngestedFilesByEnvironmentRow struct {
-	FilePath   string
-	IngestedAt *time.Time
+	FilePath    string
+	ContentHash *string
+	SizeBytes   *int64
+	IngestedAt  *time.Time
 }
 
 func (q *Queries) GetIngestedFilesByEnvironment(ctx context.Context, environmentName string) ([]GetIngestedFilesByEnvironmentRow, error) {
@@ -135,7 +175,67 @@ func (q *Queries) GetIngestedFilesByEnvironment(ctx context.Context, environment
 	var items []GetIngestedFilesByEnvironmentRow
 	for rows.Next() {
 		var i GetIngestedFilesByEnvironmentRow
-		if err := rows.Scan(&i.FilePath, &i.IngestedAt); err != nil {
+		if err := rows.Scan(
+			&i.FilePath,
+			&i.ContentHash,
+			&i.SizeBytes,
+			&i.IngestedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getK8sIngestedFilesByEnvironment = `-- name: GetK8sIngestedFilesByEnvironment :many
+SELECT
+    file_path,
+    content_hash,
+    size_bytes,
+    ingested_at
+FROM
+    k8s_ingested_files
+WHERE
+    context = ?
+    AND environment_name = ?
+ORDER BY
+    ingested_at DESC
+`
+
+type GetK8sIngestedFilesByEnvironmentParams struct {
+	Context         string
+	EnvironmentName string
+}
+
+type GetK8sIngestedFilesByEnvironmentRow struct {
+	FilePath    string
+	ContentHash *string
+	SizeBytes   *int64
+	IngestedAt  *time.Time
+}
+
+func (q *Queries) GetK8sIngestedFilesByEnvironment(ctx context.Context, arg GetK8sIngestedFilesByEnvironmentParams) ([]GetK8sIngestedFilesByEnvironmentRow, error) {
+	rows, err := q.db.QueryContext(ctx, getK8sIngestedFilesByEnvironment, arg.Context, arg.EnvironmentName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetK8sIngestedFilesByEnvironmentRow
+	for rows.Next() {
+		var i GetK8sIngestedFilesByEnvironmentRow
+		if err := rows.Scan(
+			&i.FilePath,
+			&i.ContentHash,
+			&i.SizeBytes,
+			&i.IngestedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -170,22 +270,73 @@ INSERT INTO
     ingested_files (
         environment_name,
         file_path,
+        content_hash,
+        size_bytes,
         ingested_at
     )
 VALUES
-    (?, ?, CURRENT_TIMESTAMP) ON CONFLICT (environment_name, file_path) DO
+    (?, ?, ?, ?, CURRENT_TIMESTAMP) ON CONFLICT (environment_name, file_path) DO
 UPDATE
 SET
+    content_hash = excluded.content_hash,
+    size_bytes = excluded.size_bytes,
     ingested_at = CURRENT_TIMESTAMP
 `
 
 type InsertIngestedFileParams struct {
 	EnvironmentName string
 	FilePath        string
+	ContentHash     *string
+	SizeBytes       *int64
 }
 
 func (q *Queries) InsertIngestedFile(ctx context.Context, arg InsertIngestedFileParams) error {
-	_, err := q.db.ExecContext(ctx, insertIngestedFile, arg.EnvironmentName, arg.FilePath)
+	_, err := q.db.ExecContext(ctx, insertIngestedFile,
+		arg.EnvironmentName,
+		arg.FilePath,
+		arg.ContentHash,
+		arg.SizeBytes,
+	)
+	return err
+}
+
+const insertK8sIngestedFile = `-- name: InsertK8sIngestedFile :exec
+INSERT INTO
+    k8s_ingested_files (
+        context,
+        environment_name,
+        file_path,
+        content_hash,
+        size_bytes,
+        ingested_at
+    )
+VALUES
+    (?, ?, ?, ?, ?, ?) ON CONFLICT (context, environment_name, file_path) DO
+UPDATE
+SET
+    content_hash = excluded.content_hash,
+    size_bytes = excluded.size_bytes,
+    ingested_at = excluded.ingested_at
+`
+
+type InsertK8sIngestedFileParams struct {
+	Context         string
+	EnvironmentName string
+	FilePath        string
+	ContentHash     *string
+	SizeBytes       *int64
+	IngestedAt      *time.Time
+}
+
+func (q *Queries) InsertK8sIngestedFile(ctx context.Context, arg InsertK8sIngestedFileParams) error {
+	_, err := q.db.ExecContext(ctx, insertK8sIngestedFile,
+		arg.Context,
+		arg.EnvironmentName,
+		arg.FilePath,
+		arg.ContentHash,
+		arg.SizeBytes,
+		arg.IngestedAt,
+	)
 	return err
 }
 
@@ -255,3 +255,40 @@ func ImageUpdatesAvailable(updates []ImageUpdateInfo, envName string) {
 
 	_, _ = fmt.Fprintf(Stdout, "%s\n", t.Render())
 }
+
+// FailedFile is a file that could not be ingested, as listed by FailureSummary.
+type FailedFile struct {
+	Path   string
+	Status string
+	Reason string
+}
+
+// FailureSummary prints a table of the files that could not be ingested, with the reason of every failure.
+func FailureSummary(failures []FailedFile) {
+	if len(failures) == 0 {
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetTitle(fmt.Sprintf("%d File(s) Not Ingested", len(failures)))
+	t.SetStyle(table.StyleRounded)
+
+	t.Style().Title.Align = text.AlignCenter
+	t.Style().Title.Colors = text.Colors{text.FgRed, text.Bold}
+	t.Style().Color.Border = text.Colors{text.FgRed}
+	t.Style().Color.Separator = text.Colors{text.FgRed}
+	t.Style().Color.Header = text.Colors{text.FgCyan}
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 1, WidthMax: 60},
+		{Number: 2, Colors: text.Colors{text.FgYellow}},
+		{Number: 3, WidthMax: 80},
+	})
+
+	t.AppendHeader(table.Row{"File", "Status", "Reason"})
+	for _, failure := range failures {
+		t.AppendRow(table.Row{failure.Path, failure.Status, failure.Reason})
+		t.AppendSeparator()
+	}
+
+	_, _ = fmt.Fprintf(Stdout, "%s\n", t.Render())
+}
@@ -0,0 +1,167 @@
+// Command offlinedata downloads the copies of the example datasets and base ontologies embedded by package
+// offline, and pins them in common/offline/sources.yaml.
+//
+// Sources that are not pinned yet are pinned to the current head of their branch. With -update every source
+// is pinned again to the current head of its branch. Copies that are missing or do not match their checksum
+// are downloaded again at their pinned revision.
+//
+// Run it from the root of the repository with "make offline-data".
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/common/offline"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	manifestPath = "common/offline/sources.yaml"
+	dataDir      = "common/offline/" + offline.DataDir
+)
+
+var client = &http.Client{Timeout: 1 * time.Minute}
+
+func main() {
+	update := flag.Bool("update", false, "pin every source to the current head of its branch")
+	flag.Parse()
+
+	if err := run(context.Background(), *update); err != nil {
+		fmt.Fprintf(os.Stderr, "offlinedata: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, update bool) error {
+	raw, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("error reading manifest, run from the root of the repository: %w", err)
+	}
+	m, err := offline.ParseManifest(raw)
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %w", manifestPath, err)
+	}
+
+	for _, sources := range [][]offline.Source{m.Ontologies, m.Examples} {
+		for i := range sources {
+			if err := sync(ctx, &sources[i], update); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writeManifest(raw, m)
+}
+
+// sync pins src when needed and makes sure its copy in dataDir matches the pinned checksum.
+func sync(ctx context.Context, src *offline.Source, update bool) error {
+	target := filepath.Join(dataDir, filepath.FromSlash(src.File))
+
+	if src.Pinned() && !update {
+		content, err := os.ReadFile(target)
+		if err == nil && offline.Checksum(content) == src.SHA256 {
+			fmt.Printf("%s: up to date at %s\n", src.Name, src.Revision)
+			return nil
+		}
+	} else {
+		revision, err := branchHead(ctx, src.Repository, src.Branch)
+		if err != nil {
+			return fmt.Errorf("error resolving the head of %s@%s: %w", src.Repository, src.Branch, err)
+		}
+		src.Revision = revision
+		src.SHA256 = ""
+	}
+
+	content, err := download(ctx, src.RemoteURL())
+	if err != nil {
+		return fmt.Errorf("error downloading %s: %w", src.Name, err)
+	}
+	sum := offline.Checksum(content)
+	if src.SHA256 != "" && sum != src.SHA256 {
+		return fmt.Errorf("%s at %s does not match its pinned checksum: got %s, expected %s", src.Name, src.Revision, sum, src.SHA256)
+	}
+	src.SHA256 = sum
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return fmt.Errorf("error creating directory for %s: %w", src.Name, err)
+	}
+	if err := os.WriteFile(target, content, 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", target, err)
+	}
+	fmt.Printf("%s: downloaded at %s\n", src.Name, src.Revision)
+	return nil
+}
+
+// branchHead returns the commit at the head of branch of the GitHub repository repo.
+func branchHead(ctx context.Context, repo, branch string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://api.github.com/repos/%s/commits/%s", repo, branch), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github.sha")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	body, err := do(req)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return do(req)
+}
+
+func do(req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %s failed with status %d: %s", req.URL, resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// writeManifest writes m to manifestPath, keeping the comment at the top of the original manifest.
+func writeManifest(original []byte, m offline.Manifest) error {
+	var buf bytes.Buffer
+	for line := range strings.Lines(string(original)) {
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		buf.WriteString(line)
+	}
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return fmt.Errorf("error encoding manifest: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("error encoding manifest: %w", err)
+	}
+
+	return os.WriteFile(manifestPath, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,590 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/command"
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/db"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker/config"
+)
+
+// backupFormatVersion is the version of the layout of backup archives, increased on incompatible changes.
+const backupFormatVersion = 1
+
+// Entries of a backup archive, in the order they are written. The database dump is last, so that it can be
+// streamed into the database once the other entries are read.
+const (
+	backupManifestEntry      = "backup.json"
+	backupConfigEntry        = "config.yaml"
+	backupIngestedFilesEntry = "ingested-files.json"
+	backupImagesEntry        = "images.json"
+	backupDumpEntry          = "metadata.sql"
+)
+
+// metadataDatabaseService is the name of the compose service of the metadata database.
+const metadataDatabaseService = "metadata-database"
+
+const (
+	// restoreSuffix names the scratch database a backup is loaded into before it replaces the current one.
+	restoreSuffix = "_restore"
+	// previousSuffix names the current database while it is being replaced by a restored one.
+	previousSuffix = "_previous"
+)
+
+// BackupManifest describes the content of a backup archive.
+type BackupManifest struct {
+	// Version of the layout of the archive
+	Version int `json:"version"`
+	// Environment is the name of the environment the backup was taken from
+	Environment string `json:"environment"`
+	// CreatedAt is when the backup was taken
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// backupIngestedFile is the record of an ingested file stored in a backup archive.
+type backupIngestedFile struct {
+	Path        string `json:"path"`
+	ContentHash string `json:"contentHash,omitempty"`
+	SizeBytes   int64  `json:"sizeBytes,omitempty"`
+}
+
+// backupImage is an image of the environment stored in a backup archive.
+type backupImage struct {
+	// Name of the service of the image
+	Name string `json:"name"`
+	// Ref is the image reference in the config of the environment
+	Ref string `json:"ref"`
+	// Digest is the repository digest (repo@sha256:...) of the image the environment ran, empty when the
+	// image has none, for example when it was built locally
+	Digest string `json:"digest,omitempty"`
+}
+
+// backupContents are the entries of a backup archive read in memory, everything but the database dump.
+type backupContents struct {
+	manifest      BackupManifest
+	config        []byte
+	ingestedFiles []backupIngestedFile
+	images        []backupImage
+}
+
+// BackupOpts defines inputs for Backup.
+type BackupOpts struct {
+	// Required. name of the environment
+	Name string
+	// Required. path of the backup archive to write
+	Output string
+}
+
+// Backup writes a backup of a Docker environment to a gzipped tar archive: a dump of its metadata database,
+// taken with pg_dump inside the database container, together with its stored config and the records of its
+// ingested files, and the references and digests of its images. The environment must be running. The archive
+// is self-contained: ImportEnv recreates the environment from it on another machine. See Restore.
+func Backup(opts BackupOpts) error {
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid backup parameters: %w", err)
+	}
+
+	display.Step("Backing up environment %s to %s", opts.Name, opts.Output)
+
+	env, err := GetEnv(opts.Name)
+	if err != nil {
+		return fmt.Errorf("error getting docker environment '%s': %w", opts.Name, err)
+	}
+
+	cfg, err := env.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to marshal environment config: %w", err)
+	}
+
+	rows, err := db.GetIngestedFilesByEnvironment(opts.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get the ingested files of environment '%s': %w", opts.Name, err)
+	}
+
+	ingestedFiles := make([]backupIngestedFile, 0, len(rows))
+	for _, row := range rows {
+		file := backupIngestedFile{Path: row.FilePath}
+		if row.ContentHash != nil {
+			file.ContentHash = *row.ContentHash
+		}
+		if row.SizeBytes != nil {
+			file.SizeBytes = *row.SizeBytes
+		}
+		ingestedFiles = append(ingestedFiles, file)
+	}
+
+	images := backupImages(env.ActiveImages())
+
+	dump, err := os.CreateTemp("", "epos-backup-*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary dump file: %w", err)
+	}
+	defer func() {
+		_ = dump.Close()
+		_ = os.Remove(dump.Name())
+	}()
+
+	display.Debug("dumping metadata database to: %s", dump.Name())
+
+	database := env.Components.MetadataDatabase
+	err = execDatabase(&env.EnvConfig, nil, dump, "pg_dump", "-U", database.User, "-d", database.DBName, "--no-owner", "--no-privileges")
+	if err != nil {
+		return fmt.Errorf("failed to dump the metadata database, is the environment running? %w", err)
+	}
+
+	if _, err := dump.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read temporary dump file: %w", err)
+	}
+
+	contents := backupContents{
+		manifest: BackupManifest{
+			Version:     backupFormatVersion,
+			Environment: opts.Name,
+			CreatedAt:   time.Now().UTC(),
+		},
+		config:        cfg,
+		ingestedFiles: ingestedFiles,
+		images:        images,
+	}
+	if err := writeBackup(opts.Output, contents, dump); err != nil {
+		_ = os.Remove(opts.Output)
+		return err
+	}
+
+	display.Done("Backed up environment %s to %s", opts.Name, opts.Output)
+
+	return nil
+}
+
+// Validate checks BackupOpts and ensures the environment exists.
+func (b *BackupOpts) Validate() error {
+	display.Debug("name: %s", b.Name)
+	display.Debug("output: %s", b.Output)
+
+	if b.Output == "" {
+		return fmt.Errorf("a path for the backup archive is required")
+	}
+
+	if err := EnsureEnvironmentExists(b.Name); err != nil {
+		return fmt.Errorf("no environment with the name '%s' exists: %w", b.Name, err)
+	}
+
+	return nil
+}
+
+// RestoreOpts defines inputs for Restore.
+type RestoreOpts struct {
+	// Required. name of the environment to restore the backup into. It can differ from the environment the backup was taken from
+	Name string
+	// Required. path of a backup archive written by Backup
+	Input string
+	// Optional. update the environment with the config stored in the backup before restoring the database
+	RestoreConfig bool
+}
+
+// Restore loads a backup written by Backup into an existing Docker environment: the metadata database is
+// replaced with the dump of the backup and the records of the ingested files with the ones of the backup.
+// The dump is loaded into a separate database that only replaces the current one once fully loaded, so a
+// failed restore leaves the current data in place. The services using the database are stopped while it is
+// restored, and the environment is started again once done. With RestoreConfig set the environment is first updated with the config stored in the backup.
+func Restore(opts RestoreOpts) (*Env, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid restore parameters: %w", err)
+	}
+
+	contents, err := readBackup(opts.Input, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	display.Step("Restoring backup of environment %s taken at %s into environment %s", contents.manifest.Environment, contents.manifest.CreatedAt.Local().Format(time.DateTime), opts.Name)
+
+	if opts.RestoreConfig {
+		cfg, err := config.LoadConfigFromBytes(contents.config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config in backup: %w", err)
+		}
+		cfg.Name = opts.Name
+
+		if _, err := Update(UpdateOpts{OldEnvName: opts.Name, NewConfig: cfg}); err != nil {
+			return nil, fmt.Errorf("failed to update the environment with the config of the backup: %w", err)
+		}
+	}
+
+	env, err := GetEnv(opts.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting docker environment '%s': %w", opts.Name, err)
+	}
+
+	handleFailure := func(msg string, mainErr error) (*Env, error) {
+		display.Error("Unexpected error: %v", mainErr)
+		display.Warn("Restore failed, trying to start the services again")
+
+		if err := startStack(&env.EnvConfig); err != nil {
+			return nil, fmt.Errorf("unable to recover from error: %w", err)
+		}
+
+		return nil, fmt.Errorf(msg, mainErr)
+	}
+
+	display.Step("Stopping the services using the metadata database")
+
+	if err := isolateDatabase(&env.EnvConfig); err != nil {
+		return handleFailure("failed to stop the services using the metadata database: %w", err)
+	}
+
+	display.Step("Restoring metadata database")
+
+	// The dump is loaded into a scratch database first, so that a broken archive or a failed load leaves the
+	// current data untouched. The scratch database replaces the current one only once it is fully loaded.
+	database := env.Components.MetadataDatabase
+	scratch := database.DBName + restoreSuffix
+	if err := psqlCommands(&env.EnvConfig,
+		fmt.Sprintf(`DROP DATABASE IF EXISTS "%s" WITH (FORCE)`, scratch),
+		fmt.Sprintf(`CREATE DATABASE "%s"`, scratch),
+	); err != nil {
+		return handleFailure("failed to create the database to restore into: %w", err)
+	}
+
+	_, err = readBackup(opts.Input, func(dump io.Reader) error {
+		return execDatabase(&env.EnvConfig, dump, io.Discard, "psql", "-U", database.User, "-d", scratch, "-v", "ON_ERROR_STOP=1", "-q")
+	})
+	if err != nil {
+		if dropErr := psqlCommands(&env.EnvConfig, fmt.Sprintf(`DROP DATABASE IF EXISTS "%s" WITH (FORCE)`, scratch)); dropErr != nil {
+			display.Warn("Could not remove the partially restored database %s: %v", scratch, dropErr)
+		}
+		return handleFailure("failed to load the database dump, the current database is unchanged: %w", err)
+	}
+
+	if err := swapDatabase(&env.EnvConfig, scratch, database.DBName); err != nil {
+		return handleFailure("failed to replace the metadata database with the restored one: %w", err)
+	}
+
+	display.Done("Metadata database restored")
+
+	if err := db.DeleteIngestedFilesByEnvironment(opts.Name); err != nil {
+		return handleFailure("failed to clear ingested files tracking: %w", err)
+	}
+	for _, file := range contents.ingestedFiles {
+		if err := db.InsertIngestedFile(opts.Name, file.Path, file.ContentHash, file.SizeBytes); err != nil {
+			return handleFailure("failed to restore ingested files tracking: %w", err)
+		}
+	}
+
+	display.Debug("restored ingested file records: %d", len(contents.ingestedFiles))
+
+	display.Step("Starting services")
+
+	if err := startStack(&env.EnvConfig); err != nil {
+		return nil, fmt.Errorf("failed to start the environment: %w", err)
+	}
+
+	display.Done("Restored environment: %s", opts.Name)
+
+	return env, nil
+}
+
+// Validate checks RestoreOpts and ensures the environment and the backup archive exist.
+func (r *RestoreOpts) Validate() error {
+	display.Debug("name: %s", r.Name)
+	display.Debug("input: %s", r.Input)
+	display.Debug("restoreConfig: %v", r.RestoreConfig)
+
+	if r.Input == "" {
+		return fmt.Errorf("a backup archive is required")
+	}
+
+	if _, err := os.Stat(r.Input); err != nil {
+		return fmt.Errorf("error stating backup archive %q: %w", r.Input, err)
+	}
+
+	if err := EnsureEnvironmentExists(r.Name); err != nil {
+		return fmt.Errorf("no environment with the name '%s' exists: %w", r.Name, err)
+	}
+
+	return nil
+}
+
+// backupImages returns the images to store in a backup, with the digests of the local images.
+func backupImages(images []common.NamedImage) []backupImage {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result := make([]backupImage, 0, len(images))
+	for _, image := range images {
+		digest, err := common.LocalImageDigest(ctx, image.Ref)
+		if err != nil {
+			display.Debug("no digest recorded for image %s (%s): %v", image.Name, image.Ref, err)
+		}
+		result = append(result, backupImage{Name: image.Name, Ref: image.Ref, Digest: digest})
+	}
+	return result
+}
+
+// backupBefore backs up the environment called name to a new archive in dir before an operation destroys its
+// metadata database. Nothing is backed up when dir is empty.
+func backupBefore(name, dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create backup directory %s: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.tar.gz", name, time.Now().Format("20060102-150405")))
+	if err := Backup(BackupOpts{Name: name, Output: path}); err != nil {
+		return fmt.Errorf("failed to back up environment '%s' before destroying its data: %w", name, err)
+	}
+
+	return nil
+}
+
+// swapDatabase replaces the database called target with the database called scratch. The current database is
+// renamed aside first and only dropped once scratch took its name, so it is put back if the rename fails.
+func swapDatabase(cfg *config.EnvConfig, scratch, target string) error {
+	previous := target + previousSuffix
+
+	if err := psqlCommands(cfg,
+		fmt.Sprintf(`DROP DATABASE IF EXISTS "%s" WITH (FORCE)`, previous),
+		fmt.Sprintf(`SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = '%s' AND pid <> pg_backend_pid()`, target),
+		fmt.Sprintf(`ALTER DATABASE "%s" RENAME TO "%s"`, target, previous),
+	); err != nil {
+		return fmt.Errorf("failed to move the current database aside: %w", err)
+	}
+
+	if err := psqlCommands(cfg, fmt.Sprintf(`ALTER DATABASE "%s" RENAME TO "%s"`, scratch, target)); err != nil {
+		if undoErr := psqlCommands(cfg, fmt.Sprintf(`ALTER DATABASE "%s" RENAME TO "%s"`, previous, target)); undoErr != nil {
+			return fmt.Errorf("failed to rename the restored database: %w (the previous data is kept in database %s: %v)", err, previous, undoErr)
+		}
+		return fmt.Errorf("failed to rename the restored database, the current database is unchanged: %w", err)
+	}
+
+	if err := psqlCommands(cfg, fmt.Sprintf(`DROP DATABASE IF EXISTS "%s" WITH (FORCE)`, previous)); err != nil {
+		display.Warn("Could not remove the previous metadata database %s: %v", previous, err)
+	}
+
+	return nil
+}
+
+// psqlCommands runs each statement in its own transaction against the maintenance database of the metadata
+// database container, stopping at the first failing one.
+func psqlCommands(cfg *config.EnvConfig, statements ...string) error {
+	args := []string{"psql", "-U", cfg.Components.MetadataDatabase.User, "-d", "postgres", "-v", "ON_ERROR_STOP=1", "-q"}
+	for _, statement := range statements {
+		args = append(args, "-c", statement)
+	}
+	return execDatabase(cfg, nil, io.Discard, args...)
+}
+
+// isolateDatabase stops every service of the environment but the metadata database, and starts the
+// metadata database if it is not running, waiting for it to be healthy.
+func isolateDatabase(cfg *config.EnvConfig) error {
+	return withComposeBundle(cfg, func(bundle *composeBundle) error {
+		services, err := composeServices(bundle, cfg.Name)
+		if err != nil {
+			return err
+		}
+
+		args := []string{"stop"}
+		for _, service := range services {
+			if service != metadataDatabaseService {
+				args = append(args, service)
+			}
+		}
+
+		if len(args) > 1 {
+			if _, err := command.RunCommand(composeCommand(bundle, cfg.Name, args...), false); err != nil {
+				return fmt.Errorf("docker compose stop failed: %w", err)
+			}
+		}
+
+		if _, err := command.RunCommand(composeCommand(bundle, cfg.Name, "up", "-d", "--wait", "--pull", "never", metadataDatabaseService), false); err != nil {
+			return fmt.Errorf("docker compose up of the metadata database failed: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// execDatabase runs args inside the metadata database container of the environment, with stdin as its input
+// when not nil and its output written to stdout.
+func execDatabase(cfg *config.EnvConfig, stdin io.Reader, stdout io.Writer, args ...string) error {
+	container := fmt.Sprintf("%s-%s", cfg.Name, metadataDatabaseService)
+
+	execArgs := []string{"exec", "-e", "PGPASSWORD=" + cfg.Components.MetadataDatabase.Password}
+	if stdin != nil {
+		execArgs = append(execArgs, "-i")
+	}
+	execArgs = append(execArgs, container)
+
+	display.Debug("running in %s: %s", container, strings.Join(args, " "))
+
+	cmd := exec.Command("docker", append(execArgs, args...)...)
+	cmd.Stdin = stdin
+	if stdin == nil {
+		cmd.Stdin = bytes.NewReader(nil)
+	}
+	cmd.Stdout = stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := command.StartCommand(cmd); err != nil {
+		return fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// writeBackup writes a backup archive with contents and the database dump read from dump to path.
+func writeBackup(path string, contents backupContents, dump *os.File) error {
+	manifest, err := json.MarshalIndent(contents.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal backup manifest: %w", err)
+	}
+
+	ingestedFiles, err := json.MarshalIndent(contents.ingestedFiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ingested files: %w", err)
+	}
+
+	images, err := json.MarshalIndent(contents.images, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal images: %w", err)
+	}
+
+	info, err := dump.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat temporary dump file: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create backup archive %s: %w", path, err)
+	}
+	defer f.Close()
+
+	zw := gzip.NewWriter(f)
+	tw := tar.NewWriter(zw)
+
+	entries := []struct {
+		name string
+		size int64
+		r    io.Reader
+	}{
+		{backupManifestEntry, int64(len(manifest)), bytes.NewReader(manifest)},
+		{backupConfigEntry, int64(len(contents.config)), bytes.NewReader(contents.config)},
+		{backupIngestedFilesEntry, int64(len(ingestedFiles)), bytes.NewReader(ingestedFiles)},
+		{backupImagesEntry, int64(len(images)), bytes.NewReader(images)},
+		{backupDumpEntry, info.Size(), dump},
+	}
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:    entry.name,
+			Mode:    0o600,
+			Size:    entry.size,
+			ModTime: contents.manifest.CreatedAt,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s to backup archive: %w", entry.name, err)
+		}
+		if _, err := io.Copy(tw, entry.r); err != nil {
+			return fmt.Errorf("failed to write %s to backup archive: %w", entry.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write backup archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write backup archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write backup archive: %w", err)
+	}
+
+	return nil
+}
+
+// readBackup reads the backup archive at path and checks its version. When restoreDump is not nil it is
+// called with a reader of the database dump; otherwise the dump is skipped.
+func readBackup(path string, restoreDump func(dump io.Reader) error) (backupContents, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return backupContents{}, fmt.Errorf("failed to open backup archive %s: %w", path, err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return backupContents{}, fmt.Errorf("backup archive %s is not a gzipped tar archive: %w", path, err)
+	}
+	defer zr.Close()
+
+	var contents backupContents
+	found := map[string]bool{}
+	tr := tar.NewReader(zr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return backupContents{}, fmt.Errorf("failed to read backup archive %s: %w", path, err)
+		}
+
+		found[header.Name] = true
+		switch header.Name {
+		case backupManifestEntry:
+			if err := json.NewDecoder(tr).Decode(&contents.manifest); err != nil {
+				return backupContents{}, fmt.Errorf("invalid backup manifest: %w", err)
+			}
+			if contents.manifest.Version != backupFormatVersion {
+				return backupContents{}, fmt.Errorf("unsupported backup version %d, expected %d", contents.manifest.Version, backupFormatVersion)
+			}
+		case backupConfigEntry:
+			if contents.config, err = io.ReadAll(tr); err != nil {
+				return backupContents{}, fmt.Errorf("failed to read config from backup archive: %w", err)
+			}
+		case backupIngestedFilesEntry:
+			if err := json.NewDecoder(tr).Decode(&contents.ingestedFiles); err != nil {
+				return backupContents{}, fmt.Errorf("invalid ingested files in backup archive: %w", err)
+			}
+		case backupImagesEntry:
+			if err := json.NewDecoder(tr).Decode(&contents.images); err != nil {
+				return backupContents{}, fmt.Errorf("invalid images in backup archive: %w", err)
+			}
+		case backupDumpEntry:
+			if restoreDump != nil {
+				if err := restoreDump(tr); err != nil {
+					return backupContents{}, err
+				}
+			}
+		}
+	}
+
+	for _, entry := range []string{backupManifestEntry, backupConfigEntry, backupIngestedFilesEntry, backupDumpEntry} {
+		if !found[entry] {
+			return backupContents{}, fmt.Errorf("backup archive %s has no %s", path, entry)
+		}
+	}
+
+	return contents, nil
+}
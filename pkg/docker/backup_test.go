@@ -0,0 +1,151 @@
+package docker
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriteReadBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	dump, err := os.Create(filepath.Join(tmpDir, "dump.sql"))
+	if err != nil {
+		t.Fatalf("os.Create() error = %v", err)
+	}
+	defer dump.Close()
+	if _, err := dump.WriteString("CREATE TABLE t (id int);\n"); err != nil {
+		t.Fatalf("WriteString() error = %v", err)
+	}
+	if _, err := dump.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Seek() error = %v", err)
+	}
+
+	want := backupContents{
+		manifest: BackupManifest{
+			Version:     backupFormatVersion,
+			Environment: "env",
+			CreatedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		config: []byte("name: env\n"),
+		ingestedFiles: []backupIngestedFile{
+			{Path: "/data/a.ttl", ContentHash: "abc", SizeBytes: 3},
+			{Path: "https://example.org/b.ttl"},
+		},
+		images: []backupImage{
+			{Name: "Gateway", Ref: "ghcr.io/epos-eric/epos-api-gateway:latest", Digest: "ghcr.io/epos-eric/epos-api-gateway@sha256:abc"},
+			{Name: "Rabbitmq", Ref: "rabbitmq:local"},
+		},
+	}
+
+	path := filepath.Join(tmpDir, "backup.tar.gz")
+	if err := writeBackup(path, want, dump); err != nil {
+		t.Fatalf("writeBackup() error = %v", err)
+	}
+
+	got, err := readBackup(path, nil)
+	if err != nil {
+		t.Fatalf("readBackup() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("readBackup() = %+v, want %+v", got, want)
+	}
+
+	var restored []byte
+	_, err = readBackup(path, func(r io.Reader) error {
+		restored, err = io.ReadAll(r)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("readBackup() error = %v", err)
+	}
+	if string(restored) != "CREATE TABLE t (id int);\n" {
+		t.Fatalf("readBackup() dump = %q, want the written dump", restored)
+	}
+}
+
+func TestReadBackup_Invalid(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeArchive := func(name string, entries map[string]string) string {
+		path := filepath.Join(tmpDir, name)
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("os.Create() error = %v", err)
+		}
+		defer f.Close()
+
+		zw := gzip.NewWriter(f)
+		tw := tar.NewWriter(zw)
+		for entry, content := range entries {
+			if err := tw.WriteHeader(&tar.Header{Name: entry, Mode: 0o600, Size: int64(len(content))}); err != nil {
+				t.Fatalf("WriteHeader() error = %v", err)
+			}
+			if _, err := tw.Write([]byte(content)); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatalf("tar Close() error = %v", err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("gzip Close() error = %v", err)
+		}
+		return path
+	}
+
+	notArchive := filepath.Join(tmpDir, "plain.sql")
+	if err := os.WriteFile(notArchive, []byte("SELECT 1;"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "not an archive", path: notArchive},
+		{name: "missing file", path: filepath.Join(tmpDir, "missing.tar.gz")},
+		{
+			name: "unsupported version",
+			path: writeArchive("version.tar.gz", map[string]string{
+				backupManifestEntry:      `{"version": 99}`,
+				backupConfigEntry:        "name: env\n",
+				backupIngestedFilesEntry: "[]",
+				backupDumpEntry:          "",
+			}),
+		},
+		{
+			name: "missing dump",
+			path: writeArchive("nodump.tar.gz", map[string]string{
+				backupManifestEntry:      `{"version": 1}`,
+				backupConfigEntry:        "name: env\n",
+				backupIngestedFilesEntry: "[]",
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readBackup(tt.path, nil); err == nil {
+				t.Fatal("readBackup() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestBackupRestoreOpts_Validate(t *testing.T) {
+	if err := (&BackupOpts{Name: "does_not_exist"}).Validate(); err == nil {
+		t.Fatal("BackupOpts.Validate() error = nil, want error for a missing output")
+	}
+	if err := (&BackupOpts{Name: "does_not_exist", Output: "backup.tar.gz"}).Validate(); err == nil {
+		t.Fatal("BackupOpts.Validate() error = nil, want error for a non-existent environment")
+	}
+	if err := (&RestoreOpts{Name: "does_not_exist", Input: filepath.Join(t.TempDir(), "missing.tar.gz")}).Validate(); err == nil {
+		t.Fatal("RestoreOpts.Validate() error = nil, want error for a missing backup archive")
+	}
+}
@@ -1,8 +1,10 @@
 package docker
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
+	"time"
 
 	"github.com/EPOS-ERIC/epos-opensource/command"
 	"github.com/EPOS-ERIC/epos-opensource/common"
@@ -14,6 +16,12 @@ import (
 type CleanOpts struct {
 	// Required. name of the environment
 	Name string
+	// Optional. make the ingestor fetch the base ontologies from GitHub instead of uploading the embedded copies
+	RemoteOntologies bool
+	// Optional. directory the environment is backed up to before its database is removed, see Backup
+	BackupDir string
+	// Optional. how long to wait for the services to become ready before initializing the ontologies. Defaults to DefaultReadyTimeout
+	ReadyTimeout time.Duration
 }
 
 // Clean removes runtime data from an existing Docker environment and restarts required services.
@@ -34,6 +42,10 @@ func Clean(opts CleanOpts) (*Env, error) {
 		return nil, fmt.Errorf("failed to build environment URLs: %w", err)
 	}
 
+	if err := backupBefore(opts.Name, opts.BackupDir); err != nil {
+		return nil, err
+	}
+
 	metadataContainer := fmt.Sprintf("%s-metadata-database", opts.Name)
 	volumeName := fmt.Sprintf("%s_psqldata", opts.Name)
 
@@ -85,7 +97,11 @@ func Clean(opts CleanOpts) (*Env, error) {
 
 	display.Done("Services restarted successfully")
 
-	if err := common.PopulateOntologies(urls.APIURL); err != nil {
+	if err := waitReady(context.Background(), &env.EnvConfig, opts.ReadyTimeout); err != nil {
+		return handleFailure("environment not ready: %w", err)
+	}
+
+	if err := common.PopulateOntologies(urls.APIURL, env.Ontologies, opts.RemoteOntologies); err != nil {
 		return handleFailure("failed to populate base ontologies in environment: %w", err)
 	}
 
@@ -98,6 +114,13 @@ func Clean(opts CleanOpts) (*Env, error) {
 // Validate checks CleanOpts and ensures the target environment exists.
 func (c *CleanOpts) Validate() error {
 	display.Debug("name: %s", c.Name)
+	display.Debug("remoteOntologies: %v", c.RemoteOntologies)
+	display.Debug("backupDir: %s", c.BackupDir)
+	display.Debug("readyTimeout: %v", c.ReadyTimeout)
+
+	if c.ReadyTimeout < 0 {
+		return fmt.Errorf("ready timeout must not be negative")
+	}
 
 	if err := EnsureEnvironmentExists(c.Name); err != nil {
 		return fmt.Errorf("no environment with the name '%s' exists: %w", c.Name, err)
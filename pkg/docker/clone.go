@@ -0,0 +1,221 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/EPOS-ERIC/epos-opensource/command"
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/db"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker/config"
+	"github.com/EPOS-ERIC/epos-opensource/validate"
+)
+
+// CloneOpts defines inputs for Clone.
+type CloneOpts struct {
+	// Required. name of the environment to clone
+	Source string
+	// Required. name of the new environment
+	Name string
+}
+
+// Clone creates a new Docker environment called opts.Name as a copy of an existing one, including its data:
+// the stored config is copied with the new name and free ports, the volume of the metadata database is
+// duplicated and the records of the ingested files are copied. The metadata database of the source
+// environment is stopped while its volume is copied, and started again afterwards.
+func Clone(opts CloneOpts) (*Env, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid clone parameters: %w", err)
+	}
+
+	display.Step("Cloning environment %s into %s", opts.Source, opts.Name)
+
+	source, err := GetEnv(opts.Source)
+	if err != nil {
+		return nil, fmt.Errorf("error getting docker environment '%s': %w", opts.Source, err)
+	}
+
+	cfg, err := cloneConfig(&source.EnvConfig, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	volume := fmt.Sprintf("%s_psqldata", opts.Name)
+
+	var stackDeployed bool
+	handleFailure := func(msg string, mainErr error) (*Env, error) {
+		display.Error("Clone failed: %v", mainErr)
+
+		if stackDeployed {
+			if err := downStack(cfg, true); err != nil {
+				display.Warn("docker compose down failed, there may be dangling resources: %v", err)
+			}
+		} else if _, err := command.RunCommand(exec.Command("docker", "volume", "rm", "-f", volume), true); err != nil {
+			display.Warn("failed to remove volume %s: %v", volume, err)
+		}
+
+		return nil, fmt.Errorf(msg, mainErr)
+	}
+
+	display.Step("Copying database volume")
+
+	if err := copyDatabaseVolume(source, opts.Name); err != nil {
+		return handleFailure("failed to copy the database volume: %w", err)
+	}
+
+	display.Done("Database volume copied")
+
+	if err := syncEnvImages(cfg, false); err != nil {
+		return handleFailure("preparing docker images failed: %w", err)
+	}
+
+	stackDeployed = true
+
+	if err := deployStack(true, cfg); err != nil {
+		return handleFailure("deploy failed: %w", err)
+	}
+
+	env, err := upsertEnvConfig(cfg)
+	if err != nil {
+		return handleFailure("failed to persist environment config: %w", err)
+	}
+
+	files, err := db.GetIngestedFilesByEnvironment(opts.Source)
+	if err != nil {
+		return nil, fmt.Errorf("environment cloned, but getting the ingested files of '%s' failed: %w", opts.Source, err)
+	}
+	for _, file := range files {
+		var contentHash string
+		var sizeBytes int64
+		if file.ContentHash != nil {
+			contentHash = *file.ContentHash
+		}
+		if file.SizeBytes != nil {
+			sizeBytes = *file.SizeBytes
+		}
+
+		if err := db.InsertIngestedFile(opts.Name, file.FilePath, contentHash, sizeBytes); err != nil {
+			return nil, fmt.Errorf("environment cloned, but copying its ingested files tracking failed: %w", err)
+		}
+	}
+
+	display.Debug("copied ingested file records: %d", len(files))
+	display.Done("Cloned environment %s into %s", opts.Source, opts.Name)
+
+	return env, nil
+}
+
+// Validate checks CloneOpts, ensuring the source environment exists and the new one does not.
+func (c *CloneOpts) Validate() error {
+	display.Debug("source: %s", c.Source)
+	display.Debug("name: %s", c.Name)
+
+	if err := validate.Name(c.Name); err != nil {
+		return fmt.Errorf("'%s' is an invalid name for an environment: %w", c.Name, err)
+	}
+
+	if err := EnsureEnvironmentExists(c.Source); err != nil {
+		return fmt.Errorf("no environment with the name '%s' exists: %w", c.Source, err)
+	}
+
+	if err := EnsureEnvironmentDoesNotExist(c.Name); err != nil {
+		return fmt.Errorf("an environment with the name '%s' already exists: %w", c.Name, err)
+	}
+
+	return nil
+}
+
+// cloneConfig returns a copy of cfg for a new environment called name. The published ports are reset to the
+// defaults and replaced with free ones when in use, so that the clone can run next to its source.
+func cloneConfig(cfg *config.EnvConfig, name string) (*config.EnvConfig, error) {
+	content, err := cfg.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal environment config: %w", err)
+	}
+
+	clone, err := config.LoadConfigFromBytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy environment config: %w", err)
+	}
+
+	clone.Name = name
+
+	defaults := config.GetDefaultConfig()
+	clone.Components.PlatformGUI.Port = defaults.Components.PlatformGUI.Port
+	clone.Components.Gateway.Port = defaults.Components.Gateway.Port
+	clone.Components.Backoffice.GUI.Port = defaults.Components.Backoffice.GUI.Port
+
+	if err := clone.EnsurePortsFree(); err != nil {
+		return nil, fmt.Errorf("failed to ensure ports are free: %w", err)
+	}
+
+	// the ports EnsurePortsFree does not handle always get a new free port
+	if clone.Components.MetadataDatabase.PublishedPort > 0 {
+		if clone.Components.MetadataDatabase.PublishedPort, err = common.FindFreePort(); err != nil {
+			return nil, fmt.Errorf("error finding free port for metadata database: %w", err)
+		}
+	}
+	if clone.Components.AAIService.Enabled {
+		if clone.Components.AAIService.Port, err = common.FindFreePort(); err != nil {
+			return nil, fmt.Errorf("error finding free port for aai service: %w", err)
+		}
+	}
+
+	if err := clone.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config for clone: %w", err)
+	}
+
+	return clone, nil
+}
+
+// copyDatabaseVolume copies the content of the database volume of source to a new database volume for the
+// environment called name, labelled as a volume of its compose project. The metadata database of source is
+// stopped during the copy when it is running, so that the copy is consistent.
+func copyDatabaseVolume(source *Env, name string) error {
+	sourceVolume := fmt.Sprintf("%s_psqldata", source.Name)
+	volume := fmt.Sprintf("%s_psqldata", name)
+	container := fmt.Sprintf("%s-%s", source.Name, metadataDatabaseService)
+
+	output, err := command.RunCommand(exec.Command("docker", "inspect", "-f", "{{.State.Running}}", container), true)
+	running := err == nil && strings.TrimSpace(output) == "true"
+	if running {
+		display.Info("Stopping the metadata database of %s while its volume is copied", source.Name)
+
+		if _, err := command.RunCommand(exec.Command("docker", "stop", container), true); err != nil {
+			return fmt.Errorf("failed to stop metadata container %s: %w", container, err)
+		}
+
+		defer func() {
+			if _, err := command.RunCommand(exec.Command("docker", "start", container), true); err != nil {
+				display.Warn("Failed to start the metadata database of %s again: %v", source.Name, err)
+			}
+		}()
+	}
+
+	_, err = command.RunCommand(exec.Command("docker", "volume", "create",
+		"--label", composeProjectLabel+"="+name,
+		"--label", "com.docker.compose.volume=psqldata",
+		volume,
+	), true)
+	if err != nil {
+		return fmt.Errorf("failed to create volume %s: %w", volume, err)
+	}
+
+	display.Debug("copying volume %s to %s", sourceVolume, volume)
+
+	// the database image is available locally and has cp, so no other image is pulled for the copy
+	_, err = command.RunCommand(exec.Command("docker", "run", "--rm",
+		"--entrypoint", "cp",
+		"-v", sourceVolume+":/from:ro",
+		"-v", volume+":/to",
+		source.Images.MetadataDatabaseImage,
+		"-a", "/from/.", "/to/",
+	), true)
+	if err != nil {
+		return fmt.Errorf("failed to copy volume %s to %s: %w", sourceVolume, volume, err)
+	}
+
+	return nil
+}
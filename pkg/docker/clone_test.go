@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker/config"
+)
+
+func TestCloneConfig(t *testing.T) {
+	source := config.GetDefaultConfig()
+	source.Name = "source"
+	source.Components.MetadataDatabase.PublishedPort = 5432
+
+	clone, err := cloneConfig(source, "clone")
+	if err != nil {
+		t.Fatalf("cloneConfig() error = %v", err)
+	}
+
+	if clone.Name != "clone" {
+		t.Fatalf("cloneConfig() name = %q, want %q", clone.Name, "clone")
+	}
+	if source.Name != "source" {
+		t.Fatalf("cloneConfig() changed the source config name to %q", source.Name)
+	}
+	if clone.Components.MetadataDatabase.PublishedPort == 5432 {
+		t.Fatal("cloneConfig() kept the published port of the metadata database of the source")
+	}
+	if clone.Components.MetadataDatabase.DBName != source.Components.MetadataDatabase.DBName {
+		t.Fatalf("cloneConfig() db name = %q, want %q", clone.Components.MetadataDatabase.DBName, source.Components.MetadataDatabase.DBName)
+	}
+}
+
+func TestCloneOpts_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts CloneOpts
+	}{
+		{name: "invalid name", opts: CloneOpts{Source: "does_not_exist", Name: "Invalid Name"}},
+		{name: "non-existent source", opts: CloneOpts{Source: "does_not_exist", Name: "clone"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); err == nil {
+				t.Fatal("Validate() error = nil, want error")
+			}
+		})
+	}
+}
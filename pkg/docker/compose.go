@@ -183,3 +183,37 @@ func downStack(cfg *config.EnvConfig, removeVolumes bool) error {
 
 	return nil
 }
+
+// startStack starts the containers of the stack for the given environment configuration, creating again any
+// container that was removed. Volumes are kept.
+func startStack(cfg *config.EnvConfig) error {
+	err := withComposeBundle(cfg, func(bundle *composeBundle) error {
+		if _, err := command.RunCommand(composeCommand(bundle, cfg.Name, "up", "-d", "--pull", "never"), false); err != nil {
+			return fmt.Errorf("docker compose up failed: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// stopStack stops the containers of the stack for the given environment configuration, without removing them
+// or their volumes.
+func stopStack(cfg *config.EnvConfig) error {
+	err := withComposeBundle(cfg, func(bundle *composeBundle) error {
+		if _, err := command.RunCommand(composeCommand(bundle, cfg.Name, "stop"), false); err != nil {
+			return fmt.Errorf("docker compose stop failed: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
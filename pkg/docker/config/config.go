@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -33,7 +34,8 @@ func GetDefaultConfigBytes() []byte {
 	return defaultConfig
 }
 
-// LoadConfig loads a Docker configuration from a YAML file.
+// LoadConfig loads a Docker configuration from a YAML file. A relative seed manifest path is resolved against
+// the directory of the file.
 func LoadConfig(path string) (*EnvConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -45,6 +47,10 @@ func LoadConfig(path string) (*EnvConfig, error) {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
 	}
 
+	if config.SeedManifest != "" && !filepath.IsAbs(config.SeedManifest) {
+		config.SeedManifest = filepath.Join(filepath.Dir(path), config.SeedManifest)
+	}
+
 	return config, nil
 }
 
@@ -428,6 +434,11 @@ func (e *EnvConfig) Validate() error {
 		}
 	}
 
+	// Ontologies validation
+	if err := common.ValidateOntologies(e.Ontologies); err != nil {
+		return err
+	}
+
 	// Monitoring validation
 	if e.Monitoring.Enabled {
 		if e.Monitoring.URL == "" {
@@ -1,9 +1,13 @@
 package config_test
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/docker/config"
 )
 
@@ -102,6 +106,47 @@ func TestEnvConfigValidate_DefaultConfigIsValidWithName(t *testing.T) {
 	}
 }
 
+func TestEnvConfigValidate_DefaultOntologies(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.Name = "default-env"
+
+	if !reflect.DeepEqual(cfg.Ontologies, common.DefaultOntologies()) {
+		t.Fatalf("GetDefaultConfig().Ontologies = %+v, want %+v", cfg.Ontologies, common.DefaultOntologies())
+	}
+
+	cfg.Ontologies = append(cfg.Ontologies, common.Ontology{Name: "MY-SHAPES", Type: common.OntologyTypeBase})
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("Validate() error = nil, want error for an ontology without url that is not embedded")
+	}
+}
+
+func TestLoadConfig_ResolvesSeedManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, tc := range []struct {
+		seed string
+		want string
+	}{
+		{seed: "", want: ""},
+		{seed: "seed/datasets.yaml", want: filepath.Join(dir, "seed", "datasets.yaml")},
+		{seed: "/srv/datasets.yaml", want: "/srv/datasets.yaml"},
+	} {
+		data := strings.Replace(string(config.GetDefaultConfigBytes()), `seed_manifest: ""`, "seed_manifest: "+tc.seed, 1)
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+
+		cfg, err := config.LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v, want nil", err)
+		}
+		if cfg.SeedManifest != tc.want {
+			t.Errorf("LoadConfig().SeedManifest = %q, want %q", cfg.SeedManifest, tc.want)
+		}
+	}
+}
+
 func TestEnvConfigValidate_MetadataDatabasePublishedPortIsOptional(t *testing.T) {
 	cfg := NewTestConfig(t, "test-env").Build()
 	cfg.Components.MetadataDatabase.PublishedPort = 35432
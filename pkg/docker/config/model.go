@@ -8,12 +8,14 @@ import (
 
 // EnvConfig represents the full Docker environment configuration schema.
 type EnvConfig struct {
-	Name       string        `yaml:"name"`
-	Domain     string        `yaml:"domain"`
-	Protocol   string        `yaml:"protocol"`
-	Components Components    `yaml:"components"`
-	Monitoring Monitoring    `yaml:"monitoring"`
-	Images     common.Images `yaml:"images"`
+	Name         string            `yaml:"name"`
+	Domain       string            `yaml:"domain"`
+	Protocol     string            `yaml:"protocol"`
+	Components   Components        `yaml:"components"`
+	Ontologies   []common.Ontology `yaml:"ontologies"`
+	SeedManifest string            `yaml:"seed_manifest"`
+	Monitoring   Monitoring        `yaml:"monitoring"`
+	Images       common.Images     `yaml:"images"`
 }
 
 // PlatformGUI configures the platform GUI endpoint.
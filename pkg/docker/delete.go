@@ -11,7 +11,8 @@ import (
 
 // DeleteOpts defines inputs for Delete.
 type DeleteOpts struct {
-	Name []string // names of environments
+	Name      []string // names of environments
+	BackupDir string   // directory every environment is backed up to before it is deleted, see Backup
 }
 
 // Delete stops and removes one or more Docker environments and their tracked metadata.
@@ -32,6 +33,10 @@ func Delete(opts DeleteOpts) error {
 				return fmt.Errorf("error getting docker environment '%s': %w", envName, err)
 			}
 
+			if err := backupBefore(envName, opts.BackupDir); err != nil {
+				return err
+			}
+
 			display.Step("Stopping stack for environment: %s", envName)
 			display.Debug("running docker compose down for environment: %s", envName)
 
@@ -68,6 +73,7 @@ func Delete(opts DeleteOpts) error {
 // Validate checks DeleteOpts and ensures every requested environment exists.
 func (d *DeleteOpts) Validate() error {
 	display.Debug("names: %+v", d.Name)
+	display.Debug("backupDir: %s", d.BackupDir)
 
 	for _, env := range d.Name {
 		if err := EnsureEnvironmentExists(env); err != nil {
@@ -1,8 +1,10 @@
 package docker
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/EPOS-ERIC/epos-opensource/display"
@@ -14,12 +16,26 @@ import (
 type DeployOpts struct {
 	// Pull images before deploying
 	PullImages bool
+	// Make the ingestor fetch the base ontologies from GitHub instead of uploading the embedded copies
+	RemoteOntologies bool
+	// Do not populate the environment from the seed manifest of its config
+	SkipSeed bool
+	// Do not wait for the services and initialize the ontologies, for an environment whose database is replaced
+	// right after it is deployed, see ImportEnv. Implies SkipSeed
+	SkipOntologies bool
+	// Number of parallel uploads when populating the environment from its seed manifest (1-20). Defaults to 1
+	SeedParallel int
+	// How long to wait for the services to become ready before initializing the ontologies. Defaults to DefaultReadyTimeout
+	ReadyTimeout time.Duration
 	// Environment configuration (required)
 	Config *config.EnvConfig
 }
 
 // Deploy creates and starts a Docker-based EPOS environment and persists it in the local store.
-func Deploy(opts DeployOpts) (*Env, error) {
+// When the config has a seed manifest the environment is then populated from it, unless SkipSeed is set.
+// Cancelling ctx stops waiting for the services, and stops the seeding gracefully like Populate. If seeding
+// fails the deployed environment is kept and returned along with the error.
+func Deploy(ctx context.Context, opts DeployOpts) (*Env, error) {
 	if err := opts.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid deploy parameters: %w", err)
 	}
@@ -77,12 +93,19 @@ func Deploy(opts DeployOpts) (*Env, error) {
 
 	display.Debug("urls: %+v", urls)
 
-	if err := common.PopulateOntologies(urls.APIURL); err != nil {
-		display.Error("error initializing the ontologies in the environment: %v", err)
-		return handleFailure("error initializing the ontologies: %w", err)
-	}
+	if !opts.SkipOntologies {
+		if err := waitReady(ctx, opts.Config, opts.ReadyTimeout); err != nil {
+			display.Error("The environment did not become ready: %v", err)
+			return handleFailure("environment not ready: %w", err)
+		}
 
-	display.Debug("initialized base ontologies using: %s", urls.APIURL)
+		if err := common.PopulateOntologies(urls.APIURL, opts.Config.Ontologies, opts.RemoteOntologies); err != nil {
+			display.Error("error initializing the ontologies in the environment: %v", err)
+			return handleFailure("error initializing the ontologies: %w", err)
+		}
+
+		display.Debug("initialized base ontologies using: %s", urls.APIURL)
+	}
 
 	env, err := upsertEnvConfig(opts.Config)
 	if err != nil {
@@ -91,14 +114,43 @@ func Deploy(opts DeployOpts) (*Env, error) {
 
 	display.Done("Created environment: %s", opts.Config.Name)
 
+	if opts.Config.SeedManifest != "" && !opts.SkipSeed && !opts.SkipOntologies {
+		display.Step("Seeding environment %s from manifest %s", opts.Config.Name, opts.Config.SeedManifest)
+
+		_, err := Populate(ctx, PopulateOpts{
+			Name:     opts.Config.Name,
+			Parallel: opts.SeedParallel,
+			Manifest: opts.Config.SeedManifest,
+		})
+		if err != nil {
+			return env, fmt.Errorf("environment deployed, but seeding it from manifest '%s' failed: %w", opts.Config.SeedManifest, err)
+		}
+	}
+
 	return env, nil
 }
 
 // Validate checks DeployOpts and resolves any required preconditions before deployment.
 func (d *DeployOpts) Validate() error {
 	display.Debug("pullImages: %v", d.PullImages)
+	display.Debug("remoteOntologies: %v", d.RemoteOntologies)
+	display.Debug("skipSeed: %v", d.SkipSeed)
+	display.Debug("skipOntologies: %v", d.SkipOntologies)
+	display.Debug("seedParallel: %d", d.SeedParallel)
+	display.Debug("readyTimeout: %v", d.ReadyTimeout)
 	display.Debug("config: %+v", d.Config)
 
+	if d.SeedParallel == 0 {
+		d.SeedParallel = 1
+	}
+	if d.SeedParallel < 1 || d.SeedParallel > 20 {
+		return fmt.Errorf("seed parallel must be between 1 and 20")
+	}
+
+	if d.ReadyTimeout < 0 {
+		return fmt.Errorf("ready timeout must not be negative")
+	}
+
 	if d.Config == nil {
 		return fmt.Errorf("config is required")
 	}
@@ -111,6 +163,13 @@ func (d *DeployOpts) Validate() error {
 		return fmt.Errorf("invalid name for environment: %w", err)
 	}
 
+	// fail before deploying rather than after when the seed manifest is invalid
+	if d.Config.SeedManifest != "" && !d.SkipSeed && !d.SkipOntologies {
+		if _, err := common.LoadDatasetManifest(d.Config.SeedManifest); err != nil {
+			return fmt.Errorf("invalid seed manifest: %w", err)
+		}
+	}
+
 	if err := EnsureEnvironmentDoesNotExist(d.Config.Name); err != nil {
 		return fmt.Errorf("an environment with the name '%s' already exists: %w", d.Config.Name, err)
 	}
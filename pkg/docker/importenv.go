@@ -0,0 +1,148 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker/config"
+	"github.com/EPOS-ERIC/epos-opensource/validate"
+)
+
+// ImportEnvOpts defines inputs for ImportEnv.
+type ImportEnvOpts struct {
+	// Required. path of a bundle written by Backup, e.g. with the export-env command
+	Input string
+	// Optional. name of the new environment. Defaults to the name of the environment the bundle was exported from
+	Name string
+	// Optional. deploy the image tags of the config of the bundle instead of the image digests recorded in it
+	KeepTags bool
+}
+
+// ImportEnv recreates a Docker environment from a bundle written by Backup, for example on another machine:
+// a new environment is deployed with the config of the bundle, using the default ports or free ones when
+// they are in use, and the data of the bundle is then restored into it. The ontologies are not initialized
+// when the environment is deployed, since the restored database already has them. The images are pinned to
+// the digests recorded in the bundle, so that the environment runs the same images it was exported with,
+// unless KeepTags is set. If restoring the data fails the deployed environment is kept and returned along
+// with an error telling how to remove it.
+func ImportEnv(opts ImportEnvOpts) (*Env, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid import parameters: %w", err)
+	}
+
+	contents, err := readBackup(opts.Input, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = contents.manifest.Environment
+	}
+
+	if err := validate.Name(name); err != nil {
+		return nil, fmt.Errorf("'%s' is an invalid name for an environment: %w", name, err)
+	}
+
+	if err := EnsureEnvironmentDoesNotExist(name); err != nil {
+		return nil, fmt.Errorf("an environment with the name '%s' already exists: %w", name, err)
+	}
+
+	display.Step("Importing environment %s exported at %s as %s", contents.manifest.Environment, contents.manifest.CreatedAt.Local().Format(time.DateTime), name)
+
+	bundled, err := config.LoadConfigFromBytes(contents.config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config in bundle: %w", err)
+	}
+
+	if !opts.KeepTags {
+		pinned := pinImages(&bundled.Images, contents.images)
+		display.Debug("images pinned to their digest: %d", pinned)
+	}
+
+	cfg, err := cloneConfig(bundled, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := Deploy(context.Background(), DeployOpts{Config: cfg, SkipOntologies: true}); err != nil {
+		return nil, fmt.Errorf("failed to deploy the imported environment: %w", err)
+	}
+
+	env, err := Restore(RestoreOpts{Name: name, Input: opts.Input})
+	if err != nil {
+		env, _ = GetEnv(name)
+		return env, fmt.Errorf("environment '%s' deployed, but restoring its data failed, remove it with 'epos-opensource docker delete %s' before importing again: %w", name, name, err)
+	}
+
+	display.Done("Imported environment %s as %s", contents.manifest.Environment, name)
+
+	return env, nil
+}
+
+// Validate checks ImportEnvOpts, ensuring the bundle exists and the name, when given, is valid. Whether the
+// environment already exists is checked by ImportEnv, since the name can come from the bundle.
+func (i *ImportEnvOpts) Validate() error {
+	display.Debug("input: %s", i.Input)
+	display.Debug("name: %s", i.Name)
+	display.Debug("keepTags: %v", i.KeepTags)
+
+	if i.Input == "" {
+		return fmt.Errorf("a bundle is required")
+	}
+
+	if _, err := os.Stat(i.Input); err != nil {
+		return fmt.Errorf("error stating bundle %q: %w", i.Input, err)
+	}
+
+	if i.Name != "" {
+		if err := validate.Name(i.Name); err != nil {
+			return fmt.Errorf("'%s' is an invalid name for an environment: %w", i.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// pinImages replaces the references in images that have a digest recorded in bundled with that digest, and
+// returns how many were replaced. References without a recorded digest, for example of images built
+// locally, are kept.
+func pinImages(images *common.Images, bundled []backupImage) int {
+	digests := make(map[string]string, len(bundled))
+	for _, image := range bundled {
+		if image.Digest != "" {
+			digests[image.Ref] = image.Digest
+		}
+	}
+
+	refs := []*string{
+		&images.RabbitmqImage,
+		&images.DataportalImage,
+		&images.GatewayImage,
+		&images.MetadataDatabaseImage,
+		&images.ResourcesServiceImage,
+		&images.IngestorServiceImage,
+		&images.ExternalAccessImage,
+		&images.ConverterServiceImage,
+		&images.ConverterRoutineImage,
+		&images.BackofficeServiceImage,
+		&images.BackofficeUIImage,
+		&images.EmailSenderServiceImage,
+		&images.SharingServiceImage,
+		&images.AAIServiceImage,
+	}
+
+	var pinned int
+	for _, ref := range refs {
+		if digest, ok := digests[*ref]; ok {
+			*ref = digest
+			pinned++
+		}
+	}
+
+	return pinned
+}
@@ -0,0 +1,57 @@
+package docker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/EPOS-ERIC/epos-opensource/common"
+)
+
+func TestPinImages(t *testing.T) {
+	images := common.Images{
+		RabbitmqImage:         "rabbitmq:local",
+		GatewayImage:          "ghcr.io/epos-eric/epos-api-gateway:latest",
+		MetadataDatabaseImage: "ghcr.io/epos-eric/metadata-database/deploy:latest",
+		AAIServiceImage:       "ghcr.io/epos-eric/epos-api-gateway:latest",
+	}
+	bundled := []backupImage{
+		{Name: "Rabbitmq", Ref: "rabbitmq:local"},
+		{Name: "Gateway", Ref: "ghcr.io/epos-eric/epos-api-gateway:latest", Digest: "ghcr.io/epos-eric/epos-api-gateway@sha256:abc"},
+		{Name: "Metadata Database", Ref: "ghcr.io/epos-eric/metadata-database/deploy:latest", Digest: "ghcr.io/epos-eric/metadata-database/deploy@sha256:def"},
+	}
+
+	want := common.Images{
+		RabbitmqImage:         "rabbitmq:local",
+		GatewayImage:          "ghcr.io/epos-eric/epos-api-gateway@sha256:abc",
+		MetadataDatabaseImage: "ghcr.io/epos-eric/metadata-database/deploy@sha256:def",
+		AAIServiceImage:       "ghcr.io/epos-eric/epos-api-gateway@sha256:abc",
+	}
+
+	if got := pinImages(&images, bundled); got != 3 {
+		t.Fatalf("pinImages() = %d, want 3", got)
+	}
+	if images != want {
+		t.Fatalf("pinImages() images = %+v, want %+v", images, want)
+	}
+}
+
+func TestImportEnvOpts_Validate(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "env.tar.gz")
+
+	tests := []struct {
+		name string
+		opts ImportEnvOpts
+	}{
+		{name: "missing bundle path", opts: ImportEnvOpts{}},
+		{name: "non-existent bundle", opts: ImportEnvOpts{Input: bundle}},
+		{name: "invalid name", opts: ImportEnvOpts{Input: t.TempDir(), Name: "invalid name!"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); err == nil {
+				t.Fatal("Validate() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/EPOS-ERIC/epos-opensource/command"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// LogsOpts defines inputs for Logs.
+type LogsOpts struct {
+	// Required. name of the environment
+	Name string
+	// Optional. services whose logs are shown. The logs of every service are shown when empty
+	Services []string
+	// Optional. keep streaming new log lines until the context is cancelled
+	Follow bool
+	// Optional. only show the lines logged since this time, a timestamp (2026-01-02T15:04:05) or a duration relative to now (42m)
+	Since string
+	// Optional. number of lines shown from the end of the logs of every service, or "all". Defaults to all
+	Tail string
+	// Optional. regular expression the shown lines must match
+	Grep string
+	// Optional. show the lines that do not match Grep instead
+	InvertMatch bool
+	// Optional. where the log lines are written. Defaults to command.Stdout
+	Output io.Writer
+
+	// grep is the regular expression compiled from Grep by Validate
+	grep *regexp.Regexp
+}
+
+// Logs writes the logs of the services of a Docker environment to opts.Output, prefixed by the container they
+// come from. With Follow set it keeps streaming new lines until ctx is cancelled, which is not an error.
+func Logs(ctx context.Context, opts LogsOpts) error {
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid logs parameters: %w", err)
+	}
+
+	display.Debug("loading docker environment: %s", opts.Name)
+
+	env, err := GetEnv(opts.Name)
+	if err != nil {
+		return fmt.Errorf("error getting docker environment '%s': %w", opts.Name, err)
+	}
+
+	return withComposeBundle(&env.EnvConfig, func(bundle *composeBundle) error {
+		if len(opts.Services) > 0 {
+			services, err := composeServices(bundle, opts.Name)
+			if err != nil {
+				return err
+			}
+
+			for _, service := range opts.Services {
+				if !slices.Contains(services, service) {
+					return fmt.Errorf("environment '%s' has no service '%s', available services: %s", opts.Name, service, strings.Join(services, ", "))
+				}
+			}
+		}
+
+		cmd := composeCommand(bundle, opts.Name, opts.args()...)
+		display.Debug("streaming logs: %s", strings.Join(cmd.Args, " "))
+
+		return streamLogs(ctx, cmd, opts.output(), opts.grep, opts.InvertMatch)
+	})
+}
+
+// Validate checks LogsOpts, compiles Grep and ensures the environment exists.
+func (l *LogsOpts) Validate() error {
+	display.Debug("name: %s", l.Name)
+	display.Debug("services: %+v", l.Services)
+	display.Debug("follow: %v", l.Follow)
+	display.Debug("since: %s", l.Since)
+	display.Debug("tail: %s", l.Tail)
+	display.Debug("grep: %s", l.Grep)
+	display.Debug("invertMatch: %v", l.InvertMatch)
+
+	if l.Tail != "" && l.Tail != "all" {
+		if n, err := strconv.Atoi(l.Tail); err != nil || n < 0 {
+			return fmt.Errorf("tail must be a non-negative number of lines or 'all', got '%s'", l.Tail)
+		}
+	}
+
+	if l.Grep != "" {
+		grep, err := regexp.Compile(l.Grep)
+		if err != nil {
+			return fmt.Errorf("invalid grep pattern '%s': %w", l.Grep, err)
+		}
+		l.grep = grep
+	} else if l.InvertMatch {
+		return fmt.Errorf("a grep pattern is required to invert the match")
+	}
+
+	if err := EnsureEnvironmentExists(l.Name); err != nil {
+		return fmt.Errorf("no environment with the name '%s' exists: %w", l.Name, err)
+	}
+
+	return nil
+}
+
+// args returns the arguments of the docker compose logs command of opts.
+func (l *LogsOpts) args() []string {
+	args := []string{"logs"}
+	// colors would get in the way of the pattern, and of writers that are not terminals
+	if l.grep != nil || l.Output != nil {
+		args = append(args, "--no-color")
+	}
+	if l.Follow {
+		args = append(args, "--follow")
+	}
+	if l.Since != "" {
+		args = append(args, "--since", l.Since)
+	}
+	if l.Tail != "" {
+		args = append(args, "--tail", l.Tail)
+	}
+	return append(args, l.Services...)
+}
+
+func (l *LogsOpts) output() io.Writer {
+	if l.Output != nil {
+		return l.Output
+	}
+	return command.Stdout
+}
+
+// Services returns the names of the services of the Docker environment called name.
+func Services(name string) ([]string, error) {
+	env, err := GetEnv(name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting docker environment '%s': %w", name, err)
+	}
+
+	var services []string
+	err = withComposeBundle(&env.EnvConfig, func(bundle *composeBundle) error {
+		services, err = composeServices(bundle, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+// composeServices returns the names of the services of the compose bundle.
+func composeServices(bundle *composeBundle, projectName string) ([]string, error) {
+	output, err := command.RunCommand(composeCommand(bundle, projectName, "config", "--services"), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the services of environment '%s': %w", projectName, err)
+	}
+
+	services := strings.Fields(output)
+	slices.Sort(services)
+	return services, nil
+}
+
+// streamLogs runs cmd and copies the lines of its output to out, filtered with filterLines. The command is
+// stopped when ctx is cancelled, which is not an error.
+func streamLogs(ctx context.Context, cmd *exec.Cmd, out io.Writer, grep *regexp.Regexp, invert bool) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create docker compose logs stdout pipe: %w", err)
+	}
+
+	if err := command.StartCommand(cmd); err != nil {
+		return fmt.Errorf("failed to start docker compose logs: %w", err)
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = cmd.Process.Kill()
+	})
+	defer stop()
+
+	copyErr := filterLines(stdout, out, grep, invert)
+	if copyErr != nil {
+		// nothing reads the output anymore, so the command could block writing it
+		_ = cmd.Process.Kill()
+	}
+	waitErr := cmd.Wait()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	if copyErr != nil {
+		return copyErr
+	}
+	if waitErr != nil {
+		return fmt.Errorf("docker compose logs failed: %w: %s", waitErr, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// filterLines copies the lines of r to out, keeping only the lines matching grep, or not matching it when
+// invert is set. Every line is kept when grep is nil.
+func filterLines(r io.Reader, out io.Writer, grep *regexp.Regexp, invert bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if grep != nil && grep.Match(line) == invert {
+			continue
+		}
+
+		if _, err := out.Write(append(bytes.Clone(line), '\n')); err != nil {
+			return fmt.Errorf("failed to write log line: %w", err)
+		}
+	}
+	return scanner.Err()
+}
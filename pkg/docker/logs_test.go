@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFilterLines(t *testing.T) {
+	input := "gateway-1  | started\ningestor-1  | ERROR failed to ingest\ningestor-1  | ingested file\n"
+
+	tests := []struct {
+		name   string
+		grep   *regexp.Regexp
+		invert bool
+		want   string
+	}{
+		{
+			name: "no pattern keeps every line",
+			want: input,
+		},
+		{
+			name: "matching lines",
+			grep: regexp.MustCompile(`^ingestor`),
+			want: "ingestor-1  | ERROR failed to ingest\ningestor-1  | ingested file\n",
+		},
+		{
+			name:   "inverted match",
+			grep:   regexp.MustCompile(`ERROR`),
+			invert: true,
+			want:   "gateway-1  | started\ningestor-1  | ingested file\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := filterLines(strings.NewReader(input), &out, tt.grep, tt.invert); err != nil {
+				t.Fatalf("filterLines() error = %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Fatalf("filterLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogsOpts_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts LogsOpts
+	}{
+		{name: "negative tail", opts: LogsOpts{Name: "does_not_exist", Tail: "-1"}},
+		{name: "invalid tail", opts: LogsOpts{Name: "does_not_exist", Tail: "some"}},
+		{name: "invalid pattern", opts: LogsOpts{Name: "does_not_exist", Grep: "("}},
+		{name: "inverted match without pattern", opts: LogsOpts{Name: "does_not_exist", InvertMatch: true}},
+		{name: "non-existent environment", opts: LogsOpts{Name: "does_not_exist", Tail: "all"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); err == nil {
+				t.Fatal("Validate() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLogsOpts_Args(t *testing.T) {
+	opts := LogsOpts{
+		Services: []string{"gateway", "ingestor-service"},
+		Follow:   true,
+		Since:    "10m",
+		Tail:     "100",
+		grep:     regexp.MustCompile("ERROR"),
+	}
+
+	want := []string{"logs", "--no-color", "--follow", "--since", "10m", "--tail", "100", "gateway", "ingestor-service"}
+	if got := opts.args(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("args() = %v, want %v", got, want)
+	}
+
+	if got := (&LogsOpts{}).args(); !reflect.DeepEqual(got, []string{"logs"}) {
+		t.Fatalf("args() = %v, want [logs]", got)
+	}
+}
@@ -0,0 +1,132 @@
+package docker
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// AddOntologyOpts defines inputs for AddOntology.
+type AddOntologyOpts struct {
+	// Required. name of the environment
+	Name string
+	// Required. ontology to register and add to the environment config
+	Ontology common.Ontology
+	// Optional. make the ingestor fetch an embedded ontology from GitHub instead of uploading the embedded copy
+	RemoteOntologies bool
+}
+
+// ReloadOntologiesOpts defines inputs for ReloadOntologies.
+type ReloadOntologiesOpts struct {
+	// Required. name of the environment
+	Name string
+	// Optional. make the ingestor fetch the embedded ontologies from GitHub instead of uploading the embedded copies
+	RemoteOntologies bool
+}
+
+// ListOntologies returns the ontologies configured for a Docker environment, or the default ontologies when
+// its config has none.
+func ListOntologies(name string) ([]common.Ontology, error) {
+	env, err := GetEnv(name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting docker environment called '%s': %w", name, err)
+	}
+
+	return common.OntologiesOrDefault(env.Ontologies), nil
+}
+
+// AddOntology registers an ontology in the ingestor of an existing Docker environment and adds it to the
+// environment config, so that it is registered again when the environment is cleaned or recreated.
+func AddOntology(opts AddOntologyOpts) (*Env, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid add ontology parameters: %w", err)
+	}
+
+	env, err := GetEnv(opts.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting docker environment called '%s': %w", opts.Name, err)
+	}
+
+	ontologies := common.OntologiesOrDefault(env.Ontologies)
+	if slices.ContainsFunc(ontologies, func(o common.Ontology) bool { return o.Name == opts.Ontology.Name }) {
+		return nil, fmt.Errorf("ontology %s is already configured for environment '%s'", opts.Ontology.Name, opts.Name)
+	}
+
+	urls, err := env.BuildEnvURLs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build environment URLs: %w", err)
+	}
+
+	if err := common.PopulateOntologies(urls.APIURL, []common.Ontology{opts.Ontology}, opts.RemoteOntologies); err != nil {
+		return nil, fmt.Errorf("failed to register ontology %s: %w", opts.Ontology.Name, err)
+	}
+
+	cfg := env.EnvConfig
+	cfg.Ontologies = append(slices.Clone(ontologies), opts.Ontology)
+
+	env, err = upsertEnvConfig(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store environment config: %w", err)
+	}
+
+	display.Done("Added ontology %s to environment: %s", opts.Ontology.Name, opts.Name)
+
+	return env, nil
+}
+
+// ReloadOntologies registers all the ontologies configured for an existing Docker environment in its ingestor
+// again, e.g. after their upstream files changed.
+func ReloadOntologies(opts ReloadOntologiesOpts) (*Env, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid reload ontologies parameters: %w", err)
+	}
+
+	env, err := GetEnv(opts.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting docker environment called '%s': %w", opts.Name, err)
+	}
+
+	urls, err := env.BuildEnvURLs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build environment URLs: %w", err)
+	}
+
+	if err := common.PopulateOntologies(urls.APIURL, env.Ontologies, opts.RemoteOntologies); err != nil {
+		return nil, fmt.Errorf("failed to reload ontologies: %w", err)
+	}
+
+	display.Debug("reloaded ontologies using: %s", urls.APIURL)
+
+	return env, nil
+}
+
+// Validate checks AddOntologyOpts and ensures the target environment exists.
+func (a *AddOntologyOpts) Validate() error {
+	display.Debug("name: %s", a.Name)
+	display.Debug("ontology: %+v", a.Ontology)
+	display.Debug("remoteOntologies: %v", a.RemoteOntologies)
+
+	if err := common.ValidateOntology(a.Ontology); err != nil {
+		return err
+	}
+
+	if err := EnsureEnvironmentExists(a.Name); err != nil {
+		return fmt.Errorf("no environment with the name '%s' exists: %w", a.Name, err)
+	}
+
+	return nil
+}
+
+// Validate checks ReloadOntologiesOpts and ensures the target environment exists.
+func (r *ReloadOntologiesOpts) Validate() error {
+	display.Debug("name: %s", r.Name)
+	display.Debug("remoteOntologies: %v", r.RemoteOntologies)
+
+	if err := EnsureEnvironmentExists(r.Name); err != nil {
+		return fmt.Errorf("no environment with the name '%s' exists: %w", r.Name, err)
+	}
+
+	return nil
+}
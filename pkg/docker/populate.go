@@ -1,9 +1,12 @@
 package docker
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/EPOS-ERIC/epos-opensource/db"
@@ -12,7 +15,7 @@ import (
 
 // PopulateOpts defines inputs for Populate.
 type PopulateOpts struct {
-	// Required. paths to ttl files or directories containing ttl files to populate the environment
+	// Required. paths to RDF files (Turtle, N-Triples, JSON-LD or RDF/XML), directories or archives (.zip, .tar.gz) containing them to populate the environment
 	TTLDirs []string
 	// Required. name of the environment to populate
 	Name string
@@ -20,17 +23,87 @@ type PopulateOpts struct {
 	Parallel int
 	// Optional. weather to populate the examples or not
 	PopulateExamples bool
+	// Optional. make the gateway fetch the examples from GitHub instead of uploading the copies embedded in the binary
+	RemoteExamples bool
+	// Optional. only upload files that are new or whose content changed since they were last ingested
+	Incremental bool
+	// Optional. only check the syntax of the files, without uploading anything. The environment does not need to exist
+	DryRun bool
+	// Optional. how failed uploads are retried. The zero value disables retries
+	Retry common.RetryPolicy
+	// Optional. path of a machine-readable report of the outcome of every file, written even when populate fails
+	ReportFile string
+	// Optional. format of the report written to ReportFile. Required when ReportFile is set
+	ReportFormat common.ReportFormat
+	// Optional. ingestion type, model and mapping of the files. Empty fields use the defaults. Ingestion manifests in the populated directories override them
+	Ingestion common.IngestionSettings
+	// Optional. glob patterns selecting the files ingested from directories. The .eposignore file of every directory is applied too
+	Filter common.PathFilter
+	// Optional. ingest every path even when some files are invalid or fail, instead of stopping at the first path that fails. The failures are summarized at the end
+	KeepGoing bool
+	// Optional. keep running after the initial ingestion and re-ingest the files of TTLDirs as they change, until ctx is cancelled
+	Watch bool
+	// Optional. quiet period after the last change before changed files are re-ingested in watch mode. If not set common.DefaultWatchDebounce is used
+	WatchDebounce time.Duration
+	// Optional. path of a dataset manifest whose datasets are populated after TTLDirs, see common.DatasetManifest
+	Manifest string
+	// Optional. compress uploads with gzip. Files the gateway rejects as an unsupported media type are sent again uncompressed
+	Compress bool
+
+	// manifest is the dataset manifest loaded from Manifest by Validate
+	manifest common.DatasetManifest
 }
 
 // Populate ingests example or user-provided TTL data into an existing Docker environment.
-func Populate(opts PopulateOpts) (*Env, error) {
+// With DryRun set it only checks the syntax of the given files and returns a nil environment.
+// With ReportFile set, a report of the outcome of every file is written once populate ends, even on failure.
+// Cancelling ctx stops the ingestion; the files ingested until then are still recorded.
+// With KeepGoing set, every path is ingested even when some fail, and a table of the files that could not be
+// ingested is printed at the end.
+// With Watch set, Populate keeps watching TTLDirs after the initial ingestion, even if it failed, and
+// re-ingests and records every file that changes until ctx is cancelled.
+// With Manifest set, the datasets of the manifest are populated after TTLDirs, in their order.
+func Populate(ctx context.Context, opts PopulateOpts) (env *Env, err error) {
 	if err := opts.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid populate parameters: %w", err)
 	}
 
-	display.Step("Populating environment %s with %d path(s)", opts.Name, len(opts.TTLDirs))
+	datasets := opts.datasets()
+	paths := common.DatasetPaths(datasets)
+
+	if opts.DryRun {
+		display.Step("Checking RDF syntax of %d path(s)", len(paths))
+
+		for _, dataset := range datasets {
+			if _, err := common.ValidateTTLPaths(dataset.Paths, opts.Parallel, dataset.Filter()); err != nil {
+				return nil, fmt.Errorf("dry run failed: %w", err)
+			}
+		}
+
+		return nil, nil
+	}
 
-	env, err := GetEnv(opts.Name)
+	var report *common.PopulateReport
+	if opts.ReportFile != "" || opts.KeepGoing {
+		report = common.NewPopulateReport(opts.Name)
+	}
+	if opts.ReportFile != "" {
+		defer func() {
+			writeErr := report.WriteFile(opts.ReportFile, opts.ReportFormat)
+			switch {
+			case writeErr == nil:
+				display.Info("Populate report written to %s", opts.ReportFile)
+			case err == nil:
+				err = fmt.Errorf("error writing populate report: %w", writeErr)
+			default:
+				display.Error("error writing populate report: %v", writeErr)
+			}
+		}()
+	}
+
+	display.Step("Populating environment %s with %d path(s)", opts.Name, len(paths))
+
+	env, err = GetEnv(opts.Name)
 	if err != nil {
 		return nil, fmt.Errorf("error getting docker environment called '%s': %w", opts.Name, err)
 	}
@@ -42,52 +115,158 @@ func Populate(opts PopulateOpts) (*Env, error) {
 
 	display.Debug("loaded docker environment: %s (api: %s)", env.Name, urls.APIURL)
 
-	var allSuccessfulFiles []string
+	var ingested map[string]string
+	if opts.Incremental {
+		ingested, err = ingestedHashes(opts.Name)
+		if err != nil {
+			return nil, err
+		}
 
-	if opts.PopulateExamples {
-		display.Debug("populating bundled examples")
+		display.Debug("loaded content hashes of previously ingested files: %d", len(ingested))
+	}
 
-		successfulExamples, err := common.PopulateExample(urls.APIURL, opts.Parallel)
-		allSuccessfulFiles = append(allSuccessfulFiles, successfulExamples...)
-		if err != nil {
-			return nil, fmt.Errorf("error populating environment with examples: %w", err)
+	// the files ingested before a failure or an interrupt are recorded too, so that an incremental populate
+	// can pick up where this one stopped
+	allSuccessfulFiles, populateErr := populatePaths(ctx, opts, datasets, urls.APIURL, ingested, report)
+
+	for _, file := range allSuccessfulFiles {
+		display.Debug("recording ingested file: %s", file.Path)
+
+		if err := db.InsertIngestedFile(opts.Name, file.Path, file.ContentHash, file.SizeBytes); err != nil {
+			return nil, fmt.Errorf("error inserting ingested file record: %w", err)
 		}
+	}
 
-		display.Debug("populated example files: %d", len(successfulExamples))
+	display.Debug("recorded ingested files: %d", len(allSuccessfulFiles))
+
+	if opts.KeepGoing {
+		report.PrintFailures()
 	}
 
-	for _, p := range opts.TTLDirs {
-		display.Debug("processing metadata path: %s", p)
+	if populateErr != nil && (!opts.Watch || ctx.Err() != nil) {
+		return nil, populateErr
+	}
 
-		absPath, err := filepath.Abs(p)
-		if err != nil {
-			return nil, fmt.Errorf("error finding absolute path for given metadata path '%s': %w", p, err)
+	if populateErr != nil {
+		display.Error("%v", populateErr)
+	} else {
+		display.Done("Finished populating environment with ttl files from %d path(s)", len(paths))
+	}
+
+	if opts.Watch {
+		if err := watch(ctx, opts, urls.APIURL); err != nil {
+			return nil, err
 		}
+	}
+
+	return env, nil
+}
+
+// watch re-ingests the files of opts.TTLDirs as they change, recording every re-ingested file, until ctx is
+// cancelled.
+func watch(ctx context.Context, opts PopulateOpts, apiURL string) error {
+	ingested, err := ingestedHashes(opts.Name)
+	if err != nil {
+		return err
+	}
+
+	err = common.Watch(ctx, common.WatchOpts{
+		Paths:       opts.TTLDirs,
+		EndpointURL: apiURL,
+		Ingested:    ingested,
+		Retry:       opts.Retry,
+		Ingestion:   opts.Ingestion,
+		Filter:      opts.Filter,
+		Debounce:    opts.WatchDebounce,
+		Compress:    opts.Compress,
+		OnIngested: func(file common.IngestedFile) error {
+			if err := db.InsertIngestedFile(opts.Name, file.Path, file.ContentHash, file.SizeBytes); err != nil {
+				return fmt.Errorf("error inserting ingested file record: %w", err)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error watching for changes: %w", err)
+	}
+
+	return nil
+}
 
-		display.Debug("populating metadata from absolute path: %s", absPath)
+// populatePaths ingests the examples and every path of the given datasets through the given API URL, stopping
+// at the first path that fails. It returns the files ingested before the failure along with the error. With
+// opts.KeepGoing set it goes on with the next paths and returns the failures of all of them joined.
+func populatePaths(ctx context.Context, opts PopulateOpts, datasets []common.Dataset, apiURL string, ingested map[string]string, report *common.PopulateReport) ([]common.IngestedFile, error) {
+	var allSuccessfulFiles []common.IngestedFile
+	var errs []error
 
-		successfulFiles, err := common.PopulateEnv(absPath, urls.APIURL, opts.Parallel)
-		allSuccessfulFiles = append(allSuccessfulFiles, successfulFiles...)
+	if opts.PopulateExamples {
+		display.Debug("populating bundled examples")
+
+		successfulExamples, err := common.PopulateExample(ctx, apiURL, opts.Parallel, opts.Retry, report, opts.RemoteExamples)
+		for _, example := range successfulExamples {
+			allSuccessfulFiles = append(allSuccessfulFiles, common.IngestedFile{Path: example})
+		}
 		if err != nil {
-			return nil, fmt.Errorf("error populating environment: %w", err)
+			err = fmt.Errorf("error populating environment with examples: %w", err)
+			if !opts.KeepGoing || ctx.Err() != nil {
+				return allSuccessfulFiles, err
+			}
+			errs = append(errs, err)
 		}
 
-		display.Debug("populated metadata files from path %s: %d", absPath, len(successfulFiles))
+		display.Debug("populated example files: %d", len(successfulExamples))
 	}
 
-	// Insert ingested files into database
-	for _, filePath := range allSuccessfulFiles {
-		display.Debug("recording ingested file: %s", filePath)
+	for _, dataset := range datasets {
+		if dataset.Name != "" {
+			display.Step("Populating dataset %s", dataset.Name)
+		}
 
-		if err := db.InsertIngestedFile(opts.Name, filePath); err != nil {
-			return nil, fmt.Errorf("error inserting ingested file record: %w", err)
+		for _, p := range dataset.Paths {
+			display.Debug("processing metadata path: %s", p)
+
+			absPath, err := filepath.Abs(p)
+			if err != nil {
+				return allSuccessfulFiles, fmt.Errorf("error finding absolute path for given metadata path '%s': %w", p, err)
+			}
+
+			display.Debug("populating metadata from absolute path: %s", absPath)
+
+			successfulFiles, err := common.PopulateEnv(ctx, common.PopulateEnvOpts{
+				Path:        absPath,
+				EndpointURL: apiURL,
+				Parallel:    opts.Parallel,
+				Ingested:    ingested,
+				Retry:       opts.Retry,
+				Report:      report,
+				Ingestion:   dataset.Ingestion,
+				Filter:      dataset.Filter(),
+				KeepGoing:   opts.KeepGoing,
+				Compress:    opts.Compress,
+			})
+			allSuccessfulFiles = append(allSuccessfulFiles, successfulFiles...)
+			if err != nil {
+				err = fmt.Errorf("error populating environment: %w", err)
+				if dataset.Name != "" {
+					err = fmt.Errorf("error populating dataset %s: %w", dataset.Name, err)
+				}
+				if !opts.KeepGoing || ctx.Err() != nil {
+					return allSuccessfulFiles, err
+				}
+				errs = append(errs, err)
+				continue
+			}
+
+			display.Debug("populated metadata files from path %s: %d", absPath, len(successfulFiles))
 		}
 	}
 
-	display.Debug("recorded ingested files: %d", len(allSuccessfulFiles))
-	display.Done("Finished populating environment with ttl files from %d path(s)", len(opts.TTLDirs))
+	if len(errs) > 0 {
+		return allSuccessfulFiles, fmt.Errorf("populate failed for %d path(s): %w", len(errs), errors.Join(errs...))
+	}
 
-	return env, nil
+	return allSuccessfulFiles, nil
 }
 
 // Validate checks PopulateOpts and verifies that input paths and environment state are valid.
@@ -96,27 +275,123 @@ func (p *PopulateOpts) Validate() error {
 	display.Debug("ttlDirs: %+v", p.TTLDirs)
 	display.Debug("parallel: %d", p.Parallel)
 	display.Debug("populateExamples: %v", p.PopulateExamples)
+	display.Debug("remoteExamples: %v", p.RemoteExamples)
+	display.Debug("incremental: %v", p.Incremental)
+	display.Debug("dryRun: %v", p.DryRun)
+	display.Debug("retry: %+v", p.Retry)
+	display.Debug("reportFile: %s", p.ReportFile)
+	display.Debug("reportFormat: %s", p.ReportFormat)
+	display.Debug("ingestion: %+v", p.Ingestion)
+	display.Debug("filter: %s", p.Filter)
+	display.Debug("keepGoing: %v", p.KeepGoing)
+	display.Debug("watch: %v", p.Watch)
+	display.Debug("watchDebounce: %s", p.WatchDebounce)
+	display.Debug("manifest: %s", p.Manifest)
+	display.Debug("compress: %v", p.Compress)
 
 	if p.Parallel < 1 || p.Parallel > 20 {
 		return fmt.Errorf("parallel uploads must be between 1 and 20")
 	}
 
-	if err := EnsureEnvironmentExists(p.Name); err != nil {
-		return fmt.Errorf("no environment with name '%s' exists: %w", p.Name, err)
+	if err := p.Retry.Validate(); err != nil {
+		return fmt.Errorf("invalid retry policy: %w", err)
+	}
+
+	if err := p.Ingestion.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Filter.Validate(); err != nil {
+		return err
+	}
+
+	if p.Manifest != "" {
+		manifest, err := common.LoadDatasetManifest(p.Manifest)
+		if err != nil {
+			return err
+		}
+
+		p.manifest = manifest
+		if manifest.Examples {
+			p.PopulateExamples = true
+		}
+	}
+
+	if p.Watch {
+		if p.Manifest != "" {
+			return fmt.Errorf("watch mode cannot be combined with a dataset manifest")
+		}
+		if p.DryRun {
+			return fmt.Errorf("watch mode cannot be combined with a dry run")
+		}
+		if len(p.TTLDirs) == 0 {
+			return fmt.Errorf("watch mode requires at least one path to watch")
+		}
+		if p.ReportFile != "" {
+			return fmt.Errorf("reports are not written in watch mode")
+		}
+		if p.WatchDebounce < 0 {
+			return fmt.Errorf("watch debounce must not be negative")
+		}
+		for _, item := range p.TTLDirs {
+			if common.IsArchive(item) {
+				return fmt.Errorf("archive '%s' cannot be watched, extract it first", item)
+			}
+		}
+	}
+
+	if p.ReportFile != "" || p.ReportFormat != "" {
+		if p.ReportFile == "" {
+			return fmt.Errorf("a report file is required when a report format is set")
+		}
+		if _, err := common.ParseReportFormat(string(p.ReportFormat)); err != nil {
+			return err
+		}
+		if p.DryRun {
+			return fmt.Errorf("reports are not written for dry runs")
+		}
+	}
+
+	if !p.DryRun {
+		if err := EnsureEnvironmentExists(p.Name); err != nil {
+			return fmt.Errorf("no environment with name '%s' exists: %w", p.Name, err)
+		}
 	}
 
-	for _, item := range p.TTLDirs {
+	for _, item := range common.DatasetPaths(p.datasets()) {
 		info, err := os.Stat(item)
 		if err != nil {
 			return fmt.Errorf("error stating path %q: %w", item, err)
 		}
 
-		if !info.IsDir() {
-			if filepath.Ext(item) != ".ttl" {
-				return fmt.Errorf("file %s is not a .ttl file", item)
+		if !info.IsDir() && !common.IsArchive(item) {
+			if _, err := common.DetectRDFFormat(item); err != nil {
+				return fmt.Errorf("file %s is not a supported RDF file: %w", item, err)
 			}
 		}
 	}
 
 	return nil
 }
+
+// datasets returns the datasets populated with opts: TTLDirs followed by the datasets of the manifest.
+func (p *PopulateOpts) datasets() []common.Dataset {
+	return common.PopulateDatasets(p.TTLDirs, p.Filter, p.Ingestion, p.manifest)
+}
+
+// ingestedHashes returns the recorded content hashes of the files ingested into an environment, keyed by path.
+func ingestedHashes(name string) (map[string]string, error) {
+	files, err := db.GetIngestedFilesByEnvironment(name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting ingested files for environment '%s': %w", name, err)
+	}
+
+	hashes := make(map[string]string, len(files))
+	for _, file := range files {
+		if file.ContentHash != nil {
+			hashes[file.FilePath] = *file.ContentHash
+		}
+	}
+
+	return hashes, nil
+}
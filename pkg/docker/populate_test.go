@@ -29,6 +29,11 @@ func TestPopulateOpts_Validate(t *testing.T) {
 			opts:    PopulateOpts{Name: "does-not-exist", Parallel: 1, TTLDirs: []string{tmpDir}},
 			wantErr: true,
 		},
+		{
+			name:    "Dry run does not require the environment",
+			opts:    PopulateOpts{Name: "does-not-exist", Parallel: 1, TTLDirs: []string{tmpDir}, DryRun: true},
+			wantErr: false,
+		},
 		{
 			name:    "Parallel above maximum",
 			opts:    PopulateOpts{Name: "test", Parallel: 21, TTLDirs: []string{tmpDir}},
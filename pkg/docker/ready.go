@@ -0,0 +1,208 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker/config"
+)
+
+// DefaultReadyTimeout is how long the services of an environment are waited for before its ontologies are
+// initialized, when no timeout is given.
+const DefaultReadyTimeout = 5 * time.Minute
+
+// readyPollInterval is the delay between two checks of the services of an environment.
+const readyPollInterval = 2 * time.Second
+
+// readyServices are the services the ontologies are initialized through, which must be healthy before it.
+// The other services are not waited for, as the initialization does not need them.
+var readyServices = []string{"gateway", "ingestor-service", "metadata-database", "rabbitmq"}
+
+// readyEndpoint is an HTTP endpoint of an environment that must answer before it is initialized.
+type readyEndpoint struct {
+	// name of the service behind the endpoint
+	name string
+	url  string
+	// ready reports whether a response with status means the endpoint is ready
+	ready func(status int) bool
+}
+
+// readyEndpoints returns the endpoints checked before the ontologies are initialized through the gateway at
+// apiURL: the UI of the gateway, which the healthcheck of its container also uses, and the ontology route of
+// the ingestor behind it. The gateway answers 502, 503 or 504 while the ingestor cannot be reached; any other
+// status comes from the ingestor, which is then ready to receive the ontologies.
+func readyEndpoints(apiURL string) ([]readyEndpoint, error) {
+	base, err := url.Parse(strings.TrimSuffix(apiURL, "/ui"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing api url '%s': %w", apiURL, err)
+	}
+
+	return []readyEndpoint{
+		{
+			name: "gateway",
+			url:  base.JoinPath("ui").String(),
+			ready: func(status int) bool {
+				return status == http.StatusOK
+			},
+		},
+		{
+			name: "ingestor-service",
+			url:  base.JoinPath("ontology").String(),
+			ready: func(status int) bool {
+				return status != http.StatusBadGateway && status != http.StatusServiceUnavailable && status != http.StatusGatewayTimeout
+			},
+		},
+	}, nil
+}
+
+// waitReady waits until the environment described by cfg can be initialized: the containers of readyServices
+// are running and pass their healthcheck, and the gateway and the ingestor answer on their endpoints. Every
+// service is reported as it becomes healthy, and the other services that are not healthy yet are reported
+// with a warning once the environment is ready. When the environment is not ready within timeout, or
+// DefaultReadyTimeout when timeout is zero, the error names the services that never became ready and their
+// last state. Cancelling ctx stops waiting.
+func waitReady(ctx context.Context, cfg *config.EnvConfig, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultReadyTimeout
+	}
+
+	urls, err := cfg.BuildEnvURLs()
+	if err != nil {
+		return fmt.Errorf("error building urls: %w", err)
+	}
+
+	endpoints, err := readyEndpoints(urls.APIURL)
+	if err != nil {
+		return err
+	}
+
+	display.Step("Waiting for the services to become ready (timeout %s)", timeout)
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	httpClient := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	start := time.Now()
+	ready := map[string]bool{}
+	for {
+		// the containers can fail to be inspected while compose is still recreating them, so errors are retried
+		statuses, err := serviceStatuses(cfg.Name)
+		if err != nil {
+			display.Debug("failed to get the status of the services: %v", err)
+		}
+
+		pending, others := pendingServices(statuses)
+		if err != nil {
+			pending = []string{fmt.Sprintf("status of the services unavailable (%v)", err)}
+		}
+		for _, status := range statuses {
+			if status.Healthy() && !ready[status.Service] {
+				ready[status.Service] = true
+				display.Done("  %s is healthy (%s)", status.Service, time.Since(start).Truncate(time.Second))
+			}
+		}
+
+		// the endpoints are only checked once their containers are healthy, as they cannot answer before
+		if len(pending) == 0 {
+			for _, endpoint := range endpoints {
+				key := endpoint.name + " endpoint"
+				if ready[key] {
+					continue
+				}
+
+				if err := probeEndpoint(waitCtx, httpClient, endpoint); err != nil {
+					display.Debug("endpoint of %s not ready: %v", endpoint.name, err)
+					pending = append(pending, fmt.Sprintf("%s (%v)", endpoint.name, err))
+					continue
+				}
+
+				ready[key] = true
+				display.Done("  %s is answering on %s (%s)", endpoint.name, endpoint.url, time.Since(start).Truncate(time.Second))
+			}
+		}
+
+		if len(pending) == 0 {
+			if len(others) > 0 {
+				display.Warn("Services not healthy yet, they are not needed to initialize the environment: %s", strings.Join(others, ", "))
+			}
+			display.Done("All required services are ready")
+			return nil
+		}
+
+		display.Debug("services not ready yet: %s", strings.Join(pending, ", "))
+
+		select {
+		case <-waitCtx.Done():
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("stopped waiting for the services: %w", err)
+			}
+			return fmt.Errorf("services not ready after %s: %s", timeout, strings.Join(pending, ", "))
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
+
+// pendingServices returns a description of every service in statuses that is not healthy yet, with its state
+// and health, e.g. "ingestor-service (running, starting)". The services in readyServices are returned in
+// required, including the ones without a container, and the other services in others.
+func pendingServices(statuses []ServiceStatus) (required []string, others []string) {
+	found := map[string]bool{}
+	for _, status := range statuses {
+		found[status.Service] = true
+		if status.Healthy() {
+			continue
+		}
+
+		state := status.State
+		if status.Health != "" {
+			state += ", " + status.Health
+		}
+		description := fmt.Sprintf("%s (%s)", status.Service, state)
+
+		if slices.Contains(readyServices, status.Service) {
+			required = append(required, description)
+		} else {
+			others = append(others, description)
+		}
+	}
+
+	for _, service := range readyServices {
+		if !found[service] {
+			required = append(required, fmt.Sprintf("%s (no container)", service))
+		}
+	}
+
+	return required, others
+}
+
+// probeEndpoint sends a GET request to endpoint and returns an error when it cannot be reached or it answers
+// with a status meaning it is not ready.
+func probeEndpoint(ctx context.Context, httpClient *http.Client, endpoint readyEndpoint) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unreachable: %w", err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		return fmt.Errorf("failed to close body: %w", err)
+	}
+
+	if !endpoint.ready(resp.StatusCode) {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	return nil
+}
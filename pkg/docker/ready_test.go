@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPendingServices(t *testing.T) {
+	statuses := []ServiceStatus{
+		{Service: "aai-service", State: "restarting"},
+		{Service: "dataportal", State: "running"},
+		{Service: "gateway", State: "running", Health: HealthStarting},
+		{Service: "ingestor-service", State: "running", Health: HealthUnhealthy},
+		{Service: "metadata-database", State: "running", Health: HealthHealthy},
+		{Service: "sharing-service", State: "running", Health: HealthUnhealthy},
+	}
+
+	wantRequired := []string{"gateway (running, starting)", "ingestor-service (running, unhealthy)", "rabbitmq (no container)"}
+	wantOthers := []string{"aai-service (restarting)", "sharing-service (running, unhealthy)"}
+
+	required, others := pendingServices(statuses)
+	if !reflect.DeepEqual(required, wantRequired) {
+		t.Fatalf("pendingServices() required = %v, want %v", required, wantRequired)
+	}
+	if !reflect.DeepEqual(others, wantOthers) {
+		t.Fatalf("pendingServices() others = %v, want %v", others, wantOthers)
+	}
+}
+
+func TestReadyEndpoints(t *testing.T) {
+	endpoints, err := readyEndpoints("http://localhost:33000/api/v1")
+	if err != nil {
+		t.Fatalf("readyEndpoints() error = %v", err)
+	}
+
+	var urls []string
+	for _, endpoint := range endpoints {
+		urls = append(urls, endpoint.url)
+	}
+	want := []string{"http://localhost:33000/api/v1/ui", "http://localhost:33000/api/v1/ontology"}
+	if !reflect.DeepEqual(urls, want) {
+		t.Fatalf("readyEndpoints() urls = %v, want %v", urls, want)
+	}
+}
+
+func TestProbeEndpoint(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	endpoints, err := readyEndpoints(server.URL)
+	if err != nil {
+		t.Fatalf("readyEndpoints() error = %v", err)
+	}
+	gateway, ingestor := endpoints[0], endpoints[1]
+
+	tests := []struct {
+		name     string
+		status   int
+		endpoint readyEndpoint
+		wantErr  bool
+	}{
+		{name: "gateway unavailable", status: http.StatusServiceUnavailable, endpoint: gateway, wantErr: true},
+		{name: "gateway ready", status: http.StatusOK, endpoint: gateway},
+		{name: "ingestor behind a bad gateway", status: http.StatusBadGateway, endpoint: ingestor, wantErr: true},
+		{name: "ingestor answering", status: http.StatusMethodNotAllowed, endpoint: ingestor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status = tt.status
+			err := probeEndpoint(context.Background(), server.Client(), tt.endpoint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("probeEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	server.Close()
+	if err := probeEndpoint(context.Background(), http.DefaultClient, gateway); err == nil {
+		t.Fatal("probeEndpoint() error = nil, want error for an unreachable endpoint")
+	}
+}
@@ -0,0 +1,64 @@
+package docker
+
+import (
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// StartOpts defines inputs for Start.
+type StartOpts struct {
+	Name []string // names of environments
+}
+
+// Start starts again the containers of one or more Docker environments stopped with Stop, from their stored
+// config. Containers that were removed in the meantime are created again; volumes, and so the ingested
+// metadata, are kept. The images of the environments must still be available locally.
+func Start(opts StartOpts) error {
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid start parameters: %w", err)
+	}
+
+	var eg errgroup.Group
+	eg.SetLimit(20)
+	for _, envName := range opts.Name {
+		eg.Go(func() error {
+			display.Step("Starting environment: %s", envName)
+			display.Debug("loading docker environment: %s", envName)
+
+			env, err := GetEnv(envName)
+			if err != nil {
+				return fmt.Errorf("error getting docker environment '%s': %w", envName, err)
+			}
+
+			display.Debug("running docker compose up for environment: %s", envName)
+
+			if err := startStack(&env.EnvConfig); err != nil {
+				return fmt.Errorf("failed to start environment '%s': %w", envName, err)
+			}
+
+			display.Done("Started environment: %s", envName)
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks StartOpts and ensures every requested environment exists.
+func (s *StartOpts) Validate() error {
+	display.Debug("names: %+v", s.Name)
+
+	for _, env := range s.Name {
+		if err := EnsureEnvironmentExists(env); err != nil {
+			return fmt.Errorf("no environment with the name '%s' exists: %w", env, err)
+		}
+	}
+	return nil
+}
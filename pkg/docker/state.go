@@ -0,0 +1,84 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/EPOS-ERIC/epos-opensource/command"
+)
+
+// composeProjectLabel is the label docker compose sets on every container with the name of its project, which
+// is the name of the environment.
+const composeProjectLabel = "com.docker.compose.project"
+
+// EnvState is the running state of the containers of a Docker environment.
+type EnvState string
+
+const (
+	// EnvRunning means that every container of the environment is running
+	EnvRunning EnvState = "running"
+	// EnvPartial means that some containers of the environment are running and others are not
+	EnvPartial EnvState = "partial"
+	// EnvStopped means that no container of the environment is running
+	EnvStopped EnvState = "stopped"
+)
+
+// States returns the running state of every environment, keyed by name. Environments without any container
+// are not in the map, and are stopped.
+func States() (map[string]EnvState, error) {
+	cmd := exec.Command("docker", "ps", "-a",
+		"--filter", "label="+composeProjectLabel,
+		"--format", `{{.Label "`+composeProjectLabel+`"}}	{{.State}}`,
+	)
+
+	output, err := command.RunCommand(cmd, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list docker containers: %w", err)
+	}
+
+	return parseStates(output), nil
+}
+
+// State returns the running state of the environment called name.
+func State(name string) (EnvState, error) {
+	states, err := States()
+	if err != nil {
+		return "", err
+	}
+
+	if state, ok := states[name]; ok {
+		return state, nil
+	}
+	return EnvStopped, nil
+}
+
+// parseStates computes the state of every project from lines of "<project>\t<container state>".
+func parseStates(output string) map[string]EnvState {
+	running := map[string]int{}
+	total := map[string]int{}
+	for line := range strings.Lines(output) {
+		project, state, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok || project == "" {
+			continue
+		}
+
+		total[project]++
+		if state == "running" {
+			running[project]++
+		}
+	}
+
+	states := make(map[string]EnvState, len(total))
+	for project, n := range total {
+		switch running[project] {
+		case 0:
+			states[project] = EnvStopped
+		case n:
+			states[project] = EnvRunning
+		default:
+			states[project] = EnvPartial
+		}
+	}
+	return states
+}
@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStates(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   map[string]EnvState
+	}{
+		{
+			name:   "no containers",
+			output: "",
+			want:   map[string]EnvState{},
+		},
+		{
+			name:   "running, partial and stopped environments",
+			output: "up\trunning\nup\trunning\nhalf\trunning\nhalf\texited\ndown\texited\ndown\tcreated\n",
+			want:   map[string]EnvState{"up": EnvRunning, "half": EnvPartial, "down": EnvStopped},
+		},
+		{
+			name:   "malformed lines are ignored",
+			output: "garbage\n\trunning\nup\trunning\n",
+			want:   map[string]EnvState{"up": EnvRunning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseStates(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseStates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartStopOpts_Validate(t *testing.T) {
+	names := []string{"does_not_exist"}
+
+	if err := (&StartOpts{Name: names}).Validate(); err == nil {
+		t.Fatal("StartOpts.Validate() error = nil, want error for a non-existent environment")
+	}
+	if err := (&StopOpts{Name: names}).Validate(); err == nil {
+		t.Fatal("StopOpts.Validate() error = nil, want error for a non-existent environment")
+	}
+}
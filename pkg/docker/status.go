@@ -0,0 +1,159 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/command"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// composeServiceLabel is the label docker compose sets on every container with the name of its service.
+const composeServiceLabel = "com.docker.compose.service"
+
+// Health statuses of containers with a healthcheck. Containers without a healthcheck have an empty health.
+const (
+	HealthStarting  = "starting"
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// ServiceStatus is the status of the container of a service of a Docker environment.
+type ServiceStatus struct {
+	// Service is the name of the compose service
+	Service string
+	// Container is the name of the container of the service
+	Container string
+	// State is the state of the container: running, exited, restarting, created, paused or dead
+	State string
+	// Health is the status of the healthcheck of the container, empty when the service has no healthcheck
+	Health string
+	// RestartCount is the number of times docker restarted the container
+	RestartCount int
+	// StartedAt is when the container was last started
+	StartedAt time.Time
+	// Image is the image reference the container was created from
+	Image string
+	// ImageDigest is the content-addressable ID of the image the container runs
+	ImageDigest string
+}
+
+// Healthy reports whether the container of the service is running and is not failing or still waiting for its
+// healthcheck.
+func (s ServiceStatus) Healthy() bool {
+	return s.State == "running" && (s.Health == "" || s.Health == HealthHealthy)
+}
+
+// Uptime returns for how long the container has been running at now, or zero when it is not running.
+func (s ServiceStatus) Uptime(now time.Time) time.Duration {
+	if s.State != "running" || s.StartedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(s.StartedAt).Truncate(time.Second)
+}
+
+// ShortImageDigest returns the image digest without its algorithm, shortened to the 12 characters docker shows.
+func (s ServiceStatus) ShortImageDigest() string {
+	_, digest, ok := strings.Cut(s.ImageDigest, ":")
+	if !ok {
+		digest = s.ImageDigest
+	}
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return digest
+}
+
+// Status returns the status of the containers of every service of the environment called name, sorted by
+// service. A stopped environment whose containers were removed has no services.
+func Status(name string) ([]ServiceStatus, error) {
+	if err := EnsureEnvironmentExists(name); err != nil {
+		return nil, fmt.Errorf("no environment with the name '%s' exists: %w", name, err)
+	}
+
+	return serviceStatuses(name)
+}
+
+// serviceStatuses returns the status of the containers of the compose project called name, sorted by service.
+// Unlike Status it does not require the environment to be stored, so it can be used while it is deployed.
+func serviceStatuses(name string) ([]ServiceStatus, error) {
+	display.Debug("listing containers of environment: %s", name)
+
+	cmd := exec.Command("docker", "ps", "-a", "-q", "--no-trunc",
+		"--filter", "label="+composeProjectLabel+"="+name,
+	)
+	output, err := command.RunCommand(cmd, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the containers of environment '%s': %w", name, err)
+	}
+
+	ids := strings.Fields(output)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	display.Debug("inspecting %d containers of environment: %s", len(ids), name)
+
+	cmd = exec.Command("docker", append([]string{"inspect", "--type", "container"}, ids...)...)
+	output, err = command.RunCommand(cmd, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect the containers of environment '%s': %w", name, err)
+	}
+
+	return parseServiceStatuses([]byte(output))
+}
+
+// containerInspect is the part of the output of docker inspect read by Status.
+type containerInspect struct {
+	Name         string
+	Image        string
+	RestartCount int
+	State        struct {
+		Status    string
+		StartedAt time.Time
+		Health    *struct {
+			Status string
+		}
+	}
+	Config struct {
+		Image  string
+		Labels map[string]string
+	}
+}
+
+// parseServiceStatuses parses the JSON output of docker inspect on the containers of an environment.
+func parseServiceStatuses(output []byte) ([]ServiceStatus, error) {
+	var containers []containerInspect
+	if err := json.Unmarshal(output, &containers); err != nil {
+		return nil, fmt.Errorf("failed to parse docker inspect output: %w", err)
+	}
+
+	statuses := make([]ServiceStatus, 0, len(containers))
+	for _, c := range containers {
+		status := ServiceStatus{
+			Service:      c.Config.Labels[composeServiceLabel],
+			Container:    strings.TrimPrefix(c.Name, "/"),
+			State:        c.State.Status,
+			RestartCount: c.RestartCount,
+			StartedAt:    c.State.StartedAt,
+			Image:        c.Config.Image,
+			ImageDigest:  c.Image,
+		}
+		if c.State.Health != nil {
+			status.Health = c.State.Health.Status
+		}
+		statuses = append(statuses, status)
+	}
+
+	slices.SortFunc(statuses, func(a, b ServiceStatus) int {
+		if c := strings.Compare(a.Service, b.Service); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Container, b.Container)
+	})
+	return statuses, nil
+}
@@ -0,0 +1,94 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseServiceStatuses(t *testing.T) {
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	output := `[
+  {
+    "Name": "/env-ingestor-service-1",
+    "Image": "sha256:0123456789abcdef0123456789abcdef",
+    "RestartCount": 2,
+    "State": {"Status": "running", "StartedAt": "2026-01-02T03:04:05Z", "Health": {"Status": "unhealthy"}},
+    "Config": {"Image": "epos/ingestor:1.0", "Labels": {"com.docker.compose.service": "ingestor-service"}}
+  },
+  {
+    "Name": "/env-gateway-1",
+    "Image": "sha256:fedcba9876543210fedcba9876543210",
+    "RestartCount": 0,
+    "State": {"Status": "exited", "StartedAt": "2026-01-02T03:04:05Z"},
+    "Config": {"Image": "epos/gateway:1.0", "Labels": {"com.docker.compose.service": "gateway"}}
+  }
+]`
+
+	got, err := parseServiceStatuses([]byte(output))
+	if err != nil {
+		t.Fatalf("parseServiceStatuses() error = %v", err)
+	}
+
+	want := []ServiceStatus{
+		{
+			Service:     "gateway",
+			Container:   "env-gateway-1",
+			State:       "exited",
+			StartedAt:   started,
+			Image:       "epos/gateway:1.0",
+			ImageDigest: "sha256:fedcba9876543210fedcba9876543210",
+		},
+		{
+			Service:      "ingestor-service",
+			Container:    "env-ingestor-service-1",
+			State:        "running",
+			Health:       HealthUnhealthy,
+			RestartCount: 2,
+			StartedAt:    started,
+			Image:        "epos/ingestor:1.0",
+			ImageDigest:  "sha256:0123456789abcdef0123456789abcdef",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseServiceStatuses() = %+v, want %+v", got, want)
+	}
+
+	if _, err := parseServiceStatuses([]byte("not json")); err == nil {
+		t.Fatal("parseServiceStatuses() error = nil, want error for invalid output")
+	}
+}
+
+func TestServiceStatus(t *testing.T) {
+	now := time.Date(2026, 1, 2, 4, 4, 5, 0, time.UTC)
+	started := now.Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		status      ServiceStatus
+		wantHealthy bool
+		wantUptime  time.Duration
+	}{
+		{name: "running without healthcheck", status: ServiceStatus{State: "running", StartedAt: started}, wantHealthy: true, wantUptime: time.Hour},
+		{name: "running and healthy", status: ServiceStatus{State: "running", Health: HealthHealthy, StartedAt: started}, wantHealthy: true, wantUptime: time.Hour},
+		{name: "running and starting", status: ServiceStatus{State: "running", Health: HealthStarting, StartedAt: started}, wantUptime: time.Hour},
+		{name: "running and unhealthy", status: ServiceStatus{State: "running", Health: HealthUnhealthy, StartedAt: started}, wantUptime: time.Hour},
+		{name: "exited", status: ServiceStatus{State: "exited", StartedAt: started}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.Healthy(); got != tt.wantHealthy {
+				t.Fatalf("Healthy() = %v, want %v", got, tt.wantHealthy)
+			}
+			if got := tt.status.Uptime(now); got != tt.wantUptime {
+				t.Fatalf("Uptime() = %v, want %v", got, tt.wantUptime)
+			}
+		})
+	}
+
+	if got := (ServiceStatus{ImageDigest: "sha256:0123456789abcdef"}).ShortImageDigest(); got != "0123456789ab" {
+		t.Fatalf("ShortImageDigest() = %q, want %q", got, "0123456789ab")
+	}
+}
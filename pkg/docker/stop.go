@@ -0,0 +1,63 @@
+package docker
+
+import (
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// StopOpts defines inputs for Stop.
+type StopOpts struct {
+	Name []string // names of environments
+}
+
+// Stop stops the containers of one or more Docker environments to free their resources. Containers, volumes
+// and the stored config are kept, so that the environments can be started again with Start.
+func Stop(opts StopOpts) error {
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid stop parameters: %w", err)
+	}
+
+	var eg errgroup.Group
+	eg.SetLimit(20)
+	for _, envName := range opts.Name {
+		eg.Go(func() error {
+			display.Step("Stopping environment: %s", envName)
+			display.Debug("loading docker environment: %s", envName)
+
+			env, err := GetEnv(envName)
+			if err != nil {
+				return fmt.Errorf("error getting docker environment '%s': %w", envName, err)
+			}
+
+			display.Debug("running docker compose stop for environment: %s", envName)
+
+			if err := stopStack(&env.EnvConfig); err != nil {
+				return fmt.Errorf("failed to stop environment '%s': %w", envName, err)
+			}
+
+			display.Done("Stopped environment: %s", envName)
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks StopOpts and ensures every requested environment exists.
+func (s *StopOpts) Validate() error {
+	display.Debug("names: %+v", s.Name)
+
+	for _, env := range s.Name {
+		if err := EnsureEnvironmentExists(env); err != nil {
+			return fmt.Errorf("no environment with the name '%s' exists: %w", env, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/db"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// UnpopulateOpts defines inputs for Unpopulate.
+type UnpopulateOpts struct {
+	// Required. name of the environment
+	Name string
+	// Required. paths of ingested files, or of directories containing ingested files, to remove from the environment
+	Paths []string
+	// Optional. only list the entities that would be removed, without removing anything
+	DryRun bool
+	// Optional. access token of a backoffice administrator, sent to the backoffice API. Required to remove
+	// anything when the backoffice service auth is enabled
+	Token string
+}
+
+// Unpopulate removes the metadata ingested from the given files from an existing Docker environment. The files
+// must be tracked as ingested into the environment and still be readable, unchanged since they were
+// ingested: the entities they define are determined from their content and deleted through the backoffice API, so the environment must be
+// deployed with the backoffice enabled, and a Token is needed when its API is behind auth. Entities that are also
+// defined by another tracked file that is not being removed, or by a tracked example, are kept. The entities
+// of the examples are read from their embedded copies, so nothing is removed while an example without an
+// embedded copy is tracked.
+//
+// The tracking record of a file is deleted once all of its entities are removed, so a failure or a cancelled ctx
+// leaves the remaining files tracked and Unpopulate can be run again.
+func Unpopulate(ctx context.Context, opts UnpopulateOpts) (*Env, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid unpopulate parameters: %w", err)
+	}
+
+	env, err := GetEnv(opts.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting docker environment called '%s': %w", opts.Name, err)
+	}
+
+	if !env.Components.Backoffice.Enabled {
+		return nil, fmt.Errorf("environment '%s' has no backoffice, which is required to remove metadata", opts.Name)
+	}
+
+	urls, err := env.BuildEnvURLs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build environment URLs: %w", err)
+	}
+
+	if !opts.DryRun && opts.Token == "" && env.Components.Backoffice.Service.Auth.Enabled {
+		return nil, fmt.Errorf("the backoffice API of environment '%s' requires authentication: log in to the backoffice at %s as an administrator and pass the access token with --token or the EPOS_ACCESS_TOKEN environment variable", opts.Name, *urls.BackofficeURL)
+	}
+
+	tracked, err := db.GetIngestedFilesByEnvironment(opts.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting ingested files for environment '%s': %w", opts.Name, err)
+	}
+
+	var trackedPaths []string
+	hashes := map[string]string{}
+	for _, file := range tracked {
+		trackedPaths = append(trackedPaths, file.FilePath)
+		if file.ContentHash != nil {
+			hashes[file.FilePath] = *file.ContentHash
+		}
+	}
+
+	files, err := selectTrackedFiles(trackedPaths, opts.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	display.Step("Determining the entities defined by %d file(s)", len(files))
+
+	entities := make(map[string][]common.Entity, len(files))
+	for _, file := range files {
+		if err := checkUnchanged(file, hashes); err != nil {
+			return nil, err
+		}
+
+		fileEntities, err := common.MetadataEntities(file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine the entities defined by '%s': %w", file, err)
+		}
+		entities[file] = fileEntities
+
+		display.Debug("entities defined by %s: %d", file, len(fileEntities))
+	}
+
+	kept, err := keptEntities(trackedPaths, files, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	remover, err := common.NewEntityRemover(urls.APIURL, opts.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		display.Step("Removing metadata of %s", filepath.Base(file))
+
+		for _, entity := range entities[file] {
+			if kept[entity] {
+				display.Info("Keeping %s, it is also defined by another ingested file", entity)
+				continue
+			}
+			if opts.DryRun {
+				display.Info("Would remove %s", entity)
+				continue
+			}
+
+			found, err := remover.Remove(ctx, entity)
+			if err != nil {
+				return nil, fmt.Errorf("error removing metadata of '%s': %w", file, err)
+			}
+			if !found {
+				display.Warn("%s is not in the environment", entity)
+				continue
+			}
+			removed++
+		}
+
+		if opts.DryRun {
+			continue
+		}
+
+		if err := db.DeleteIngestedFile(opts.Name, file); err != nil {
+			return nil, fmt.Errorf("error deleting ingested file record: %w", err)
+		}
+
+		display.Debug("deleted ingested file record: %s", file)
+	}
+
+	if opts.DryRun {
+		display.Done("Dry run finished, nothing was removed from environment %s", opts.Name)
+		return env, nil
+	}
+
+	display.Done("Removed %d entities of %d file(s) from environment %s", removed, len(files), opts.Name)
+
+	return env, nil
+}
+
+// selectTrackedFiles returns the tracked files matching paths: a file path matches itself, a directory
+// matches every tracked file below it and an archive matches every tracked entry of it. Every path must match
+// at least one tracked file.
+func selectTrackedFiles(tracked, paths []string) ([]string, error) {
+	var files []string
+	selected := map[string]bool{}
+
+	for _, p := range paths {
+		absPath, err := filepath.Abs(p)
+		if err != nil {
+			return nil, fmt.Errorf("error finding absolute path for given path '%s': %w", p, err)
+		}
+
+		matched := false
+		for _, file := range tracked {
+			if file != absPath && !strings.HasPrefix(file, absPath+string(filepath.Separator)) && !strings.HasPrefix(file, absPath+common.ArchiveEntrySeparator) {
+				continue
+			}
+			matched = true
+			if !selected[file] {
+				selected[file] = true
+				files = append(files, file)
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no file ingested from '%s' is tracked for this environment", p)
+		}
+	}
+
+	return files, nil
+}
+
+// checkUnchanged returns an error when the content of file differs from its hash in hashes, the content it was
+// ingested with, since the entities it defines in the environment cannot be determined from it then. A file
+// without a recorded hash is assumed to be unchanged, with a warning.
+func checkUnchanged(file string, hashes map[string]string) error {
+	recorded, ok := hashes[file]
+	if !ok {
+		display.Warn("No content hash is recorded for %s, assuming it did not change since it was ingested", file)
+		return nil
+	}
+
+	hash, err := common.ContentHash(file)
+	if err != nil {
+		return fmt.Errorf("cannot read '%s': %w", file, err)
+	}
+	if hash != recorded {
+		return fmt.Errorf("'%s' changed since it was ingested, so the entities it defines in the environment cannot be determined from it: restore the ingested version, or populate it again, before removing it", file)
+	}
+	return nil
+}
+
+// keptEntities returns the entities defined by the tracked files that are not being removed. The entities of
+// the examples are read from their embedded copies. An error is returned when a tracked file is missing or
+// cannot be parsed, or an example has no embedded copy, since the entities it shares with the removed files
+// could not be kept. A tracked file that changed since it was ingested, according to hashes, is read as it is
+// now, with a warning.
+func keptEntities(tracked, removing []string, hashes map[string]string) (map[common.Entity]bool, error) {
+	kept := map[common.Entity]bool{}
+	for _, file := range tracked {
+		if slices.Contains(removing, file) {
+			continue
+		}
+
+		var entities []common.Entity
+		var err error
+		if strings.Contains(file, "://") {
+			entities, err = common.ExampleEntities(file)
+			if err != nil {
+				return nil, fmt.Errorf("cannot determine the entities defined by the ingested example '%s', which must be kept: %w", file, err)
+			}
+		} else {
+			entities, err = common.MetadataEntities(file)
+			if err != nil {
+				return nil, fmt.Errorf("cannot determine the entities defined by '%s', which is still ingested and whose entities must be kept, restore it before removing other files: %w", file, err)
+			}
+			if err := checkUnchanged(file, hashes); err != nil {
+				display.Warn("%s changed since it was ingested, only the entities it defines now are kept", file)
+			}
+		}
+
+		for _, entity := range entities {
+			kept[entity] = true
+		}
+	}
+	return kept, nil
+}
+
+// Validate checks UnpopulateOpts and verifies that the environment exists.
+func (u *UnpopulateOpts) Validate() error {
+	display.Debug("name: %s", u.Name)
+	display.Debug("paths: %+v", u.Paths)
+	display.Debug("dryRun: %v", u.DryRun)
+	display.Debug("token set: %v", u.Token != "")
+
+	if len(u.Paths) == 0 {
+		return fmt.Errorf("at least one path is required")
+	}
+
+	if err := EnsureEnvironmentExists(u.Name); err != nil {
+		return fmt.Errorf("no environment with name '%s' exists: %w", u.Name, err)
+	}
+
+	return nil
+}
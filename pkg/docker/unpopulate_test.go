@@ -0,0 +1,148 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/EPOS-ERIC/epos-opensource/common"
+)
+
+func TestSelectTrackedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	tracked := []string{
+		filepath.Join(tmpDir, "data", "a.ttl"),
+		filepath.Join(tmpDir, "data", "sub", "b.ttl"),
+		filepath.Join(tmpDir, "data-old", "c.ttl"),
+		"https://example.org/example.ttl",
+		filepath.Join(tmpDir, "delivery.zip") + "!/stations/d.ttl",
+	}
+
+	tests := []struct {
+		name    string
+		paths   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "Single file",
+			paths: []string{filepath.Join(tmpDir, "data", "a.ttl")},
+			want:  []string{tracked[0]},
+		},
+		{
+			name:  "Directory matches nested files only",
+			paths: []string{filepath.Join(tmpDir, "data")},
+			want:  []string{tracked[0], tracked[1]},
+		},
+		{
+			name:  "Overlapping paths are selected once",
+			paths: []string{filepath.Join(tmpDir, "data", "sub"), filepath.Join(tmpDir, "data")},
+			want:  []string{tracked[1], tracked[0]},
+		},
+		{
+			name:  "Archive matches its entries",
+			paths: []string{filepath.Join(tmpDir, "delivery.zip")},
+			want:  []string{tracked[4]},
+		},
+		{
+			name:    "Untracked path",
+			paths:   []string{filepath.Join(tmpDir, "other")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectTrackedFiles(tracked, tt.paths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectTrackedFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectTrackedFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeptEntities(t *testing.T) {
+	tmpDir := t.TempDir()
+	shared := filepath.Join(tmpDir, "shared.ttl")
+	removing := filepath.Join(tmpDir, "removing.ttl")
+	content := `<https://example.org/org/1> a <http://schema.org/Organization> .
+`
+	for _, path := range []string{shared, removing} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		tracked []string
+		want    map[common.Entity]bool
+		wantErr bool
+	}{
+		{
+			name:    "Entities of the other files are kept",
+			tracked: []string{shared, removing},
+			want:    map[common.Entity]bool{{Kind: "organization", UID: "https://example.org/org/1"}: true},
+		},
+		{
+			name:    "Missing tracked file",
+			tracked: []string{shared, removing, filepath.Join(tmpDir, "missing.ttl")},
+			wantErr: true,
+		},
+		{
+			name:    "Removed files are not kept",
+			tracked: []string{removing},
+			want:    map[common.Entity]bool{},
+		},
+		{
+			name:    "Example without an embedded copy",
+			tracked: []string{removing, "https://example.org/example.ttl"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := keptEntities(tt.tracked, []string{removing}, map[string]string{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("keptEntities() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keptEntities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.ttl")
+	if err := os.WriteFile(path, []byte("<https://example.org/a> a <http://schema.org/Person> .\n"), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	hash, err := common.ContentHash(path)
+	if err != nil {
+		t.Fatalf("ContentHash() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		hashes  map[string]string
+		wantErr bool
+	}{
+		{name: "Unchanged", hashes: map[string]string{path: hash}},
+		{name: "No recorded hash", hashes: map[string]string{}},
+		{name: "Changed", hashes: map[string]string{path: "0123"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkUnchanged(path, tt.hashes); (err != nil) != tt.wantErr {
+				t.Fatalf("checkUnchanged() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@
 package docker
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/EPOS-ERIC/epos-opensource/db"
@@ -20,6 +22,12 @@ type UpdateOpts struct {
 	Force bool
 	// Reset config to embedded defaults. Cannot be used with NewConfig
 	Reset bool
+	// Make the ingestor fetch the base ontologies from GitHub instead of uploading the embedded copies. Only used with Force
+	RemoteOntologies bool
+	// Directory the environment is backed up to before its database is removed, see Backup. Only used with Force
+	BackupDir string
+	// How long to wait for the services to become ready before initializing the ontologies. Defaults to DefaultReadyTimeout. Only used with Force
+	ReadyTimeout time.Duration
 	// Name of the environment to update (required)
 	OldEnvName string
 	// New configuration to apply. If nil, preserves existing config
@@ -81,6 +89,11 @@ func Update(opts UpdateOpts) (*Env, error) {
 	// If force is set do a docker compose down on the original env
 	if opts.Force {
 		display.Info("Force flag enabled: stopping old environment with volumes")
+
+		if err := backupBefore(opts.OldEnvName, opts.BackupDir); err != nil {
+			return nil, err
+		}
+
 		display.Step("Stopping old environment")
 
 		if err := downStack(&oldConfig, true); err != nil {
@@ -123,7 +136,12 @@ func Update(opts UpdateOpts) (*Env, error) {
 	if opts.Force {
 		display.Debug("force update: repopulating base ontologies and clearing ingested tracking")
 
-		if err := common.PopulateOntologies(urls.APIURL); err != nil {
+		if err := waitReady(context.Background(), opts.NewConfig, opts.ReadyTimeout); err != nil {
+			display.Error("The environment did not become ready: %v", err)
+			return handleFailure("environment not ready: %w", err)
+		}
+
+		if err := common.PopulateOntologies(urls.APIURL, opts.NewConfig.Ontologies, opts.RemoteOntologies); err != nil {
 			display.Error("error initializing the ontologies in the environment: %v", err)
 			return handleFailure("error initializing the ontologies in the environment: %w", err)
 		}
@@ -149,12 +167,19 @@ func (u *UpdateOpts) Validate() error {
 	display.Debug("pullImages: %v", u.PullImages)
 	display.Debug("force: %v", u.Force)
 	display.Debug("reset: %v", u.Reset)
+	display.Debug("remoteOntologies: %v", u.RemoteOntologies)
+	display.Debug("backupDir: %s", u.BackupDir)
+	display.Debug("readyTimeout: %v", u.ReadyTimeout)
 	display.Debug("newConfig: %+v", u.NewConfig)
 
 	if u.OldEnvName == "" {
 		return fmt.Errorf("name is required")
 	}
 
+	if u.ReadyTimeout < 0 {
+		return fmt.Errorf("ready timeout must not be negative")
+	}
+
 	if err := validate.Name(u.OldEnvName); err != nil {
 		return fmt.Errorf("'%s' is an invalid name for an environment: %w", u.OldEnvName, err)
 	}
@@ -31,6 +31,11 @@ func TestDeployOpts_Validate(t *testing.T) {
 			opts:    DeployOpts{Config: invalidNameConfig},
 			wantErr: true,
 		},
+		{
+			name:    "Too many parallel seed uploads returns error",
+			opts:    DeployOpts{Config: validConfig, SeedParallel: 21},
+			wantErr: true,
+		},
 		{
 			name:    "Valid opts",
 			opts:    DeployOpts{Config: validConfig},
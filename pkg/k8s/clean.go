@@ -84,6 +84,12 @@ func Clean(opts CleanOpts) (*Env, error) {
 		}
 	}
 
+	display.Debug("clearing ingested files tracking")
+
+	if err := clearIngestedFiles(opts.Name, opts.Context); err != nil {
+		return nil, fmt.Errorf("failed to clear ingested files tracking: %w", err)
+	}
+
 	display.Done("Cleaned environment: %s", opts.Name)
 
 	return env, nil
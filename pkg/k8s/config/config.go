@@ -40,7 +40,8 @@ func GetDefaultConfigBytes() []byte {
 	return out
 }
 
-// LoadConfig loads a K8s configuration from a YAML file.
+// LoadConfig loads a K8s configuration from a YAML file. A relative seed manifest path is resolved against the
+// directory of the file.
 func LoadConfig(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -53,6 +54,10 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("error unmarshalling config yaml: %w", err)
 	}
 
+	if config.SeedManifest != "" && !filepath.IsAbs(config.SeedManifest) {
+		config.SeedManifest = filepath.Join(filepath.Dir(path), config.SeedManifest)
+	}
+
 	return &config, nil
 }
 
@@ -375,6 +380,11 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Ontologies validation
+	if err := common.ValidateOntologies(c.Ontologies); err != nil {
+		return err
+	}
+
 	// Monitoring validation
 	if c.Monitoring.Enabled {
 		if c.Monitoring.URL == "" {
@@ -408,6 +418,9 @@ func (c *Config) Validate() error {
 }
 
 // AsValues converts Config into Helm chart values.
+//
+// The values also hold ontology_sources, the configured ontologies (or the defaults) with the URL the ontology
+// populator job fetches each of them from, since the job cannot upload the copies embedded in the binary.
 func (c *Config) AsValues() (*chartutil.Values, error) {
 	configYAML, err := yaml.Marshal(c)
 	if err != nil {
@@ -419,6 +432,12 @@ func (c *Config) AsValues() (*chartutil.Values, error) {
 		return nil, fmt.Errorf("parse chart values from YAML: %w", err)
 	}
 
+	var sources []any
+	for _, ont := range common.OntologiesOrDefault(c.Ontologies) {
+		sources = append(sources, map[string]any{"name": ont.Name, "type": ont.Type, "url": ont.SourceURL()})
+	}
+	values["ontology_sources"] = sources
+
 	return &values, nil
 }
 
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/EPOS-ERIC/epos-opensource/common"
 )
 
 const (
@@ -215,6 +218,19 @@ func TestConfigValidate_DefaultConfigIsValid(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_DefaultOntologies(t *testing.T) {
+	cfg := GetDefaultConfig()
+
+	if !reflect.DeepEqual(cfg.Ontologies, common.DefaultOntologies()) {
+		t.Fatalf("GetDefaultConfig().Ontologies = %+v, want %+v", cfg.Ontologies, common.DefaultOntologies())
+	}
+
+	cfg.Ontologies = append(cfg.Ontologies, common.Ontology{Name: "MY-SHAPES", Type: common.OntologyTypeBase})
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("Validate() error = nil, want error for an ontology without url that is not embedded")
+	}
+}
+
 func TestConfigValidate_DefaultCreateNamespaceEnabled(t *testing.T) {
 	cfg := GetDefaultConfig()
 
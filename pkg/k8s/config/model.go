@@ -4,17 +4,19 @@ import "github.com/EPOS-ERIC/epos-opensource/common"
 
 // Config represents the full Kubernetes environment values schema.
 type Config struct {
-	Name               string           `yaml:"name"`
-	Domain             string           `yaml:"domain"`
-	Protocol           string           `yaml:"protocol"`
-	URLPrefixNamespace bool             `yaml:"url_prefix_namespace"`
-	CreateNamespace    bool             `yaml:"create_namespace"`
-	Components         Components       `yaml:"components"`
-	Jobs               Jobs             `yaml:"jobs"`
-	Monitoring         Monitoring       `yaml:"monitoring"`
-	ImagePullSecrets   ImagePullSecrets `yaml:"image_pull_secrets"`
-	CertManagerIssuer  string           `yaml:"cert_manager_issuer"`
-	Images             common.Images    `yaml:"images"`
+	Name               string            `yaml:"name"`
+	Domain             string            `yaml:"domain"`
+	Protocol           string            `yaml:"protocol"`
+	URLPrefixNamespace bool              `yaml:"url_prefix_namespace"`
+	CreateNamespace    bool              `yaml:"create_namespace"`
+	Components         Components        `yaml:"components"`
+	Ontologies         []common.Ontology `yaml:"ontologies"`
+	SeedManifest       string            `yaml:"seed_manifest"`
+	Jobs               Jobs              `yaml:"jobs"`
+	Monitoring         Monitoring        `yaml:"monitoring"`
+	ImagePullSecrets   ImagePullSecrets  `yaml:"image_pull_secrets"`
+	CertManagerIssuer  string            `yaml:"cert_manager_issuer"`
+	Images             common.Images     `yaml:"images"`
 }
 
 // TLS configures ingress TLS behavior.
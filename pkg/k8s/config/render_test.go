@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/k8s/config"
 )
 
@@ -277,6 +278,24 @@ func TestConfigRender(t *testing.T) {
 				"templates/dataportal.yaml": {"cert-manager.io/cluster-issuer:"},
 			},
 		},
+		{
+			name: "configured ontologies render in the ontology populator job",
+			mutate: func(cfg *config.Config) {
+				cfg.Name = "test-ontologies"
+				cfg.Ontologies = append(cfg.Ontologies, common.Ontology{
+					Name: "MY-SHAPES",
+					Type: common.OntologyTypeBase,
+					URL:  "https://example.com/shapes.ttl?ref=v1",
+				})
+			},
+			wantContains: map[string][]string{
+				"templates/ontology-populator-job.yaml": {
+					`register_ontology 'EPOS-DCAT-AP-V1' 'BASE' 'https%3A%2F%2Fraw.githubusercontent.com%2Fepos-eu%2FEPOS-DCAT-AP%2F`,
+					`register_ontology 'EDM-TO-DCAT-AP' 'MAPPING' `,
+					`register_ontology 'MY-SHAPES' 'BASE' 'https%3A%2F%2Fexample.com%2Fshapes.ttl%3Fref%3Dv1'`,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
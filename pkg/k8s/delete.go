@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/db"
 	"github.com/EPOS-ERIC/epos-opensource/display"
 	"golang.org/x/sync/errgroup"
 	"helm.sh/helm/v3/pkg/action"
@@ -71,6 +72,12 @@ func Delete(opts DeleteOpts) error {
 			}
 
 			display.Done("Deleted namespace: %s", envName)
+
+			// the ingested files ConfigMap was deleted with the namespace, only the local cache is left
+			if err := db.DeleteK8sIngestedFilesByEnvironment(opts.Context, envName); err != nil {
+				return fmt.Errorf("failed to clear ingested files tracking: %w", err)
+			}
+
 			display.Done("Deleted environment: %s", envName)
 
 			return nil
@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -20,10 +21,17 @@ type DeployOpts struct {
 	Timeout time.Duration
 	// Required. Environment configuration used to build deployment values.
 	Config *config.Config
+	// Optional. Do not populate the environment from the seed manifest of its config.
+	SkipSeed bool
+	// Optional. Number of parallel uploads when populating the environment from its seed manifest (1-20); defaults to 1.
+	SeedParallel int
 }
 
 // Deploy installs the EPOS Helm chart and returns the deployed environment metadata.
-func Deploy(opts DeployOpts) (*Env, error) {
+// When the config has a seed manifest the environment is then populated from it, unless SkipSeed is set.
+// Cancelling ctx aborts the install, and stops the seeding gracefully like Populate. If seeding fails the
+// deployed environment is kept and returned along with the error.
+func Deploy(ctx context.Context, opts DeployOpts) (*Env, error) {
 	if err := opts.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid deploy parameters: %w", err)
 	}
@@ -81,7 +89,7 @@ func Deploy(opts DeployOpts) (*Env, error) {
 
 	display.Debug("running helm install")
 
-	rel, err := client.Run(chart, values.AsMap())
+	rel, err := client.RunWithContext(ctx, chart, values.AsMap())
 	if err != nil {
 		return nil, fmt.Errorf("failed to install helm chart: %w", err)
 	}
@@ -95,18 +103,41 @@ func Deploy(opts DeployOpts) (*Env, error) {
 
 	display.Done("Deployed environment: %s", opts.Config.Name)
 
+	if opts.Config.SeedManifest != "" && !opts.SkipSeed {
+		display.Step("Seeding environment %s from manifest %s", opts.Config.Name, opts.Config.SeedManifest)
+
+		_, err := Populate(ctx, PopulateOpts{
+			Name:     opts.Config.Name,
+			Context:  opts.Context,
+			Parallel: opts.SeedParallel,
+			Manifest: opts.Config.SeedManifest,
+		})
+		if err != nil {
+			return env, fmt.Errorf("environment deployed, but seeding it from manifest '%s' failed: %w", opts.Config.SeedManifest, err)
+		}
+	}
+
 	return env, nil
 }
 
 // Validate checks DeployOpts and resolves required deployment preconditions.
 func (d *DeployOpts) Validate() error {
 	display.Debug("context: %s", d.Context)
+	display.Debug("skipSeed: %v", d.SkipSeed)
+	display.Debug("seedParallel: %d", d.SeedParallel)
 	display.Debug("config: %+v", d.Config)
 
 	if d.Config == nil {
 		return fmt.Errorf("config is required")
 	}
 
+	if d.SeedParallel == 0 {
+		d.SeedParallel = 1
+	}
+	if d.SeedParallel < 1 || d.SeedParallel > 20 {
+		return fmt.Errorf("seed parallel must be between 1 and 20")
+	}
+
 	resolvedTimeout, err := resolveCommandTimeout(d.Timeout)
 	if err != nil {
 		return fmt.Errorf("invalid timeout: %w", err)
@@ -122,6 +153,13 @@ func (d *DeployOpts) Validate() error {
 		return fmt.Errorf("'%s' is an invalid name for an environment: %w", d.Config.Name, err)
 	}
 
+	// fail before deploying rather than after when the seed manifest is invalid
+	if d.Config.SeedManifest != "" && !d.SkipSeed {
+		if _, err := common.LoadDatasetManifest(d.Config.SeedManifest); err != nil {
+			return fmt.Errorf("invalid seed manifest: %w", err)
+		}
+	}
+
 	if d.Context == "" {
 		context, err := common.GetCurrentKubeContext()
 		if err != nil {
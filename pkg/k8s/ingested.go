@@ -0,0 +1,359 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/command"
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/db"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ingestedFilesConfigMap is the ConfigMap, in the namespace of an environment, recording the files ingested
+	// into it so that everyone using the cluster sees the same history. When the records do not fit in it they
+	// are continued in ConfigMaps with the same name and the suffix -1, -2...
+	ingestedFilesConfigMap = "epos-ingested-files"
+	// ingestedFilesKey is the ConfigMap data key holding the YAML list of ingested files
+	ingestedFilesKey = "files.yaml"
+	// ingestedFilesShardBytes is the maximum size of the records stored in one ConfigMap, below the 1 MiB limit
+	// of Kubernetes to leave room for the rest of the ConfigMap
+	ingestedFilesShardBytes = 900 * 1024
+	// ingestedFilesUpdateAttempts is how many times an update of the ConfigMap is attempted when it is modified concurrently
+	ingestedFilesUpdateAttempts = 3
+)
+
+// IngestedFile is the record of a file ingested into a K8s environment.
+type IngestedFile struct {
+	// Absolute path of the file on the machine it was ingested from, or URL of an example
+	Path string `yaml:"path"`
+	// Hex encoded SHA-256 of the file content. Empty for examples
+	ContentHash string `yaml:"contentHash,omitempty"`
+	// Size of the file content in bytes. Zero for examples
+	SizeBytes int64 `yaml:"sizeBytes,omitempty"`
+	// Time of the last ingestion of the file
+	IngestedAt time.Time `yaml:"ingestedAt"`
+}
+
+// configMap is the subset of a Kubernetes ConfigMap read and written by kubectl.
+type configMap struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   configMapMetadata `json:"metadata"`
+	Data       map[string]string `json:"data,omitempty"`
+}
+
+// configMapList is the subset of a list of Kubernetes ConfigMaps read by kubectl.
+type configMapList struct {
+	Items []configMap `json:"items"`
+}
+
+type configMapMetadata struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+}
+
+// GetIngestedFiles returns the files ingested into an environment, most recent first. The records are read
+// from the ConfigMap in the environment namespace and the local cache is refreshed with them.
+func GetIngestedFiles(name, context string) ([]IngestedFile, error) {
+	files, _, err := readIngestedFiles(name, context)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cacheIngestedFiles(name, context, files); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// GetCachedIngestedFiles returns the files ingested into an environment, most recent first, as last seen in
+// the ConfigMap of the environment, without contacting the cluster.
+func GetCachedIngestedFiles(name, context string) ([]IngestedFile, error) {
+	rows, err := db.GetK8sIngestedFilesByEnvironment(context, name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cached ingested files for environment '%s': %w", name, err)
+	}
+
+	files := make([]IngestedFile, 0, len(rows))
+	for _, row := range rows {
+		file := IngestedFile{Path: row.FilePath}
+		if row.ContentHash != nil {
+			file.ContentHash = *row.ContentHash
+		}
+		if row.SizeBytes != nil {
+			file.SizeBytes = *row.SizeBytes
+		}
+		if row.IngestedAt != nil {
+			file.IngestedAt = *row.IngestedAt
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// recordIngestedFiles adds the given files to the records of an environment, replacing the records of files
+// ingested before, and refreshes the local cache.
+func recordIngestedFiles(name, context string, ingested []common.IngestedFile) error {
+	now := time.Now().UTC()
+
+	return updateIngestedFiles(name, context, func(files []IngestedFile) []IngestedFile {
+		byPath := make(map[string]IngestedFile, len(files)+len(ingested))
+		for _, file := range files {
+			byPath[file.Path] = file
+		}
+		for _, file := range ingested {
+			byPath[file.Path] = IngestedFile{
+				Path:        file.Path,
+				ContentHash: file.ContentHash,
+				SizeBytes:   file.SizeBytes,
+				IngestedAt:  now,
+			}
+		}
+
+		updated := make([]IngestedFile, 0, len(byPath))
+		for _, file := range byPath {
+			updated = append(updated, file)
+		}
+		return updated
+	})
+}
+
+// clearIngestedFiles removes every record of an environment, in the cluster and in the local cache.
+func clearIngestedFiles(name, context string) error {
+	return updateIngestedFiles(name, context, func([]IngestedFile) []IngestedFile {
+		return nil
+	})
+}
+
+// updateIngestedFiles applies update to the records of an environment and writes them back. Every ConfigMap is
+// replaced only if nobody modified it in the meantime; otherwise the records are read again and update is
+// applied again, up to ingestedFilesUpdateAttempts times, which also rewrites the ConfigMaps written before the
+// conflict.
+func updateIngestedFiles(name, context string, update func([]IngestedFile) []IngestedFile) error {
+	var err error
+	for attempt := 1; attempt <= ingestedFilesUpdateAttempts; attempt++ {
+		var files []IngestedFile
+		var resourceVersions map[string]string
+		files, resourceVersions, err = readIngestedFiles(name, context)
+		if err != nil {
+			return err
+		}
+
+		files = update(files)
+		sortIngestedFiles(files)
+
+		if err = writeIngestedFiles(name, context, files, resourceVersions); err == nil {
+			return cacheIngestedFiles(name, context, files)
+		}
+
+		display.Debug("updating ingested files of %s failed (attempt %d/%d): %v", name, attempt, ingestedFilesUpdateAttempts, err)
+	}
+
+	return fmt.Errorf("error recording ingested files for environment '%s': %w", name, err)
+}
+
+// readIngestedFiles reads the records of an environment from its ConfigMaps. It also returns the resource
+// version of every ConfigMap holding records, keyed by name, empty when none exists yet.
+func readIngestedFiles(name, context string) ([]IngestedFile, map[string]string, error) {
+	cmd := newKubectlCommand(".", context, "get", "configmap", "-n", name, "-o", "json")
+	out, err := command.RunCommand(cmd, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading ingested files of environment '%s': %w", name, err)
+	}
+
+	var list configMapList
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		return nil, nil, fmt.Errorf("invalid ConfigMaps in namespace '%s': %w", name, err)
+	}
+
+	var files []IngestedFile
+	resourceVersions := map[string]string{}
+	for _, cm := range list.Items {
+		if !isIngestedFilesConfigMap(cm.Metadata.Name) {
+			continue
+		}
+
+		shard, err := decodeIngestedFiles(cm.Data[ingestedFilesKey])
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s ConfigMap in namespace '%s': %w", cm.Metadata.Name, name, err)
+		}
+		files = append(files, shard...)
+		resourceVersions[cm.Metadata.Name] = cm.Metadata.ResourceVersion
+	}
+
+	if len(resourceVersions) == 0 {
+		display.Debug("no ingested files recorded for environment %s yet", name)
+	}
+	sortIngestedFiles(files)
+
+	return files, resourceVersions, nil
+}
+
+// writeIngestedFiles writes files to the ConfigMaps of an environment, split with shardIngestedFiles, creating
+// the ConfigMaps that do not exist in resourceVersions and replacing the others. Replacing fails if the
+// ConfigMap was modified since its resource version. The ConfigMaps left without records are deleted.
+func writeIngestedFiles(name, context string, files []IngestedFile, resourceVersions map[string]string) error {
+	shards, err := shardIngestedFiles(files, ingestedFilesShardBytes)
+	if err != nil {
+		return err
+	}
+
+	written := map[string]bool{}
+	for i, data := range shards {
+		cmName := ingestedFilesConfigMapName(i)
+		written[cmName] = true
+
+		manifest, err := json.Marshal(configMap{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Metadata: configMapMetadata{
+				Name:            cmName,
+				Namespace:       name,
+				ResourceVersion: resourceVersions[cmName],
+				Labels:          map[string]string{"app.kubernetes.io/managed-by": "epos-opensource"},
+			},
+			Data: map[string]string{ingestedFilesKey: data},
+		})
+		if err != nil {
+			return fmt.Errorf("error encoding %s ConfigMap: %w", cmName, err)
+		}
+
+		verb := "create"
+		if resourceVersions[cmName] != "" {
+			verb = "replace"
+		}
+
+		if err := runKubectlWithInput(".", true, context, string(manifest), verb, "-f", "-", "-n", name); err != nil {
+			return err
+		}
+	}
+
+	for cmName := range resourceVersions {
+		if written[cmName] {
+			continue
+		}
+		cmd := newKubectlCommand(".", context, "delete", "configmap", cmName, "-n", name, "--ignore-not-found")
+		if _, err := command.RunCommand(cmd, true); err != nil {
+			return fmt.Errorf("error deleting %s ConfigMap: %w", cmName, err)
+		}
+		display.Debug("deleted ConfigMap %s of environment %s", cmName, name)
+	}
+
+	return nil
+}
+
+// shardIngestedFiles encodes files as YAML lists of at most limit bytes each, in order, so that every list fits
+// in a ConfigMap. It always returns at least one list, and an error when a single record exceeds limit.
+func shardIngestedFiles(files []IngestedFile, limit int) ([]string, error) {
+	if len(files) == 0 {
+		return []string{"[]\n"}, nil
+	}
+
+	var shards []string
+	var shard strings.Builder
+	for _, file := range files {
+		record, err := yaml.Marshal([]IngestedFile{file})
+		if err != nil {
+			return nil, fmt.Errorf("error encoding ingested files: %w", err)
+		}
+		if len(record) > limit {
+			return nil, fmt.Errorf("the record of ingested file '%s' is larger than %d bytes", file.Path, limit)
+		}
+		if shard.Len()+len(record) > limit {
+			shards = append(shards, shard.String())
+			shard.Reset()
+		}
+		shard.Write(record)
+	}
+
+	return append(shards, shard.String()), nil
+}
+
+// ingestedFilesConfigMapName returns the name of the ConfigMap holding the shard i of the records.
+func ingestedFilesConfigMapName(i int) string {
+	if i == 0 {
+		return ingestedFilesConfigMap
+	}
+	return fmt.Sprintf("%s-%d", ingestedFilesConfigMap, i)
+}
+
+// isIngestedFilesConfigMap reports whether name is the name of a ConfigMap holding records, as returned by
+// ingestedFilesConfigMapName.
+func isIngestedFilesConfigMap(name string) bool {
+	if name == ingestedFilesConfigMap {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(name, ingestedFilesConfigMap+"-")
+	if !ok {
+		return false
+	}
+	i, err := strconv.Atoi(suffix)
+	return err == nil && i > 0 && ingestedFilesConfigMapName(i) == name
+}
+
+// cacheIngestedFiles replaces the locally cached records of an environment.
+func cacheIngestedFiles(name, context string, files []IngestedFile) error {
+	if err := db.DeleteK8sIngestedFilesByEnvironment(context, name); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := db.InsertK8sIngestedFile(context, name, file.Path, file.ContentHash, file.SizeBytes, file.IngestedAt); err != nil {
+			return err
+		}
+	}
+
+	display.Debug("cached ingested files of environment %s: %d", name, len(files))
+
+	return nil
+}
+
+// decodeIngestedFiles parses the YAML list of ingested files stored in the ConfigMap.
+func decodeIngestedFiles(data string) ([]IngestedFile, error) {
+	var files []IngestedFile
+	if err := yaml.Unmarshal([]byte(data), &files); err != nil {
+		return nil, err
+	}
+
+	sortIngestedFiles(files)
+
+	return files, nil
+}
+
+// sortIngestedFiles sorts files by ingestion time, most recent first, then by path.
+func sortIngestedFiles(files []IngestedFile) {
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].IngestedAt.Equal(files[j].IngestedAt) {
+			return files[i].IngestedAt.After(files[j].IngestedAt)
+		}
+		return files[i].Path < files[j].Path
+	})
+}
+
+// ingestedHashes returns the recorded content hashes of the files ingested into an environment, keyed by path.
+func ingestedHashes(name, context string) (map[string]string, error) {
+	files, err := GetIngestedFiles(name, context)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]string, len(files))
+	for _, file := range files {
+		if file.ContentHash != "" {
+			hashes[file.Path] = file.ContentHash
+		}
+	}
+
+	return hashes, nil
+}
@@ -0,0 +1,145 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDecodeIngestedFiles(t *testing.T) {
+	older := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		data    string
+		want    []IngestedFile
+		wantErr bool
+	}{
+		{
+			name: "Empty data",
+			data: "",
+			want: nil,
+		},
+		{
+			name: "Sorts most recent first, then by path",
+			data: `
+- path: /data/b.ttl
+  contentHash: abc
+  sizeBytes: 12
+  ingestedAt: 2026-01-02T10:00:00Z
+- path: https://example.org/example.ttl
+  ingestedAt: 2026-01-02T11:00:00Z
+- path: /data/a.ttl
+  ingestedAt: 2026-01-02T10:00:00Z
+`,
+			want: []IngestedFile{
+				{Path: "https://example.org/example.ttl", IngestedAt: newer},
+				{Path: "/data/a.ttl", IngestedAt: older},
+				{Path: "/data/b.ttl", ContentHash: "abc", SizeBytes: 12, IngestedAt: older},
+			},
+		},
+		{
+			name:    "Invalid data",
+			data:    "path: [",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeIngestedFiles(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeIngestedFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeIngestedFiles() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIngestedFilesRoundTrip(t *testing.T) {
+	files := []IngestedFile{
+		{Path: "/data/a.ttl", ContentHash: "abc", SizeBytes: 12, IngestedAt: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)},
+		{Path: "https://example.org/example.ttl", IngestedAt: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+	}
+
+	data, err := yaml.Marshal(files)
+	if err != nil {
+		t.Fatalf("failed to encode ingested files: %v", err)
+	}
+
+	got, err := decodeIngestedFiles(string(data))
+	if err != nil {
+		t.Fatalf("failed to decode ingested files: %v", err)
+	}
+	if !reflect.DeepEqual(got, files) {
+		t.Errorf("decodeIngestedFiles() = %+v, want %+v", got, files)
+	}
+}
+
+func TestShardIngestedFiles(t *testing.T) {
+	var files []IngestedFile
+	for i := range 50 {
+		files = append(files, IngestedFile{
+			Path:        fmt.Sprintf("/data/stations/file-%03d.ttl", i),
+			ContentHash: strings.Repeat("a", 64),
+			SizeBytes:   int64(i),
+			IngestedAt:  time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+		})
+	}
+
+	shards, err := shardIngestedFiles(files, 1024)
+	if err != nil {
+		t.Fatalf("shardIngestedFiles() error = %v", err)
+	}
+	if len(shards) < 2 {
+		t.Fatalf("shardIngestedFiles() = %d shards, want several", len(shards))
+	}
+
+	var got []IngestedFile
+	for _, shard := range shards {
+		if len(shard) > 1024 {
+			t.Errorf("shard of %d bytes exceeds the limit", len(shard))
+		}
+		decoded, err := decodeIngestedFiles(shard)
+		if err != nil {
+			t.Fatalf("decodeIngestedFiles() error = %v", err)
+		}
+		got = append(got, decoded...)
+	}
+	sortIngestedFiles(got)
+	if !reflect.DeepEqual(got, files) {
+		t.Errorf("shards hold %d records, want %d", len(got), len(files))
+	}
+
+	if shards, err := shardIngestedFiles(nil, 1024); err != nil || len(shards) != 1 {
+		t.Errorf("shardIngestedFiles(nil) = %q, %v, want a single empty shard", shards, err)
+	}
+	if _, err := shardIngestedFiles(files[:1], 10); err == nil {
+		t.Error("shardIngestedFiles() error = nil, want error for a record larger than the limit")
+	}
+}
+
+func TestIsIngestedFilesConfigMap(t *testing.T) {
+	tests := map[string]bool{
+		"epos-ingested-files":    true,
+		"epos-ingested-files-1":  true,
+		"epos-ingested-files-12": true,
+		"epos-ingested-files-0":  false,
+		"epos-ingested-files-01": false,
+		"epos-ingested-files-x":  false,
+		"epos-config":            false,
+	}
+
+	for name, want := range tests {
+		if got := isIngestedFilesConfigMap(name); got != want {
+			t.Errorf("isIngestedFilesConfigMap(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/common"
+	"github.com/EPOS-ERIC/epos-opensource/display"
+)
+
+// AddOntologyOpts defines inputs for AddOntology.
+type AddOntologyOpts struct {
+	// Required. name of the environment
+	Name string
+	// Optional. Kubernetes context to use; defaults to the current kubectl context when unset.
+	Context string
+	// Optional. Timeout for the Helm upgrade storing the updated config; defaults when unset.
+	Timeout time.Duration
+	// Required. ontology to register and add to the environment config
+	Ontology common.Ontology
+	// Optional. make the ingestor fetch an embedded ontology from GitHub instead of uploading the embedded copy
+	RemoteOntologies bool
+}
+
+// ReloadOntologiesOpts defines inputs for ReloadOntologies.
+type ReloadOntologiesOpts struct {
+	// Required. name of the environment
+	Name string
+	// Optional. Kubernetes context to use; defaults to the current kubectl context when unset.
+	Context string
+	// Optional. make the ingestor fetch the embedded ontologies from GitHub instead of uploading the embedded copies
+	RemoteOntologies bool
+}
+
+// ListOntologies returns the ontologies configured for a K8s environment, or the default ontologies when its
+// config has none.
+func ListOntologies(name, context string) ([]common.Ontology, error) {
+	env, err := GetEnv(name, context)
+	if err != nil {
+		return nil, fmt.Errorf("error getting environment: %w", err)
+	}
+
+	return common.OntologiesOrDefault(env.Ontologies), nil
+}
+
+// AddOntology registers an ontology in the ingestor of an existing K8s environment through a port-forward, and
+// adds it to the environment config with a Helm upgrade, so that the ontology populator job registers it again
+// when the environment is reinstalled.
+func AddOntology(opts AddOntologyOpts) (*Env, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid add ontology parameters: %w", err)
+	}
+
+	env, err := GetEnv(opts.Name, opts.Context)
+	if err != nil {
+		return nil, fmt.Errorf("error getting environment: %w", err)
+	}
+
+	ontologies := common.OntologiesOrDefault(env.Ontologies)
+	if slices.ContainsFunc(ontologies, func(o common.Ontology) bool { return o.Name == opts.Ontology.Name }) {
+		return nil, fmt.Errorf("ontology %s is already configured for environment '%s'", opts.Ontology.Name, opts.Name)
+	}
+
+	if err := populateOntologies(opts.Name, opts.Context, []common.Ontology{opts.Ontology}, opts.RemoteOntologies); err != nil {
+		return nil, fmt.Errorf("failed to register ontology %s: %w", opts.Ontology.Name, err)
+	}
+
+	cfg := env.Config
+	cfg.Ontologies = append(slices.Clone(ontologies), opts.Ontology)
+
+	env, err = Update(UpdateOpts{
+		OldEnvName: opts.Name,
+		Context:    opts.Context,
+		Timeout:    opts.Timeout,
+		NewConfig:  &cfg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store environment config: %w", err)
+	}
+
+	display.Done("Added ontology %s to environment: %s", opts.Ontology.Name, opts.Name)
+
+	return env, nil
+}
+
+// ReloadOntologies registers all the ontologies configured for an existing K8s environment in its ingestor
+// again through a port-forward, e.g. after their upstream files changed.
+func ReloadOntologies(opts ReloadOntologiesOpts) (*Env, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid reload ontologies parameters: %w", err)
+	}
+
+	env, err := GetEnv(opts.Name, opts.Context)
+	if err != nil {
+		return nil, fmt.Errorf("error getting environment: %w", err)
+	}
+
+	if err := populateOntologies(opts.Name, opts.Context, env.Ontologies, opts.RemoteOntologies); err != nil {
+		return nil, fmt.Errorf("failed to reload ontologies: %w", err)
+	}
+
+	return env, nil
+}
+
+// populateOntologies registers ontologies in the ingestor of the environment called name through a
+// port-forward.
+func populateOntologies(name, context string, ontologies []common.Ontology, remote bool) error {
+	port, err := common.FindFreePort()
+	if err != nil {
+		return fmt.Errorf("error getting free port: %w", err)
+	}
+
+	display.Debug("selected local port for port-forward: %d", port)
+
+	return ForwardAndRun(name, "ingestor-service", port, 8080, context, func(host string, port int) error {
+		display.Step("Starting port-forward to ingestor-service pod")
+		display.Debug("port-forward ready on %s:%d", host, port)
+
+		url := fmt.Sprintf("http://%s:%d/api/ingestor-service/v1/", host, port)
+		return common.PopulateOntologies(url, ontologies, remote)
+	})
+}
+
+// Validate checks AddOntologyOpts and ensures the target environment exists.
+func (a *AddOntologyOpts) Validate() error {
+	display.Debug("name: %s", a.Name)
+	display.Debug("context: %s", a.Context)
+	display.Debug("timeout: %v", a.Timeout)
+	display.Debug("ontology: %+v", a.Ontology)
+	display.Debug("remoteOntologies: %v", a.RemoteOntologies)
+
+	if err := common.ValidateOntology(a.Ontology); err != nil {
+		return err
+	}
+
+	return validateOntologyEnv(a.Name, &a.Context)
+}
+
+// Validate checks ReloadOntologiesOpts and ensures the target environment exists.
+func (r *ReloadOntologiesOpts) Validate() error {
+	display.Debug("name: %s", r.Name)
+	display.Debug("context: %s", r.Context)
+	display.Debug("remoteOntologies: %v", r.RemoteOntologies)
+
+	return validateOntologyEnv(r.Name, &r.Context)
+}
+
+// validateOntologyEnv resolves context to the current kubectl context when unset and ensures the environment
+// called name exists in it.
+func validateOntologyEnv(name string, context *string) error {
+	if *context == "" {
+		current, err := common.GetCurrentKubeContext()
+		if err != nil {
+			return fmt.Errorf("failed to get current kubectl context: %w", err)
+		}
+
+		*context = current
+	} else if err := EnsureContextExists(*context); err != nil {
+		return fmt.Errorf("K8s context %q is not an available context: %w", *context, err)
+	}
+
+	if err := EnsureEnvironmentExists(name, *context); err != nil {
+		return fmt.Errorf("error validating environment name, no environment with '%s' exists: %w", name, err)
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,29 +17,104 @@ type PopulateOpts struct {
 	Name string
 	// Optional. Kubernetes context to use; defaults to the current kubectl context when unset.
 	Context string
-	// Required. list of directories or ttl files to populate the environment with
+	// Required. list of directories, archives (.zip, .tar.gz) or RDF files (Turtle, N-Triples, JSON-LD or RDF/XML) to populate the environment with
 	TTLDirs []string
 	// Optional. number of parallel uploads to do to the default is 1
 	Parallel int
 	// Optional. weather to populate the examples or not
 	PopulateExamples bool
+	// Optional. make the gateway fetch the examples from GitHub instead of uploading the copies embedded in the binary
+	RemoteExamples bool
+	// Optional. only upload files that are new or whose content changed since they were last ingested
+	Incremental bool
+	// Optional. only check the syntax of the files, without uploading anything. The environment does not need to exist
+	DryRun bool
+	// Optional. how failed uploads are retried. The zero value disables retries
+	Retry common.RetryPolicy
+	// Optional. path of a machine-readable report of the outcome of every file, written even when populate fails
+	ReportFile string
+	// Optional. format of the report written to ReportFile. Required when ReportFile is set
+	ReportFormat common.ReportFormat
+	// Optional. ingestion type, model and mapping of the files. Empty fields use the defaults. Ingestion manifests in the populated directories override them
+	Ingestion common.IngestionSettings
+	// Optional. glob patterns selecting the files ingested from directories. The .eposignore file of every directory is applied too
+	Filter common.PathFilter
+	// Optional. ingest every path even when some files are invalid or fail, instead of stopping at the first path that fails. The failures are summarized at the end
+	KeepGoing bool
+	// Optional. path of a dataset manifest whose datasets are populated after TTLDirs, see common.DatasetManifest
+	Manifest string
+	// Optional. compress uploads with gzip. Files the gateway rejects as an unsupported media type are sent again uncompressed
+	Compress bool
+
+	// manifest is the dataset manifest loaded from Manifest by Validate
+	manifest common.DatasetManifest
 }
 
 // Populate ingests example or user-provided TTL data into an existing K8s environment.
-func Populate(opts PopulateOpts) (*Env, error) {
+// With DryRun set it only checks the syntax of the given files and returns a nil environment.
+// With ReportFile set, a report of the outcome of every file is written once populate ends, even on failure.
+// The ingested files are recorded in a ConfigMap in the environment namespace, see GetIngestedFiles.
+// Cancelling ctx stops the ingestion and closes the port-forward; the files ingested until then are still recorded.
+// With KeepGoing set, every path is ingested even when some fail, and a table of the files that could not be
+// ingested is printed at the end.
+// With Manifest set, the datasets of the manifest are populated after TTLDirs, in their order.
+func Populate(ctx context.Context, opts PopulateOpts) (env *Env, err error) {
 	if err := opts.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid parameters for populate command: %w", err)
 	}
 
-	display.Step("Populating environment %s with %d directories", opts.Name, len(opts.TTLDirs))
+	datasets := opts.datasets()
+	paths := common.DatasetPaths(datasets)
+
+	if opts.DryRun {
+		display.Step("Checking RDF syntax of %d path(s)", len(paths))
+
+		for _, dataset := range datasets {
+			if _, err := common.ValidateTTLPaths(dataset.Paths, opts.Parallel, dataset.Filter()); err != nil {
+				return nil, fmt.Errorf("dry run failed: %w", err)
+			}
+		}
+
+		return nil, nil
+	}
+
+	var report *common.PopulateReport
+	if opts.ReportFile != "" || opts.KeepGoing {
+		report = common.NewPopulateReport(opts.Name)
+	}
+	if opts.ReportFile != "" {
+		defer func() {
+			writeErr := report.WriteFile(opts.ReportFile, opts.ReportFormat)
+			switch {
+			case writeErr == nil:
+				display.Info("Populate report written to %s", opts.ReportFile)
+			case err == nil:
+				err = fmt.Errorf("error writing populate report: %w", writeErr)
+			default:
+				display.Error("error writing populate report: %v", writeErr)
+			}
+		}()
+	}
 
-	env, err := GetEnv(opts.Name, opts.Context)
+	display.Step("Populating environment %s with %d directories", opts.Name, len(paths))
+
+	env, err = GetEnv(opts.Name, opts.Context)
 	if err != nil {
 		return nil, fmt.Errorf("error getting environment: %w", err)
 	}
 
 	display.Debug("loaded environment: %s", env.Name)
 
+	var ingested map[string]string
+	if opts.Incremental {
+		ingested, err = ingestedHashes(opts.Name, opts.Context)
+		if err != nil {
+			return nil, err
+		}
+
+		display.Debug("loaded content hashes of previously ingested files: %d", len(ingested))
+	}
+
 	port, err := common.FindFreePort()
 	if err != nil {
 		return nil, fmt.Errorf("error getting free port: %w", err)
@@ -45,49 +122,105 @@ func Populate(opts PopulateOpts) (*Env, error) {
 
 	display.Debug("selected local port for port-forward: %d", port)
 
+	var allSuccessfulFiles []common.IngestedFile
+
 	// start a port forward locally to the ingestor service and use that to do the populate posts
-	err = ForwardAndRun(opts.Name, "ingestor-service", port, 8080, opts.Context, func(host string, port int) error {
+	populateErr := ForwardAndRun(opts.Name, "ingestor-service", port, 8080, opts.Context, func(host string, port int) error {
 		display.Step("Starting port-forward to ingestor-service pod")
 		display.Debug("port-forward ready on %s:%d", host, port)
 
 		url := fmt.Sprintf("http://%s:%d/api/ingestor-service/v1/", host, port)
 
+		var errs []error
+
 		if opts.PopulateExamples {
 			display.Debug("populating bundled examples through port-forward")
 
-			successfulExamples, err := common.PopulateExample(url, opts.Parallel)
+			successfulExamples, err := common.PopulateExample(ctx, url, opts.Parallel, opts.Retry, report, opts.RemoteExamples)
+			for _, example := range successfulExamples {
+				allSuccessfulFiles = append(allSuccessfulFiles, common.IngestedFile{Path: example})
+			}
 			if err != nil {
-				return fmt.Errorf("error populating environment with examples through port-forward: %w", err)
+				err = fmt.Errorf("error populating environment with examples through port-forward: %w", err)
+				if !opts.KeepGoing || ctx.Err() != nil {
+					return err
+				}
+				errs = append(errs, err)
 			}
 
 			display.Debug("populated example files: %d", len(successfulExamples))
 		}
 
-		for _, p := range opts.TTLDirs {
-			display.Debug("processing metadata path: %s", p)
-
-			absPath, err := filepath.Abs(p)
-			if err != nil {
-				return fmt.Errorf("error finding absolute path for given metadata path '%s': %w", p, err)
+		for _, dataset := range datasets {
+			if dataset.Name != "" {
+				display.Step("Populating dataset %s", dataset.Name)
 			}
 
-			display.Debug("populating metadata from absolute path: %s", absPath)
+			for _, p := range dataset.Paths {
+				display.Debug("processing metadata path: %s", p)
 
-			successfulFiles, err := common.PopulateEnv(absPath, url, opts.Parallel)
-			if err != nil {
-				return fmt.Errorf("error populating environment through port-forward: %w", err)
+				absPath, err := filepath.Abs(p)
+				if err != nil {
+					return fmt.Errorf("error finding absolute path for given metadata path '%s': %w", p, err)
+				}
+
+				display.Debug("populating metadata from absolute path: %s", absPath)
+
+				successfulFiles, err := common.PopulateEnv(ctx, common.PopulateEnvOpts{
+					Path:        absPath,
+					EndpointURL: url,
+					Parallel:    opts.Parallel,
+					Ingested:    ingested,
+					Retry:       opts.Retry,
+					Report:      report,
+					Ingestion:   dataset.Ingestion,
+					Filter:      dataset.Filter(),
+					KeepGoing:   opts.KeepGoing,
+					Compress:    opts.Compress,
+				})
+				allSuccessfulFiles = append(allSuccessfulFiles, successfulFiles...)
+				if err != nil {
+					err = fmt.Errorf("error populating environment through port-forward: %w", err)
+					if dataset.Name != "" {
+						err = fmt.Errorf("error populating dataset %s: %w", dataset.Name, err)
+					}
+					if !opts.KeepGoing || ctx.Err() != nil {
+						return err
+					}
+					errs = append(errs, err)
+					continue
+				}
+
+				display.Debug("populated metadata files from path %s: %d", absPath, len(successfulFiles))
 			}
+		}
 
-			display.Debug("populated metadata files from path %s: %d", absPath, len(successfulFiles))
+		if len(errs) > 0 {
+			return fmt.Errorf("populate failed for %d path(s): %w", len(errs), errors.Join(errs...))
 		}
 
 		return nil
 	})
-	if err != nil {
-		return nil, fmt.Errorf("error populating environment: %w", err)
+
+	// the files ingested before a failure or an interrupt are recorded too, so that an incremental populate
+	// can pick up where this one stopped
+	if len(allSuccessfulFiles) > 0 {
+		if err := recordIngestedFiles(opts.Name, opts.Context, allSuccessfulFiles); err != nil {
+			return nil, err
+		}
+
+		display.Debug("recorded ingested files: %d", len(allSuccessfulFiles))
+	}
+
+	if opts.KeepGoing {
+		report.PrintFailures()
+	}
+
+	if populateErr != nil {
+		return nil, fmt.Errorf("error populating environment: %w", populateErr)
 	}
 
-	display.Done("Finished populating environment with ttl files from %d directories", len(opts.TTLDirs))
+	display.Done("Finished populating environment with ttl files from %d directories", len(paths))
 
 	return env, nil
 }
@@ -99,38 +232,93 @@ func (p *PopulateOpts) Validate() error {
 	display.Debug("ttlDirs: %+v", p.TTLDirs)
 	display.Debug("parallel: %d", p.Parallel)
 	display.Debug("populateExamples: %v", p.PopulateExamples)
+	display.Debug("remoteExamples: %v", p.RemoteExamples)
+	display.Debug("incremental: %v", p.Incremental)
+	display.Debug("dryRun: %v", p.DryRun)
+	display.Debug("retry: %+v", p.Retry)
+	display.Debug("reportFile: %s", p.ReportFile)
+	display.Debug("reportFormat: %s", p.ReportFormat)
+	display.Debug("ingestion: %+v", p.Ingestion)
+	display.Debug("filter: %s", p.Filter)
+	display.Debug("keepGoing: %v", p.KeepGoing)
+	display.Debug("manifest: %s", p.Manifest)
+	display.Debug("compress: %v", p.Compress)
 
 	if p.Parallel < 1 || p.Parallel > 20 {
 		return fmt.Errorf("parallel uploads must be between 1 and 20")
 	}
 
-	if p.Context == "" {
-		context, err := common.GetCurrentKubeContext()
+	if err := p.Retry.Validate(); err != nil {
+		return fmt.Errorf("invalid retry policy: %w", err)
+	}
+
+	if err := p.Ingestion.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Filter.Validate(); err != nil {
+		return err
+	}
+
+	if p.Manifest != "" {
+		manifest, err := common.LoadDatasetManifest(p.Manifest)
 		if err != nil {
-			return fmt.Errorf("failed to get current kubectl context: %w", err)
+			return err
 		}
 
-		p.Context = context
-	} else if err := EnsureContextExists(p.Context); err != nil {
-		return fmt.Errorf("K8s context %q is not an available context: %w", p.Context, err)
+		p.manifest = manifest
+		if manifest.Examples {
+			p.PopulateExamples = true
+		}
 	}
 
-	if err := EnsureEnvironmentExists(p.Name, p.Context); err != nil {
-		return fmt.Errorf("error validating environment name, no environment with '%s' exists: %w", p.Name, err)
+	if p.ReportFile != "" || p.ReportFormat != "" {
+		if p.ReportFile == "" {
+			return fmt.Errorf("a report file is required when a report format is set")
+		}
+		if _, err := common.ParseReportFormat(string(p.ReportFormat)); err != nil {
+			return err
+		}
+		if p.DryRun {
+			return fmt.Errorf("reports are not written for dry runs")
+		}
 	}
 
-	for _, item := range p.TTLDirs {
+	// a dry run never talks to the cluster
+	if !p.DryRun {
+		if p.Context == "" {
+			context, err := common.GetCurrentKubeContext()
+			if err != nil {
+				return fmt.Errorf("failed to get current kubectl context: %w", err)
+			}
+
+			p.Context = context
+		} else if err := EnsureContextExists(p.Context); err != nil {
+			return fmt.Errorf("K8s context %q is not an available context: %w", p.Context, err)
+		}
+
+		if err := EnsureEnvironmentExists(p.Name, p.Context); err != nil {
+			return fmt.Errorf("error validating environment name, no environment with '%s' exists: %w", p.Name, err)
+		}
+	}
+
+	for _, item := range common.DatasetPaths(p.datasets()) {
 		info, err := os.Stat(item)
 		if err != nil {
 			return fmt.Errorf("error stating path %q: %w", item, err)
 		}
 
-		if !info.IsDir() {
-			if filepath.Ext(item) != ".ttl" {
-				return fmt.Errorf("file %s is not a .ttl file", item)
+		if !info.IsDir() && !common.IsArchive(item) {
+			if _, err := common.DetectRDFFormat(item); err != nil {
+				return fmt.Errorf("file %s is not a supported RDF file: %w", item, err)
 			}
 		}
 	}
 
 	return nil
 }
+
+// datasets returns the datasets populated with opts: TTLDirs followed by the datasets of the manifest.
+func (p *PopulateOpts) datasets() []common.Dataset {
+	return common.PopulateDatasets(p.TTLDirs, p.Filter, p.Ingestion, p.manifest)
+}
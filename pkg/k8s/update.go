@@ -2,6 +2,7 @@
 package k8s
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"time"
@@ -68,10 +69,12 @@ func Update(opts UpdateOpts) (*Env, error) {
 		}); err != nil {
 			return nil, fmt.Errorf("failed to delete environment: %w", err)
 		}
-		env, err := Deploy(DeployOpts{
-			Context: opts.Context,
-			Timeout: opts.Timeout,
-			Config:  opts.NewConfig,
+		// like the docker update, a forced update does not seed the environment again
+		env, err := Deploy(context.Background(), DeployOpts{
+			Context:  opts.Context,
+			Timeout:  opts.Timeout,
+			Config:   opts.NewConfig,
+			SkipSeed: true,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to deploy new environment: %w", err)
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -76,9 +77,11 @@ type TaskOptions struct {
 	Operation    string
 	EnvName      string
 	IsDocker     bool
-	Task         func() (string, error) // Returns success message or error
+	Task         func(ctx context.Context) (string, error) // Returns success message or error
 	OnSuccess    func()
 	ClearDetails bool
+	// Cancellable lets the user cancel the context passed to Task with Esc while the task is running
+	Cancellable bool
 }
 
 // FormField represents a field in a modal form.
@@ -486,11 +489,16 @@ func (a *App) ShowConfirmation(opts ConfirmationOptions) {
 // It starts a new OperationProgress screen and executes the provided task in a goroutine.
 // Handles updating the UI upon completion (success or error).
 func (a *App) RunBackgroundTask(opts TaskOptions) {
+	ctx, cancel := context.WithCancel(context.Background())
 	progress := NewOperationProgress(a, opts.Operation, opts.EnvName)
+	if opts.Cancellable {
+		progress.cancel = cancel
+	}
 	progress.Start()
 
 	go func() {
-		msg, err := opts.Task()
+		defer cancel()
+		msg, err := opts.Task(ctx)
 		if err != nil {
 			progress.Complete(false, err.Error())
 		} else {
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
@@ -42,12 +43,12 @@ func (a *App) showCleanConfirm() {
 }
 
 // showCleanProgress displays the cleaning progress with live output.
-func (a *App) showCleanProgress(envName string, isDocker bool, context string) {
+func (a *App) showCleanProgress(envName string, isDocker bool, k8sContext string) {
 	a.RunBackgroundTask(TaskOptions{
 		Operation: "Clean",
 		EnvName:   envName,
 		IsDocker:  isDocker,
-		Task: func() (string, error) {
+		Task: func(_ context.Context) (string, error) {
 			if isDocker {
 				env, err := docker.Clean(docker.CleanOpts{
 					Name: envName,
@@ -65,7 +66,7 @@ func (a *App) showCleanProgress(envName string, isDocker bool, context string) {
 			} else {
 				env, err := k8s.Clean(k8s.CleanOpts{
 					Name:    envName,
-					Context: context,
+					Context: k8sContext,
 				})
 				if err != nil {
 					return "", err
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/k8s"
 )
@@ -46,12 +47,12 @@ func (a *App) showDeleteConfirm() {
 }
 
 // showDeleteProgress displays the deletion progress with live output.
-func (a *App) showDeleteProgress(envName string, isDocker bool, context string) {
+func (a *App) showDeleteProgress(envName string, isDocker bool, k8sContext string) {
 	a.RunBackgroundTask(TaskOptions{
 		Operation: "Delete",
 		EnvName:   envName,
 		IsDocker:  isDocker,
-		Task: func() (string, error) {
+		Task: func(_ context.Context) (string, error) {
 			if isDocker {
 				return "", docker.Delete(docker.DeleteOpts{
 					Name: []string{envName},
@@ -59,7 +60,7 @@ func (a *App) showDeleteProgress(envName string, isDocker bool, context string)
 			}
 			return "", k8s.Delete(k8s.DeleteOpts{
 				Name:    []string{envName},
-				Context: context,
+				Context: k8sContext,
 			})
 		},
 	})
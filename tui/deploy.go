@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -138,12 +139,12 @@ func (a *App) showDeployProgress(data *deployFormData, isDocker bool, dockerCfg
 		Operation: "Deploy",
 		EnvName:   data.name,
 		IsDocker:  isDocker,
-		Task: func() (string, error) {
+		Task: func(ctx context.Context) (string, error) {
 			var err error
 			var guiURL string
 
 			if isDocker {
-				env, derr := docker.Deploy(docker.DeployOpts{
+				env, derr := docker.Deploy(ctx, docker.DeployOpts{
 					PullImages: data.pullImages,
 					Config:     dockerCfg,
 				})
@@ -156,7 +157,7 @@ func (a *App) showDeployProgress(data *deployFormData, isDocker bool, dockerCfg
 					guiURL = urls.GUIURL
 				}
 			} else {
-				env, kerr := k8s.Deploy(k8s.DeployOpts{
+				env, kerr := k8s.Deploy(ctx, k8s.DeployOpts{
 					Context: data.context,
 					Config:  k8sCfg,
 				})
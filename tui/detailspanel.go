@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/EPOS-ERIC/epos-opensource/db"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/k8s"
@@ -39,6 +40,7 @@ type DetailsPanel struct {
 	detailsList           *tview.List
 	detailsListEmpty      *tview.TextView
 	detailsListFlex       *tview.Flex
+	servicesTable         *tview.Table
 	detailsEmpty          *tview.TextView
 	currentDetailsName    string
 	currentDetailsType    string
@@ -215,6 +217,8 @@ func (dp *DetailsPanel) buildUI() {
 	dp.detailsListFlex = tview.NewFlex().SetDirection(tview.FlexRow)
 	dp.detailsListFlex.AddItem(dp.detailsList, 0, 1, false)
 
+	dp.servicesTable = newServicesTable()
+
 	dp.detailsEmpty = NewStyledTextView()
 	dp.detailsEmpty.SetText(DefaultTheme.MutedTag("i") + "\nSelect an environment to view details")
 	dp.detailsEmpty.SetTextAlign(tview.AlignCenter)
@@ -353,12 +357,16 @@ func (dp *DetailsPanel) Update(name, envType, context string, focus bool) {
 		dp.details.AddItem(dp.buttonsFlex, 1, 0, true)
 		dp.details.AddItem(dp.nameDirGrid, nameDirGridSize, 0, false)
 		dp.details.AddItem(dp.detailsGrid, detailsGridSize, 0, false)
+		if envType == string(DockerKey) {
+			dp.details.AddItem(dp.servicesTable, 0, 1, false)
+		}
 		dp.details.AddItem(dp.detailsListFlex, 0, 1, false)
 		dp.detailsShown = true
 		updateBoxStyle(dp.details, true)
 	}
 
 	dp.RefreshFiles()
+	dp.refreshServices()
 
 	if focus {
 		dp.app.tview.SetFocus(dp.details)
@@ -390,39 +398,66 @@ func (dp *DetailsPanel) RefreshFiles() {
 	}
 }
 
-// populateIngestedFilesList populates the ingested files list.
+// populateIngestedFilesList populates the ingested files list. The files of K8s environments are shown from
+// the local cache first and refreshed from the cluster in the background.
 func (dp *DetailsPanel) populateIngestedFilesList() {
+	if dp.currentDetailsType != string(K8sKey) {
+		ingestedFiles, err := db.GetIngestedFilesByEnvironment(dp.currentDetailsName)
+		var paths []string
+		for _, file := range ingestedFiles {
+			paths = append(paths, file.FilePath)
+		}
+		dp.showIngestedFiles(paths, err)
+		return
+	}
+
+	name, k8sContext := dp.currentDetailsName, dp.currentDetailsContext
+	cached, err := k8s.GetCachedIngestedFiles(name, k8sContext)
+	dp.showIngestedFiles(k8sIngestedPaths(cached), err)
+
+	go func() {
+		files, err := k8s.GetIngestedFiles(name, k8sContext)
+		dp.app.tview.QueueUpdateDraw(func() {
+			if !dp.detailsShown || dp.currentDetailsName != name || dp.currentDetailsContext != k8sContext {
+				return
+			}
+			// keep showing the cached files when the cluster cannot be reached, they are the best information available
+			if err != nil && len(cached) > 0 {
+				return
+			}
+			dp.showIngestedFiles(k8sIngestedPaths(files), err)
+		})
+	}()
+}
+
+// showIngestedFiles fills the ingested files list with paths, or shows err when loading them failed.
+func (dp *DetailsPanel) showIngestedFiles(paths []string, err error) {
 	dp.detailsList.Clear()
 	dp.detailsListFlex.Clear()
 	dp.detailsList.SetSelectedFunc(nil)
 	dp.detailsList.SetTitle(" [::b]Ingested Files ")
 	dp.detailsListEmpty.SetTitle(" [::b]Ingested Files ")
-
-	if dp.currentDetailsType == string(K8sKey) {
-		dp.detailsListEmpty.SetText("\n" + DefaultTheme.MutedTag("i") + "Tracking is available only for Docker environments")
-		dp.detailsListFlex.AddItem(dp.detailsListEmpty, 0, 1, true)
-		dp.syncIngestedFilesFocus()
-
-		return
-	}
-
 	dp.detailsListEmpty.SetText("\n" + DefaultTheme.MutedTag("i") + "No ingested files yet")
 
-	if ingestedFiles, err := db.GetIngestedFilesByEnvironment(dp.currentDetailsName); err != nil {
+	if err != nil {
 		dp.detailsListEmpty.SetText("\n" + DefaultTheme.DestructiveTag("i") + fmt.Sprintf("Error loading files: %v", err))
 		dp.detailsListFlex.AddItem(dp.detailsListEmpty, 0, 1, true)
 	} else {
-		count := len(ingestedFiles)
+		count := len(paths)
 		if count > 0 {
 			dp.detailsList.SetTitle(fmt.Sprintf(" [::b]Ingested Files (%d) ", count))
-			for i, file := range ingestedFiles {
-				itemText := fmt.Sprintf("%d. %s", i+1, file.FilePath)
+			for i, path := range paths {
+				itemText := fmt.Sprintf("%d. %s", i+1, path)
 				dp.detailsList.AddItem(itemText, "", 0, nil)
 			}
 			dp.detailsList.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
 				parts := strings.SplitN(mainText, ". ", 2)
 				if len(parts) == 2 {
 					filepath := parts[1]
+					// entries of ingested archives are opened through their archive
+					if archivePath, _, ok := common.SplitArchiveEntryPath(filepath); ok {
+						filepath = archivePath
+					}
 					dp.openValue(filepath)
 				}
 			})
@@ -435,6 +470,15 @@ func (dp *DetailsPanel) populateIngestedFilesList() {
 	dp.syncIngestedFilesFocus()
 }
 
+// k8sIngestedPaths returns the paths of the ingested files of a K8s environment.
+func k8sIngestedPaths(files []k8s.IngestedFile) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
 func (dp *DetailsPanel) focusIngestedFiles() {
 	if dp.detailsListFlex.GetItemCount() > 0 {
 		target := dp.detailsListFlex.GetItem(0)
@@ -614,6 +658,21 @@ func (dp *DetailsPanel) SetupInput() {
 		case event.Rune() == 'p':
 			dp.app.showPopulateForm()
 			return nil
+		case event.Rune() == 's':
+			if dp.currentDetailsType == string(DockerKey) {
+				dp.app.showStartProgress()
+				return nil
+			}
+		case event.Rune() == 'x':
+			if dp.currentDetailsType == string(DockerKey) {
+				dp.app.showStopProgress()
+				return nil
+			}
+		case event.Rune() == 'l':
+			if dp.currentDetailsType == string(DockerKey) {
+				dp.app.showLogsViewer()
+				return nil
+			}
 		case event.Rune() == 'g':
 			if dp.detailsShown && len(dp.currentDetailsRows) > 0 {
 				dp.openValue(dp.currentDetailsRows[0].Value)
@@ -18,9 +18,10 @@ type k8sEnvRef struct {
 }
 
 type envListData struct {
-	dockerEnvs []string
-	k8sEnvs    []k8sEnvRef
-	dockerErr  error
+	dockerEnvs   []string
+	dockerStates map[string]docker.EnvState
+	k8sEnvs      []k8sEnvRef
+	dockerErr    error
 }
 
 // EnvList manages the left-side navigation for environment selection.
@@ -127,6 +128,13 @@ func (el *EnvList) loadData() envListData {
 		for _, env := range envs {
 			data.dockerEnvs = append(data.dockerEnvs, env.Name)
 		}
+
+		// without docker the states are unknown, and the environments are listed without them
+		states, err := docker.States()
+		if err != nil {
+			log.Printf("failed to get the state of docker environments: %v", err)
+		}
+		data.dockerStates = states
 	}
 
 	data.k8sEnvs = el.loadK8sEnvs()
@@ -212,7 +220,16 @@ func (el *EnvList) applyData(data envListData) {
 	} else {
 		el.dockerFlexInner.AddItem(el.docker, 0, 1, true)
 		for _, dockerEnvName := range el.dockerEnvs {
-			el.docker.AddItem("[::b] • "+dockerEnvName+"  ", "", 0, nil)
+			item := "[::b] • " + dockerEnvName + "  "
+			if data.dockerStates != nil {
+				if state := data.dockerStates[dockerEnvName]; state != docker.EnvRunning {
+					if state == "" {
+						state = docker.EnvStopped
+					}
+					item += DefaultTheme.MutedTag("") + "(" + string(state) + ")[-] "
+				}
+			}
+			el.docker.AddItem(item, "", 0, nil)
 		}
 
 		if selectedDocker != "" {
@@ -428,6 +445,16 @@ func (el *EnvList) setupRootInput(envsFlex *tview.Flex) {
 				el.app.showPopulateForm()
 				return nil
 			}
+		case event.Rune() == 's':
+			if el.IsDockerActive() && el.docker.GetItemCount() > 0 {
+				el.app.showStartProgress()
+				return nil
+			}
+		case event.Rune() == 'x':
+			if el.IsDockerActive() && el.docker.GetItemCount() > 0 {
+				el.app.showStopProgress()
+				return nil
+			}
 		}
 		return event
 	}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/gdamore/tcell/v2"
 	"github.com/ncruces/zenity"
 	"github.com/rivo/tview"
@@ -465,13 +466,22 @@ func resolveStartPath(startPath string) string {
 	return absPath
 }
 
+// rdfFilePatterns returns the glob patterns matching the RDF files and archives that can be populated.
+func rdfFilePatterns() []string {
+	var patterns []string
+	for _, ext := range append(common.RDFExtensions(), common.ArchiveExtensions()...) {
+		patterns = append(patterns, "*"+ext)
+	}
+	return patterns
+}
+
 // nativeSelectFiles uses zenity to select multiple files.
 func (a *App) nativeSelectFiles(onSelect func([]string)) {
 	go func() {
 		var opts []zenity.Option
 		opts = append(opts, zenity.Title("Select Files"))
 		opts = append(opts, zenity.FileFilters{
-			{Name: "TTL files", Patterns: []string{"*.ttl"}, CaseFold: false},
+			{Name: "RDF files and archives", Patterns: rdfFilePatterns(), CaseFold: true},
 		})
 
 		selected, err := zenity.SelectFileMultiple(opts...)
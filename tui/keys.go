@@ -20,6 +20,7 @@ const (
 	DeployFormKey    ScreenKey = "deploy-form"
 	UpdateFormKey    ScreenKey = "update-form"
 	RenderFormKey    ScreenKey = "render-form"
+	LogsKey          ScreenKey = "logs"
 )
 
 const (
@@ -35,6 +36,7 @@ const (
 	UpdateFooter     FooterText = "[Update Environment]"
 	NewFooter        FooterText = "[New Environment]"
 	RenderFooter     FooterText = "[Render Environment]"
+	LogsFooter       FooterText = "[Environment Logs]"
 )
 
 // getDetailsKey returns the appropriate details screen key based on environment type.
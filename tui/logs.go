@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	logsPageName = "logs"
+	// logsTail is the number of lines shown from the end of the logs of every service when the viewer opens
+	logsTail = "200"
+	// logsMaxLines is the number of lines kept in the viewer, older lines are dropped
+	logsMaxLines = 5000
+)
+
+// showLogsViewer opens a full-screen viewer streaming the logs of the services of the current Docker
+// environment. The filter field restarts the stream showing only the lines matching a regular expression.
+func (a *App) showLogsViewer() {
+	if a.detailsPanel.GetCurrentDetailsType() != string(DockerKey) {
+		a.FlashMessage("Logs are only available for Docker environments", 3*time.Second)
+		return
+	}
+
+	name := a.detailsPanel.GetCurrentDetailsName()
+	a.PushFocus()
+
+	logView := tview.NewTextView().
+		SetDynamicColors(false).
+		SetScrollable(true).
+		SetMaxLines(logsMaxLines).
+		SetChangedFunc(func() {
+			a.tview.Draw()
+		})
+	logView.SetBorder(true)
+	logView.SetTitle(fmt.Sprintf(" [::b]Logs: %s ", name))
+	logView.SetTitleColor(DefaultTheme.Secondary)
+	logView.SetBorderPadding(0, 0, 1, 1)
+
+	filter := NewStyledInputField("Filter (regex): ", "")
+
+	var cancel context.CancelFunc
+	stream := func(grep string) {
+		if cancel != nil {
+			cancel()
+		}
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+
+		logView.Clear()
+		logView.ScrollToEnd()
+
+		go func() {
+			err := docker.Logs(ctx, docker.LogsOpts{
+				Name:   name,
+				Follow: true,
+				Tail:   logsTail,
+				Grep:   grep,
+				Output: logView,
+			})
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(logView, "\nerror streaming logs: %v\n", err)
+			}
+		}()
+	}
+
+	closeViewer := func() {
+		cancel()
+		a.pages.RemovePage(logsPageName)
+		a.currentPage = homePageName
+		a.PopFocus()
+		a.UpdateFooter(DetailsDockerKey)
+	}
+
+	filter.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			stream(filter.GetText())
+			a.tview.SetFocus(logView)
+		case tcell.KeyEsc:
+			closeViewer()
+		case tcell.KeyTab, tcell.KeyBacktab:
+			a.tview.SetFocus(logView)
+		}
+	})
+
+	logView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc:
+			closeViewer()
+			return nil
+		case event.Key() == tcell.KeyTab, event.Key() == tcell.KeyBacktab, event.Rune() == '/':
+			a.tview.SetFocus(filter)
+			return nil
+		}
+		return event
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(filter, 1, 0, false).
+		AddItem(tview.NewBox(), 1, 0, false).
+		AddItem(logView, 0, 1, true)
+	layout.SetBorderPadding(1, 0, 1, 1)
+
+	a.pages.AddPage(logsPageName, layout, true, true)
+	a.currentPage = logsPageName
+	a.UpdateFooter(LogsKey)
+	a.tview.SetFocus(logView)
+
+	stream("")
+}
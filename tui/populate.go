@@ -1,9 +1,12 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/EPOS-ERIC/epos-opensource/common"
 	"github.com/EPOS-ERIC/epos-opensource/config"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
 	"github.com/EPOS-ERIC/epos-opensource/pkg/k8s"
@@ -24,10 +27,39 @@ const (
 type populateState struct {
 	paths       []string
 	examples    bool
+	ingestion   common.IngestionSettings
+	filter      common.PathFilter
+	keepGoing   bool
 	inputs      []*tview.InputField
 	focusButton FocusButton // "browse", "files", "dirs", or ""
 }
 
+// environmentModels returns the metadata models registered in an environment: the names of its ontologies of
+// type BASE. It falls back to common.IngestionModels when the ontologies of the environment cannot be loaded.
+func environmentModels(envName, k8sContext string, isDocker bool) []string {
+	var ontologies []common.Ontology
+	var err error
+	if isDocker {
+		ontologies, err = docker.ListOntologies(envName)
+	} else {
+		ontologies, err = k8s.ListOntologies(envName, k8sContext)
+	}
+	if err != nil {
+		return common.IngestionModels
+	}
+
+	var models []string
+	for _, ont := range ontologies {
+		if ont.Type == common.OntologyTypeBase {
+			models = append(models, ont.Name)
+		}
+	}
+	if len(models) == 0 {
+		return common.IngestionModels
+	}
+	return models
+}
+
 // showPopulateForm displays the dynamic populate form.
 func (a *App) showPopulateForm() {
 	a.PushFocus()
@@ -40,10 +72,13 @@ func (a *App) showPopulateForm() {
 	a.UpdateFooter(PopulateFormKey)
 
 	state := &populateState{
-		paths:    []string{""},
-		examples: false,
+		paths:     []string{""},
+		examples:  false,
+		ingestion: common.DefaultIngestionSettings(),
 	}
 
+	models := environmentModels(envName, k8sContext, isDocker)
+
 	formFlex := tview.NewFlex().SetDirection(tview.FlexRow)
 	formFlex.SetBorder(true).
 		SetBorderColor(DefaultTheme.Primary).
@@ -190,6 +225,62 @@ func (a *App) showPopulateForm() {
 			SetFieldTextColor(DefaultTheme.Secondary).
 			SetBorderPadding(0, 0, 1, 1)
 
+		keepGoingCheckbox := tview.NewCheckbox().
+			SetLabel("Keep Going ").
+			SetChecked(state.keepGoing).
+			SetChangedFunc(func(checked bool) {
+				state.keepGoing = checked
+			})
+		keepGoingCheckbox.SetLabelColor(DefaultTheme.Secondary).
+			SetFieldBackgroundColor(DefaultTheme.Surface).
+			SetFieldTextColor(DefaultTheme.Secondary).
+			SetBorderPadding(0, 0, 1, 1)
+
+		checkboxRow := tview.NewFlex().SetDirection(tview.FlexColumn).
+			AddItem(checkbox, 0, 1, false).
+			AddItem(keepGoingCheckbox, 0, 1, false)
+
+		modelIndex := slices.Index(models, state.ingestion.Model)
+		modelDropDown := tview.NewDropDown().
+			SetLabel("Model ").
+			SetOptions(models, func(option string, _ int) {
+				state.ingestion.Model = option
+			}).
+			SetCurrentOption(max(modelIndex, 0))
+		modelDropDown.SetLabelColor(DefaultTheme.Secondary).
+			SetBorderPadding(0, 0, 1, 1)
+		ApplyDropDownStyle(modelDropDown)
+
+		mappingInput := NewStyledInputField("Mapping ", state.ingestion.Mapping).
+			SetChangedFunc(func(text string) {
+				state.ingestion.Mapping = strings.TrimSpace(text)
+			})
+		mappingInput.SetBorderPadding(0, 0, 1, 1)
+
+		typeInput := NewStyledInputField("Type ", state.ingestion.Type).
+			SetChangedFunc(func(text string) {
+				state.ingestion.Type = strings.TrimSpace(text)
+			})
+		typeInput.SetBorderPadding(0, 0, 1, 1)
+
+		ingestionRow := tview.NewFlex().SetDirection(tview.FlexColumn).
+			AddItem(mappingInput, 0, 2, false).
+			AddItem(typeInput, 0, 1, false)
+
+		includeInput := NewStyledInputField("Include ", strings.Join(state.filter.Include, ", ")).
+			SetPlaceholder("e.g. stations/**/*.ttl").
+			SetChangedFunc(func(text string) {
+				state.filter.Include = splitPatterns(text)
+			})
+		includeInput.SetBorderPadding(0, 0, 1, 1)
+
+		excludeInput := NewStyledInputField("Exclude ", strings.Join(state.filter.Exclude, ", ")).
+			SetPlaceholder("e.g. drafts/, *.draft.ttl").
+			SetChangedFunc(func(text string) {
+				state.filter.Exclude = splitPatterns(text)
+			})
+		excludeInput.SetBorderPadding(0, 0, 1, 1)
+
 		populateBtn := NewStyledButton("Populate", func() {
 			a.handlePopulate(envName, k8sContext, state, isDocker)
 		})
@@ -220,7 +311,15 @@ func (a *App) showPopulateForm() {
 		controls := tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(controlsFlex, 1, 0, false).
 			AddItem(tview.NewBox(), 1, 0, false).
-			AddItem(checkbox, 1, 0, false).
+			AddItem(checkboxRow, 1, 0, false).
+			AddItem(tview.NewBox(), 1, 0, false).
+			AddItem(modelDropDown, 1, 0, false).
+			AddItem(tview.NewBox(), 1, 0, false).
+			AddItem(ingestionRow, 1, 0, false).
+			AddItem(tview.NewBox(), 1, 0, false).
+			AddItem(includeInput, 1, 0, false).
+			AddItem(tview.NewBox(), 1, 0, false).
+			AddItem(excludeInput, 1, 0, false).
 			AddItem(tview.NewBox(), 0, 1, false).
 			AddItem(tview.NewFlex().SetDirection(tview.FlexColumn).
 				AddItem(tview.NewBox(), 0, 1, false).
@@ -248,9 +347,9 @@ func (a *App) showPopulateForm() {
 		}
 
 		if a.config.TUI.FilePickerMode == config.FilePickerModeTUI {
-			allFocusable = append(allFocusable, browseBtn, addPathBtn, checkbox, populateBtn, cancelBtn)
+			allFocusable = append(allFocusable, browseBtn, addPathBtn, checkbox, keepGoingCheckbox, modelDropDown, mappingInput, typeInput, includeInput, excludeInput, populateBtn, cancelBtn)
 		} else {
-			allFocusable = append(allFocusable, browseDirsBtn, browseFilesBtn, addPathBtn, checkbox, populateBtn, cancelBtn)
+			allFocusable = append(allFocusable, browseDirsBtn, browseFilesBtn, addPathBtn, checkbox, keepGoingCheckbox, modelDropDown, mappingInput, typeInput, includeInput, excludeInput, populateBtn, cancelBtn)
 		}
 
 		formFlex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -298,7 +397,7 @@ func (a *App) showPopulateForm() {
 
 	rebuildUI()
 
-	a.pages.AddPage("populate", CenterPrimitiveFixed(formFlex, 65, 20), true, true)
+	a.pages.AddPage("populate", CenterPrimitiveFixed(formFlex, 65, 28), true, true)
 	a.currentPage = "populate"
 	if len(state.inputs) > 0 {
 		a.tview.SetFocus(state.inputs[0])
@@ -306,7 +405,7 @@ func (a *App) showPopulateForm() {
 }
 
 // handlePopulate validates the form and starts population.
-func (a *App) handlePopulate(envName, context string, state *populateState, isDocker bool) {
+func (a *App) handlePopulate(envName, k8sContext string, state *populateState, isDocker bool) {
 	var validPaths []string
 	for _, p := range state.paths {
 		if trimmed := strings.TrimSpace(p); trimmed != "" {
@@ -314,31 +413,40 @@ func (a *App) handlePopulate(envName, context string, state *populateState, isDo
 		}
 	}
 
-	a.showPopulateProgress(envName, context, validPaths, state.examples, isDocker)
+	a.showPopulateProgress(envName, k8sContext, validPaths, state.examples, state.ingestion, state.filter, state.keepGoing, isDocker)
 }
 
 // showPopulateProgress displays the populate progress with live output.
-func (a *App) showPopulateProgress(envName, context string, paths []string, examples, isDocker bool) {
+func (a *App) showPopulateProgress(envName, k8sContext string, paths []string, examples bool, ingestion common.IngestionSettings, filter common.PathFilter, keepGoing bool, isDocker bool) {
 	a.RunBackgroundTask(TaskOptions{
-		Operation: "Populate",
-		EnvName:   envName,
-		IsDocker:  isDocker,
-		Task: func() (string, error) {
+		Operation:   "Populate",
+		EnvName:     envName,
+		IsDocker:    isDocker,
+		Cancellable: true,
+		Task: func(ctx context.Context) (string, error) {
 			var err error
 			if isDocker {
-				_, err = docker.Populate(docker.PopulateOpts{
+				_, err = docker.Populate(ctx, docker.PopulateOpts{
 					Name:             envName,
 					TTLDirs:          paths,
 					PopulateExamples: examples,
 					Parallel:         1,
+					Retry:            common.DefaultRetryPolicy(),
+					Ingestion:        ingestion,
+					Filter:           filter,
+					KeepGoing:        keepGoing,
 				})
 			} else {
-				_, err = k8s.Populate(k8s.PopulateOpts{
+				_, err = k8s.Populate(ctx, k8s.PopulateOpts{
 					Name:             envName,
-					Context:          context,
+					Context:          k8sContext,
 					TTLDirs:          paths,
 					PopulateExamples: examples,
 					Parallel:         1,
+					Retry:            common.DefaultRetryPolicy(),
+					Ingestion:        ingestion,
+					Filter:           filter,
+					KeepGoing:        keepGoing,
 				})
 			}
 			return "", err
@@ -346,6 +454,17 @@ func (a *App) showPopulateProgress(envName, context string, paths []string, exam
 	})
 }
 
+// splitPatterns splits a comma separated list of glob patterns, dropping empty entries.
+func splitPatterns(text string) []string {
+	var patterns []string
+	for pattern := range strings.SplitSeq(text, ",") {
+		if trimmed := strings.TrimSpace(pattern); trimmed != "" {
+			patterns = append(patterns, trimmed)
+		}
+	}
+	return patterns
+}
+
 // appendUnique merges two slices, removing duplicates while preserving order.
 func appendUnique(existing, new []string) []string {
 	seen := make(map[string]bool)
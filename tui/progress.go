@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"time"
@@ -21,6 +22,8 @@ const (
 	opPopulate = "Populate"
 	opClean    = "Clean"
 	opUpdate   = "Update"
+	opStart    = "Start"
+	opStop     = "Stop"
 )
 
 var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
@@ -53,6 +56,10 @@ type OperationProgress struct {
 
 	ticker *time.Ticker
 	done   chan bool
+
+	// cancel aborts a cancellable operation, nil when the operation cannot be cancelled
+	cancel     context.CancelFunc
+	cancelling bool
 }
 
 // NewOperationProgress creates a new OperationProgress instance.
@@ -114,6 +121,9 @@ func (op *OperationProgress) rebuildUI() {
 func (op *OperationProgress) getProgressStatus() string {
 	switch op.state {
 	case StateRunning:
+		if op.cancelling {
+			return fmt.Sprintf("Cancelling %s...", op.operation)
+		}
 		return fmt.Sprintf("%s Operation in progress...", op.operation)
 	case StateSuccess:
 		return fmt.Sprintf("✓ %s Complete", op.operation)
@@ -126,8 +136,7 @@ func (op *OperationProgress) getProgressStatus() string {
 
 // Start begins the progress display and ticker.
 func (op *OperationProgress) Start() {
-	footerTitle := fmt.Sprintf("[%s Progress]", op.operation)
-	op.app.UpdateFooterCustom(footerTitle, []string{op.getProgressStatus()})
+	op.updateRunningFooter()
 
 	op.app.outputWriter.ClearBuffer()
 	op.app.outputWriter.SetView(op.app.tview, op.logsView)
@@ -152,6 +161,16 @@ func (op *OperationProgress) Start() {
 	op.app.currentPage = pageName
 }
 
+// updateRunningFooter shows the status of the running operation, and how to cancel it when possible.
+func (op *OperationProgress) updateRunningFooter() {
+	footerTitle := fmt.Sprintf("[%s Progress]", op.operation)
+	items := []string{op.getProgressStatus()}
+	if op.cancel != nil && !op.cancelling {
+		items = append(items, "Esc: cancel")
+	}
+	op.app.UpdateFooterCustom(footerTitle, items)
+}
+
 // updateHeader updates the header with current elapsed time.
 func (op *OperationProgress) updateHeader() {
 	elapsed := time.Since(op.startTime)
@@ -312,7 +331,7 @@ func (op *OperationProgress) returnToHome() {
 		RefreshFiles:   (op.operation == opPopulate || op.operation == opClean || op.operation == opUpdate) && op.state == StateSuccess,
 		RestoreFocus:   op.operation != opDeploy || op.state != StateSuccess,
 		ForceEnvFocus:  op.operation == opDeploy && op.state == StateSuccess,
-		SyncEnvRefresh: (op.operation == opDelete || op.operation == opDeploy) && op.state == StateSuccess,
+		SyncEnvRefresh: (op.operation == opDelete || op.operation == opDeploy || op.operation == opStart || op.operation == opStop) && op.state == StateSuccess,
 	})
 
 	// If we were in details and it wasn't a delete, we might need a full update
@@ -326,6 +345,11 @@ func (op *OperationProgress) returnToHome() {
 func (op *OperationProgress) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	if event.Key() == tcell.KeyEsc {
 		if op.state == StateRunning {
+			if op.cancel != nil && !op.cancelling {
+				op.cancelling = true
+				op.cancel()
+				op.updateRunningFooter()
+			}
 			return nil
 		}
 		op.returnToHome()
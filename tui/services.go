@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// servicesHeaders are the columns of the services table of Docker environments.
+var servicesHeaders = []string{"Service", "State", "Health", "Restarts", "Uptime", "Digest"}
+
+// newServicesTable creates the table showing the status of the services of a Docker environment.
+func newServicesTable() *tview.Table {
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetTitle(" [::b]Services ")
+	table.SetTitleColor(DefaultTheme.Secondary)
+	table.SetBorderPadding(1, 0, 1, 1)
+	table.SetSelectable(false, false)
+	return table
+}
+
+// refreshServices shows the status of the services of the current Docker environment. The status is loaded
+// in the background, since inspecting the containers can take a moment.
+func (dp *DetailsPanel) refreshServices() {
+	if dp.currentDetailsType != string(DockerKey) {
+		return
+	}
+
+	dp.showServicesMessage(DefaultTheme.MutedTag("i") + "Loading services...")
+
+	name := dp.currentDetailsName
+	go func() {
+		services, err := docker.Status(name)
+		dp.app.tview.QueueUpdateDraw(func() {
+			if !dp.detailsShown || dp.currentDetailsType != string(DockerKey) || dp.currentDetailsName != name {
+				return
+			}
+			dp.showServices(services, err)
+		})
+	}()
+}
+
+// showServicesMessage replaces the content of the services table with a single message.
+func (dp *DetailsPanel) showServicesMessage(message string) {
+	dp.servicesTable.Clear()
+	dp.servicesTable.SetTitle(" [::b]Services ")
+	dp.servicesTable.SetCell(0, 0, tview.NewTableCell(message).SetExpansion(1).SetAlign(tview.AlignCenter))
+}
+
+// showServices fills the services table with services, or shows err when loading them failed.
+func (dp *DetailsPanel) showServices(services []docker.ServiceStatus, err error) {
+	if err != nil {
+		dp.showServicesMessage(DefaultTheme.DestructiveTag("i") + fmt.Sprintf("Error loading services: %v", err))
+		return
+	}
+	if len(services) == 0 {
+		dp.showServicesMessage(DefaultTheme.MutedTag("i") + "No containers, the environment is stopped")
+		return
+	}
+
+	dp.servicesTable.Clear()
+	for col, header := range servicesHeaders {
+		dp.servicesTable.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(DefaultTheme.Secondary).
+			SetAttributes(tcell.AttrBold).
+			SetExpansion(1))
+	}
+
+	now := time.Now()
+	healthy := 0
+	for i, s := range services {
+		color := DefaultTheme.OnSurface
+		if s.Healthy() {
+			healthy++
+		} else {
+			color = DefaultTheme.Destructive
+		}
+
+		health := s.Health
+		if health == "" {
+			health = "-"
+		}
+		uptime := "-"
+		if d := s.Uptime(now); d > 0 {
+			uptime = d.String()
+		}
+
+		values := []string{s.Service, s.State, health, strconv.Itoa(s.RestartCount), uptime, s.ShortImageDigest()}
+		for col, value := range values {
+			dp.servicesTable.SetCell(i+1, col, tview.NewTableCell(tview.Escape(value)).
+				SetTextColor(color).
+				SetExpansion(1))
+		}
+	}
+
+	dp.servicesTable.SetTitle(fmt.Sprintf(" [::b]Services (%d/%d healthy) ", healthy, len(services)))
+}
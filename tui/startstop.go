@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"github.com/EPOS-ERIC/epos-opensource/pkg/docker"
+)
+
+// showStartProgress starts the selected Docker environment, displaying the progress with live output.
+func (a *App) showStartProgress() {
+	envName, isDocker, _ := a.envList.GetSelected()
+	if envName == "" {
+		return
+	}
+	if !isDocker {
+		a.FlashMessage("Start is only available for Docker environments", 2*time.Second)
+		return
+	}
+
+	a.RunBackgroundTask(TaskOptions{
+		Operation: opStart,
+		EnvName:   envName,
+		IsDocker:  true,
+		Task: func(_ context.Context) (string, error) {
+			return "", docker.Start(docker.StartOpts{
+				Name: []string{envName},
+			})
+		},
+	})
+}
+
+// showStopProgress stops the selected Docker environment, displaying the progress with live output.
+func (a *App) showStopProgress() {
+	envName, isDocker, _ := a.envList.GetSelected()
+	if envName == "" {
+		return
+	}
+	if !isDocker {
+		a.FlashMessage("Stop is only available for Docker environments", 2*time.Second)
+		return
+	}
+
+	a.RunBackgroundTask(TaskOptions{
+		Operation: opStop,
+		EnvName:   envName,
+		IsDocker:  true,
+		Task: func(_ context.Context) (string, error) {
+			return "", docker.Stop(docker.StopOpts{
+				Name: []string{envName},
+			})
+		},
+	})
+}
@@ -64,6 +64,7 @@ var FooterTexts = map[ScreenKey]FooterText{
 	UpdateFormKey:    UpdateFooter,
 	DeployFormKey:    NewFooter,
 	RenderFormKey:    RenderFooter,
+	LogsKey:          LogsFooter,
 }
 
 func GetFooterText(key ScreenKey) FooterText {
@@ -93,6 +94,8 @@ var KeyHints = map[ScreenKey][]KeyHint{
 		{"u: update", "u: update the selected docker environment", true, "Environment"},
 		{"r: render", "r: render files for the selected docker environment", true, "Environment"},
 		{"p: populate", "p: populate the selected docker environment", true, "Environment"},
+		{"s: start", "s: start the selected docker environment", true, "Environment"},
+		{"x: stop", "x: stop the selected docker environment, keeping its data", true, "Environment"},
 		{"enter: details", "enter: view details of the selected docker environment", true, "Navigation"},
 		{"?: help", "?: show help for current context", true, "Generic"},
 		{"q: quit", "q: quit the application", true, "Generic"},
@@ -118,6 +121,9 @@ var KeyHints = map[ScreenKey][]KeyHint{
 		{"u: update", "u: update this docker environment", true, "Environment"},
 		{"r: render", "r: render files for this docker environment", true, "Environment"},
 		{"p: populate", "p: populate this docker environment", true, "Environment"},
+		{"s: start", "s: start this docker environment", true, "Environment"},
+		{"x: stop", "x: stop this docker environment, keeping its data", true, "Environment"},
+		{"l: logs", "l: stream the logs of the services of this docker environment", true, "Environment"},
 		{"g: gui", "g: open gui in browser", true, "Browser"},
 		{"G: copy gui", "G: copy gui url to clipboard", false, "Browser"},
 		{"b: backoffice", "b: open backoffice in browser", true, "Browser"},
@@ -225,6 +231,12 @@ var KeyHints = map[ScreenKey][]KeyHint{
 	"deploy-complete": {
 		{"esc/enter: back", "", true, "Generic"},
 	},
+	"logs": {
+		{"↑↓: scroll", "↑↓: scroll through the logs", true, "Generic"},
+		{"tab: filter", "tab: switch between the logs and the filter", true, "Generic"},
+		{"enter: apply", "enter: restart the logs showing only the lines matching the filter", true, "Generic"},
+		{"esc: close", "esc: close the logs", true, "Generic"},
+	},
 	"help": {
 		{"↑↓: nav", "↑↓: navigate through help content", true, "Generic"},
 		{"esc: close", "esc: close the help screen", true, "Generic"},
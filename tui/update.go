@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -125,7 +126,7 @@ func (a *App) showUpdateProgress(data *updateFormData, isDocker bool, dockerCfg
 		Operation: "Update",
 		EnvName:   data.name,
 		IsDocker:  isDocker,
-		Task: func() (string, error) {
+		Task: func(_ context.Context) (string, error) {
 			var err error
 			if isDocker {
 				_, err = docker.Update(docker.UpdateOpts{